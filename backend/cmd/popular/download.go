@@ -0,0 +1,284 @@
+package main
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	downloadAttempts    = 5
+	downloadBaseBackoff = 2 * time.Second
+	downloadMaxBackoff  = time.Minute
+	downloadTimeout     = 30 * time.Minute
+)
+
+// downloadSleep waits out the backoff between download attempts; tests replace it.
+var downloadSleep = time.Sleep
+
+// errPermanent wraps download failures that retrying cannot fix, such as 404s.
+type errPermanent struct{ err error }
+
+func (e errPermanent) Error() string { return e.err.Error() }
+func (e errPermanent) Unwrap() error { return e.err }
+
+// downloadState tracks a partial download between attempts so it can resume with Range.
+type downloadState struct {
+	expected  int64  // total size from Content-Length/Content-Range, -1 when unknown
+	validator string // ETag or Last-Modified for If-Range
+	ranges    bool   // server advertised Accept-Ranges: bytes
+}
+
+// downloadDatasetFile fetches a dataset file into a temp file, retrying with exponential backoff.
+// Retries resume from the partial file when the server supports Range requests, and a size
+// mismatch against Content-Length discards the partial file and downloads from scratch.
+func downloadDatasetFile(file datasetFile, apiKey string) (string, error) {
+	if file.FileURL == "" {
+		return "", errors.New("missing file url")
+	}
+	tmp, err := os.CreateTemp("", "uspto-*.zip")
+	if err != nil {
+		return "", err
+	}
+	defer tmp.Close()
+
+	client := &http.Client{Timeout: downloadTimeout}
+	state := &downloadState{expected: -1}
+	logger := logrus.WithField("url", file.FileURL)
+	logger.Info("downloading dataset file")
+
+	var lastErr error
+	for attempt := 1; attempt <= downloadAttempts; attempt++ {
+		if attempt > 1 {
+			wait := backoff(attempt - 1)
+			logger.WithError(lastErr).WithFields(logrus.Fields{
+				"attempt": attempt,
+				"wait":    wait,
+			}).Warn("retrying dataset download")
+			downloadSleep(wait)
+		}
+
+		lastErr = downloadAttempt(client, file.FileURL, apiKey, tmp, state)
+		if lastErr == nil {
+			lastErr = verifyDownloadSize(tmp, state.expected)
+			if lastErr == nil {
+				break
+			}
+			// The partial file cannot be trusted; start the next attempt from scratch.
+			if err := resetDownload(tmp, state); err != nil {
+				os.Remove(tmp.Name())
+				return "", err
+			}
+		}
+		var permanent errPermanent
+		if errors.As(lastErr, &permanent) {
+			break
+		}
+	}
+	if lastErr == nil {
+		lastErr = verifyDatasetFile(tmp, file)
+	}
+	if lastErr != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("download %s: %w", file.FileURL, lastErr)
+	}
+
+	size := int64(0)
+	if info, err := tmp.Stat(); err == nil {
+		size = info.Size()
+	}
+	logrus.WithFields(logrus.Fields{
+		"file": tmp.Name(),
+		"size": size,
+	}).Info("dataset file downloaded")
+	return tmp.Name(), nil
+}
+
+// downloadAttempt issues one GET, resuming from the current end of dest when possible.
+func downloadAttempt(client *http.Client, fileURL, apiKey string, dest *os.File, state *downloadState) error {
+	offset, err := dest.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fileURL, nil)
+	if err != nil {
+		return errPermanent{err}
+	}
+	if apiKey != "" {
+		req.Header.Set("x-api-key", apiKey)
+	}
+	resuming := offset > 0 && state.ranges
+	if resuming {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if state.validator != "" {
+			req.Header.Set("If-Range", state.validator)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && resuming:
+		if total, ok := contentRangeTotal(resp.Header.Get("Content-Range")); ok {
+			state.expected = total
+		}
+		logrus.WithFields(logrus.Fields{"url": fileURL, "offset": offset}).Info("resuming dataset download")
+	case resp.StatusCode == http.StatusOK:
+		// Full body: either a fresh download or the server ignored/rejected the range.
+		if err := resetDownload(dest, state); err != nil {
+			return errPermanent{err}
+		}
+		state.expected = resp.ContentLength
+		state.ranges = strings.EqualFold(strings.TrimSpace(resp.Header.Get("Accept-Ranges")), "bytes")
+		state.validator = resp.Header.Get("ETag")
+		if state.validator == "" {
+			state.validator = resp.Header.Get("Last-Modified")
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && resuming:
+		if state.expected >= 0 && offset == state.expected {
+			return nil
+		}
+		state.ranges = false
+		return fmt.Errorf("range not satisfiable at offset %d", offset)
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		err := fmt.Errorf("download failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		if !retryableStatus(resp.StatusCode) {
+			return errPermanent{err}
+		}
+		return err
+	}
+
+	if _, err := io.Copy(dest, resp.Body); err != nil {
+		return err
+	}
+	return nil
+}
+
+// verifyDownloadSize compares the file size with the size the server reported, if any.
+func verifyDownloadSize(file *os.File, expected int64) error {
+	if expected < 0 {
+		return nil
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() != expected {
+		return fmt.Errorf("size mismatch: got %d bytes, expected %d", info.Size(), expected)
+	}
+	return nil
+}
+
+func resetDownload(file *os.File, state *downloadState) error {
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	state.expected = -1
+	state.ranges = false
+	state.validator = ""
+	return nil
+}
+
+// contentRangeTotal extracts the complete length from "bytes start-end/total".
+func contentRangeTotal(header string) (int64, bool) {
+	idx := strings.LastIndex(header, "/")
+	if idx < 0 {
+		return 0, false
+	}
+	total, err := strconv.ParseInt(strings.TrimSpace(header[idx+1:]), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return total, true
+}
+
+func retryableStatus(status int) bool {
+	return status == http.StatusRequestTimeout || status == http.StatusTooManyRequests || status >= 500
+}
+
+func backoff(retry int) time.Duration {
+	wait := downloadBaseBackoff << (retry - 1)
+	if wait > downloadMaxBackoff {
+		return downloadMaxBackoff
+	}
+	return wait
+}
+
+// verifyDatasetFile checks the download against the size and checksum published in the dataset
+// index. Files without that metadata are accepted as-is, but a published checksum that cannot be
+// verified, such as one using an unsupported algorithm, rejects the file.
+func verifyDatasetFile(file *os.File, meta datasetFile) error {
+	if meta.FileSize > 0 {
+		if err := verifyDownloadSize(file, meta.FileSize); err != nil {
+			return fmt.Errorf("%s: %w", meta.FileName, err)
+		}
+	}
+	checksum := strings.ToLower(strings.TrimSpace(meta.Checksum))
+	if checksum == "" {
+		return nil
+	}
+	algo, want := "", checksum
+	if idx := strings.Index(checksum, ":"); idx >= 0 {
+		algo, want = checksum[:idx], checksum[idx+1:]
+	}
+	h, err := checksumHash(algo, want)
+	if err != nil {
+		return fmt.Errorf("%s: %w", meta.FileName, err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.Copy(h, file); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		return fmt.Errorf("%s: checksum mismatch: got %s, expected %s", meta.FileName, got, want)
+	}
+	logrus.WithField("file", meta.FileName).Info("dataset file checksum verified")
+	return nil
+}
+
+// checksumHash picks the hash for an explicit algorithm prefix, or infers it from the digest length.
+func checksumHash(algo, digest string) (hash.Hash, error) {
+	if algo == "" {
+		switch len(digest) {
+		case md5.Size * 2:
+			algo = "md5"
+		case sha1.Size * 2:
+			algo = "sha1"
+		case sha256.Size * 2:
+			algo = "sha256"
+		}
+	}
+	switch strings.ReplaceAll(algo, "-", "") {
+	case "md5":
+		return md5.New(), nil
+	case "sha1":
+		return sha1.New(), nil
+	case "sha256":
+		return sha256.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported checksum %q", digest)
+	}
+}
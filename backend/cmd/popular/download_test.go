@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serveDataset answers each download request with the handler for its attempt, repeating the
+// last one, and records the requests' Range headers.
+func serveDataset(t *testing.T, handlers ...http.HandlerFunc) (*httptest.Server, *[]string) {
+	t.Helper()
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		handlers[min(len(ranges), len(handlers))-1](w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &ranges
+}
+
+// noSleep replaces the retry backoff for the test and records the waits.
+func noSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var waits []time.Duration
+	downloadSleep = func(d time.Duration) { waits = append(waits, d) }
+	t.Cleanup(func() { downloadSleep = time.Sleep })
+	return &waits
+}
+
+func TestDownloadDatasetFile(t *testing.T) {
+	const body = "0123456789abcdefghij"
+	// disconnect promises the whole body but sends only its first half.
+	disconnect := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Accept-Ranges", "bytes")
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+		w.Write([]byte(body[:10]))
+	}
+	full := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}
+	resume := func(total int) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-Range") != `"v1"` {
+				t.Errorf("expected If-Range with the ETag, got %q", r.Header.Get("If-Range"))
+			}
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes 10-%d/%d", len(body)-1, total))
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write([]byte(body[10:]))
+		}
+	}
+
+	tests := []struct {
+		name     string
+		handlers []http.HandlerFunc
+		ranges   []string
+	}{
+		{"resumes after a disconnect", []http.HandlerFunc{disconnect, resume(len(body))}, []string{"", "bytes=10-"}},
+		{"restarts when the range is ignored", []http.HandlerFunc{disconnect, full}, []string{"", "bytes=10-"}},
+		{"restarts after a size mismatch", []http.HandlerFunc{disconnect, resume(len(body) + 5), full}, []string{"", "bytes=10-", ""}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			waits := noSleep(t)
+			srv, ranges := serveDataset(t, tc.handlers...)
+			path, err := downloadDatasetFile(datasetFile{FileName: "data.zip", FileURL: srv.URL}, "")
+			if err != nil {
+				t.Fatalf("download: %v", err)
+			}
+			defer os.Remove(path)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if string(data) != body {
+				t.Fatalf("expected %q got %q", body, data)
+			}
+			if strings.Join(*ranges, ",") != strings.Join(tc.ranges, ",") {
+				t.Fatalf("expected Range headers %q got %q", tc.ranges, *ranges)
+			}
+			if len(*waits) != len(tc.ranges)-1 || (*waits)[0] != downloadBaseBackoff {
+				t.Fatalf("unexpected backoff waits %v", *waits)
+			}
+		})
+	}
+}
+
+func TestDownloadDatasetFileStopsOnPermanentError(t *testing.T) {
+	waits := noSleep(t)
+	srv, ranges := serveDataset(t, func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	_, err := downloadDatasetFile(datasetFile{FileURL: srv.URL}, "")
+	var permanent errPermanent
+	if !errors.As(err, &permanent) {
+		t.Fatalf("expected a permanent error, got %v", err)
+	}
+	if len(*ranges) != 1 || len(*waits) != 0 {
+		t.Fatalf("expected a single attempt, got %d requests and waits %v", len(*ranges), *waits)
+	}
+}
+
+func TestVerifyDatasetFile(t *testing.T) {
+	const body = "dataset"
+	const (
+		md5Sum    = "3c4d09e4ef50b370ae0efacdb43ec2dd"
+		sha1Sum   = "76f747de912e8682e29a23cb506dd5bf0de080d2"
+		sha256Sum = "b277fd623676a525c29b9eb155afc8c9010681814ceafb2d7627f47b9a232576"
+	)
+	file, err := os.CreateTemp(t.TempDir(), "data-*.zip")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(body); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		meta datasetFile
+		ok   bool
+	}{
+		{"no metadata", datasetFile{}, true},
+		{"size match", datasetFile{FileSize: int64(len(body))}, true},
+		{"size mismatch", datasetFile{FileSize: int64(len(body)) + 1}, false},
+		{"md5 by length", datasetFile{Checksum: md5Sum}, true},
+		{"sha1 by length", datasetFile{Checksum: strings.ToUpper(sha1Sum)}, true},
+		{"sha256 by length", datasetFile{Checksum: sha256Sum}, true},
+		{"sha256 prefix", datasetFile{Checksum: "sha256:" + sha256Sum}, true},
+		{"sha-256 prefix", datasetFile{Checksum: "SHA-256:" + sha256Sum}, true},
+		{"mismatch", datasetFile{Checksum: "sha256:" + strings.Repeat("0", 64)}, false},
+		{"unsupported algorithm", datasetFile{Checksum: "crc32:1a2b3c4d"}, false},
+		{"unrecognized length", datasetFile{Checksum: "abc123"}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.meta.FileName = "data.zip"
+			if err := verifyDatasetFile(file, tc.meta); (err == nil) != tc.ok {
+				t.Fatalf("expected ok %v, got %v", tc.ok, err)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"domain-risk-eval/backend/internal/match"
 	"domain-risk-eval/backend/internal/scoring"
 	"domain-risk-eval/backend/internal/store"
 	xmlparser "domain-risk-eval/backend/internal/xml"
@@ -39,14 +40,18 @@ func main() {
 		datasetKey  = flag.String("dataset-key", "", "USPTO dataset API key (env USPTO_DATASET_KEY)")
 		fromDate    = flag.String("from", "", "Dataset start date YYYY-MM-DD")
 		toDate      = flag.String("to", "", "Dataset end date YYYY-MM-DD")
+		minLength   = flag.Int("fanciful-min-length", 6, "Minimum normalized mark length for the fanciful heuristic")
+		minClasses  = flag.Int("fanciful-min-classes", 2, "Minimum class count for the fanciful heuristic")
 	)
 	flag.Var(&xmlPaths, "xml", "USPTO bulk XML or ZIP file (repeatable)")
 	flag.Var(&xmlDirPaths, "xml-dir", "Directory containing USPTO ZIP files (repeatable)")
 	flag.Parse()
 
 	loadEnvDefaults(datasetURL, datasetKey, fromDate, toDate)
+	// Marks are normalized once at ingest, so fold them the same way the server does.
+	match.SetUnicodeFolding(strings.EqualFold(strings.TrimSpace(os.Getenv("UNICODE_FOLDING")), "true"))
 
-	db, err := store.Open(*dbPath, true)
+	db, err := store.Open(*dbPath, true, store.Config{})
 	if err != nil {
 		logrus.Fatalf("open database: %v", err)
 	}
@@ -122,7 +127,7 @@ func main() {
 	}
 
 	if !*refreshOnly && len(downloadList) > 0 {
-		decider, err := scoring.NewFancifulDecider(*seedPath)
+		decider, err := scoring.NewFancifulDecider(*seedPath, &scoring.FancifulThresholds{MinLength: *minLength, MinClasses: *minClasses})
 		if err != nil {
 			logrus.Fatalf("fanciful decider: %v", err)
 		}
@@ -260,54 +265,6 @@ func fetchDatasetIndex(baseURL, apiKey, fromDate, toDate string) ([]datasetFile,
 	return files, nil
 }
 
-func downloadDatasetFile(file datasetFile, apiKey string) (string, error) {
-	if file.FileURL == "" {
-		return "", errors.New("missing file url")
-	}
-	req, err := http.NewRequest(http.MethodGet, file.FileURL, nil)
-	if err != nil {
-		return "", err
-	}
-	if apiKey != "" {
-		req.Header.Set("x-api-key", apiKey)
-	}
-	client := &http.Client{Timeout: 5 * time.Minute}
-
-	logrus.WithField("url", file.FileURL).Info("downloading dataset file")
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return "", fmt.Errorf("download failed: %s", strings.TrimSpace(string(body)))
-	}
-
-	tmp, err := os.CreateTemp("", "uspto-*.zip")
-	if err != nil {
-		return "", err
-	}
-	defer tmp.Close()
-
-	if _, err := io.Copy(tmp, resp.Body); err != nil {
-		os.Remove(tmp.Name())
-		return "", err
-	}
-
-	fileInfo, statErr := tmp.Stat()
-	size := int64(0)
-	if statErr == nil {
-		size = fileInfo.Size()
-	}
-	logrus.WithFields(logrus.Fields{
-		"file": tmp.Name(),
-		"size": size,
-	}).Info("dataset file downloaded")
-	return tmp.Name(), nil
-}
-
 func writeTokens(path string, tokens []string) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		if !os.IsExist(err) {
@@ -340,9 +297,13 @@ func (m *multiFlag) Set(value string) error {
 	return nil
 }
 
+// datasetFile describes one downloadable dataset file. FileSize and Checksum are optional
+// metadata used to verify the download; Checksum may be bare hex or prefixed "sha256:".
 type datasetFile struct {
 	FileName string `json:"fileName"`
 	FileURL  string `json:"fileUrl"`
+	FileSize int64  `json:"fileSize"`
+	Checksum string `json:"fileChecksum"`
 }
 
 type datasetResponse struct {
@@ -354,6 +315,8 @@ type datasetResponse struct {
 		Docs []struct {
 			FileName string `json:"fileName"`
 			FileURL  string `json:"fileLocation"`
+			FileSize int64  `json:"fileSize"`
+			Checksum string `json:"fileChecksum"`
 		} `json:"docs"`
 	} `json:"response"`
 }
@@ -377,7 +340,7 @@ func (r datasetResponse) Files() []datasetFile {
 		appendFile(f)
 	}
 	for _, doc := range r.Response.Docs {
-		appendFile(datasetFile{FileName: doc.FileName, FileURL: doc.FileURL})
+		appendFile(datasetFile{FileName: doc.FileName, FileURL: doc.FileURL, FileSize: doc.FileSize, Checksum: doc.Checksum})
 	}
 	return files
 }
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"domain-risk-eval/backend/internal/ai"
+	"domain-risk-eval/backend/internal/api"
+	"domain-risk-eval/backend/internal/match"
+	"domain-risk-eval/backend/internal/scoring"
+	"domain-risk-eval/backend/internal/usp"
+)
+
+// loadConfig builds the server configuration from the environment. Data files default to paths
+// relative to baseDir, the working directory, and each can be overridden by its own variable.
+func loadConfig(baseDir string) (api.Config, error) {
+	dataDir := envString("DATA_DIR", filepath.Join(baseDir, "data"))
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		return api.Config{}, fmt.Errorf("create data directory: %w", err)
+	}
+
+	allowlistPath := filepath.Join(baseDir, "internal", "scoring", "vice_allowlist.json")
+	if envAllowlist, ok := os.LookupEnv("VICE_ALLOWLIST_PATH"); ok {
+		allowlistPath = strings.TrimSpace(envAllowlist)
+	}
+
+	cfg := api.Config{
+		DBPath:             envString("DOMAIN_RISK_DB_PATH", filepath.Join(dataDir, "domain-risk.db")),
+		SeedsPath:          envString("FANCIFUL_SEEDS_PATH", filepath.Join(baseDir, "internal", "scoring", "fanciful_seed.json")),
+		ViceTermsPath:      envString("VICE_TERMS_PATH", filepath.Join(baseDir, "internal", "scoring", "vice_terms.json")),
+		ViceAllowlistPath:  allowlistPath,
+		DefaultXMLPath:     envString("DEFAULT_XML_PATH", filepath.Join(baseDir, "..", "apc250917.xml")),
+		DefaultDomainsPath: envString("DEFAULT_DOMAINS_PATH", filepath.Join(baseDir, "..", "Test domains.csv")),
+		CommercialSales:    envString("COMMERCIAL_SALES_PATH", filepath.Join(baseDir, "bquxjob_40fe6a70_1995182bb6e.csv")),
+		AllowedOrigins: []string{
+			"http://localhost:1000",
+			"http://127.0.0.1:1000",
+			"https://domain-risk-frontend.onrender.com",
+		},
+		AIConfig:        loadAIConfig(),
+		USPTOConfig:     loadUSPTOConfig(),
+		DisableAI:       envFlag("DISABLE_AI"),
+		FakeAI:          envFlag("AI_FAKE"),
+		PopularLimit:    envInt("POPULAR_MARK_LIMIT", 200000, 1),
+		PopularMinCount: envInt("POPULAR_MARK_MIN_COUNT", 2, 1),
+		MarksLimit:      envInt("MARKS_LIMIT", 200000, 1),
+		AIBatchSize:     envInt("AI_BATCH_SIZE", 0, 1),
+	}
+	cfg.MarksDBPath = envString("MARKS_DB_PATH", "")
+	cfg.MarksDBWritable = envFlag("MARKS_DB_WRITABLE")
+	cfg.AdminToken = os.Getenv("ADMIN_TOKEN")
+
+	cfg.TrademarkScoresPath = envString("TRADEMARK_SCORES_PATH", "")
+	cfg.CommonWordsPath = envString("COMMON_WORDS_PATH", "")
+	cfg.ExtraCommonWords = envList("COMMON_WORDS_EXTRA", nil)
+	cfg.ReviewTrademarkConflicts = envFlag("TRADEMARK_CONFLICT_REVIEW")
+	cfg.HighValueOwners = envList("HIGH_VALUE_OWNERS", nil)
+	cfg.PreloadMarks = envFlag("PRELOAD_MARKS")
+	cfg.LiveMarksOnly = envFlag("LIVE_MARKS_ONLY")
+	cfg.RefreshInterval = envDuration("DATASET_REFRESH_INTERVAL", 0)
+	cfg.MaxNarrativeLength = envInt("NARRATIVE_MAX_LENGTH", 0, -1)
+	cfg.AIGuardrailScore = envInt("AI_GUARDRAIL_SCORE", 0, -1)
+	cfg.UnicodeFolding = envFlag("UNICODE_FOLDING")
+	cfg.SubdomainSignals = envFlag("SUBDOMAIN_SIGNALS")
+	cfg.EmbeddedTrademarks = envFlag("EMBEDDED_TRADEMARKS")
+	cfg.StoreTimings = envFlag("STORE_EVALUATION_TIMINGS")
+	cfg.SimilarMarkReview = envFlag("USPTO_SIMILAR_REVIEW")
+	cfg.SimilarMarkThreshold = envFloat("USPTO_SIMILAR_THRESHOLD", 0, 0)
+	cfg.SimilarOnlyScore = envInt("USPTO_SIMILAR_ONLY_SCORE", 0, 0)
+	cfg.SoftenLowConfidence = envFlag("LOW_CONFIDENCE_SOFTEN")
+	cfg.LowConfidenceThreshold = envFloat("LOW_CONFIDENCE_THRESHOLD", 0, 0)
+	cfg.TLDRiskPath = envString("TLD_RISK_PATH", "")
+	cfg.ReviewRandomDomains = envFlag("RANDOM_DOMAIN_REVIEW")
+	cfg.ReviewSpamDomains = envFlag("SPAM_PATTERN_REVIEW")
+	if hyphens, ratio, tokens := envString("SPAM_MIN_HYPHENS", ""), envString("SPAM_MIN_DIGIT_RATIO", ""), envString("SPAM_MIN_TOKENS", ""); hyphens != "" || ratio != "" || tokens != "" {
+		thresholds := match.DefaultSpamThresholds()
+		thresholds.MinHyphens = envInt("SPAM_MIN_HYPHENS", thresholds.MinHyphens, 0)
+		thresholds.MinDigitRatio = envFloat("SPAM_MIN_DIGIT_RATIO", thresholds.MinDigitRatio, 0)
+		thresholds.MinTokens = envInt("SPAM_MIN_TOKENS", thresholds.MinTokens, 0)
+		cfg.SpamThresholds = &thresholds
+	}
+	if minLength, minClasses := envString("FANCIFUL_MIN_LENGTH", ""), envString("FANCIFUL_MIN_CLASSES", ""); minLength != "" || minClasses != "" {
+		thresholds := scoring.DefaultFancifulThresholds()
+		thresholds.MinLength = envInt("FANCIFUL_MIN_LENGTH", thresholds.MinLength, 0)
+		thresholds.MinClasses = envInt("FANCIFUL_MIN_CLASSES", thresholds.MinClasses, 0)
+		cfg.FancifulThresholds = &thresholds
+	}
+
+	cfg.CommercialPolicyPath = envString("COMMERCIAL_POLICY_PATH", "")
+	cfg.CommercialSimilarity = envString("COMMERCIAL_SIMILARITY", "")
+	if parsed, err := strconv.ParseFloat(envString("COMMERCIAL_MIN_PRICE", ""), 64); err == nil && parsed >= 0 {
+		cfg.CommercialMinPrice = &parsed
+	}
+	cfg.CommercialCandidateLimit = envInt("COMMERCIAL_CANDIDATE_LIMIT", 0, 1)
+	if window := envInt("COMMERCIAL_LENGTH_WINDOW", -1, 0); window >= 0 {
+		cfg.CommercialLengthWindow = &window
+	}
+	cfg.CommercialEarlyExit = envFloat("COMMERCIAL_EARLY_EXIT", 0, 0)
+
+	cfg.AllowedHeaders = envList("CORS_ALLOWED_HEADERS", nil)
+	cfg.AllowedMethods = envList("CORS_ALLOWED_METHODS", strings.ToUpper)
+	cfg.CORSMaxAge = envDuration("CORS_MAX_AGE", 0)
+	cfg.UploadRateLimit = envFloat("UPLOAD_RATE_LIMIT", 0, 0)
+	cfg.EvaluateRateLimit = envFloat("EVALUATE_RATE_LIMIT", 0, 0)
+	cfg.TrustedProxies = envList("TRUSTED_PROXIES", nil)
+	cfg.RateLimitBurst = envInt("RATE_LIMIT_BURST", 0, 1)
+	cfg.AIMaxConcurrency = envInt("AI_MAX_CONCURRENCY", 0, 0)
+	cfg.PrewarmMaxDomains = envInt("PREWARM_MAX_DOMAINS", 100000, 0)
+	cfg.MaxUploadBytes = int64(envInt("UPLOAD_MAX_BYTES", 0, 1))
+	cfg.MaxUploadRows = envInt("UPLOAD_MAX_ROWS", 0, 1)
+	cfg.UploadDedupe = envFlag("UPLOAD_DEDUPE")
+	cfg.CSVComment = envString("CSV_COMMENT_CHAR", "")
+
+	pageLimits := []struct {
+		env    string
+		limits *api.PageLimits
+	}{
+		{"RESULTS_PAGE_SIZE", &cfg.ResultsPage},
+		{"BATCHES_PAGE_SIZE", &cfg.BatchesPage},
+		{"MARKS_PAGE_SIZE", &cfg.MarksPage},
+		{"EVALUATE_LIMIT", &cfg.EvaluateLimit},
+	}
+	for _, p := range pageLimits {
+		p.limits.Default = envInt(p.env, 0, 1)
+		p.limits.Max = envInt(p.env+"_MAX", 0, 1)
+	}
+
+	cfg.StoreConfig.BusyTimeout = envDuration("DB_BUSY_TIMEOUT", 0)
+	cfg.StoreConfig.MaxOpenConns = envInt("DB_MAX_OPEN_CONNS", 0, 1)
+	cfg.StoreConfig.MaxIdleConns = envInt("DB_MAX_IDLE_CONNS", 0, 1)
+
+	checkDataFiles(&cfg)
+	return cfg, nil
+}
+
+// loadTLSFiles returns the certificate and key paths from TLS_CERT_FILE and TLS_KEY_FILE, or two
+// empty strings to serve plain HTTP. Setting only one of them, or a path that does not exist, is
+// an error rather than a silent fallback to HTTP.
+func loadTLSFiles() (string, string, error) {
+	certFile, keyFile := envString("TLS_CERT_FILE", ""), envString("TLS_KEY_FILE", "")
+	if certFile == "" && keyFile == "" {
+		return "", "", nil
+	}
+	if certFile == "" || keyFile == "" {
+		return "", "", errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	for _, path := range []string{certFile, keyFile} {
+		if _, err := os.Stat(path); err != nil {
+			return "", "", err
+		}
+	}
+	return certFile, keyFile, nil
+}
+
+func loadAIConfig() ai.Config {
+	cfg := ai.Config{
+		APIKey:              os.Getenv("OPENAI_API_KEY"),
+		Model:               os.Getenv("OPENAI_MODEL"),
+		BaseURL:             os.Getenv("OPENAI_BASE_URL"),
+		MaxTokens:           envInt("OPENAI_MAX_TOKENS", 0, 1),
+		TopP:                envFloat("OPENAI_TOP_P", 0, 0),
+		Timeout:             envDuration("OPENAI_TIMEOUT", 0),
+		MaxIdleConnsPerHost: envInt("HTTP_MAX_IDLE_CONNS_PER_HOST", 0, 1),
+		PromptTemplatePath:  envString("OPENAI_PROMPT_TEMPLATE", ""),
+		JSONMode:            envFlag("OPENAI_JSON_MODE"),
+		Language:            envString("NARRATIVE_LANGUAGE", ""),
+	}
+	// Unset keeps the client's default temperature; an explicit 0 is honoured.
+	if temp, err := strconv.ParseFloat(envString("OPENAI_TEMPERATURE", ""), 64); err == nil && temp >= 0 {
+		cfg.Temperature = &temp
+	}
+	if seed, err := strconv.ParseInt(envString("OPENAI_SEED", ""), 10, 64); err == nil {
+		cfg.Seed = seed
+	}
+	return cfg
+}
+
+func loadUSPTOConfig() usp.Config {
+	return usp.Config{
+		Timeout:             envDuration("USPTO_TIMEOUT", 0),
+		CacheTTL:            envDuration("USPTO_CACHE_TTL", 0),
+		Rows:                envInt("USPTO_ROWS", 0, 1),
+		MaxIdleConnsPerHost: envInt("HTTP_MAX_IDLE_CONNS_PER_HOST", 0, 1),
+	}
+}
+
+// checkDataFiles warns about each optional data file that does not exist and drops it so startup
+// carries on without it. The seed and vice term files are not checked: the scoring package falls
+// back to its embedded defaults and warns itself.
+func checkDataFiles(cfg *api.Config) {
+	files := []struct {
+		env     string
+		path    *string
+		without string
+	}{
+		{"DEFAULT_XML_PATH", &cfg.DefaultXMLPath, "no default trademark XML"},
+		{"DEFAULT_DOMAINS_PATH", &cfg.DefaultDomainsPath, "no default domains CSV"},
+		{"COMMERCIAL_SALES_PATH", &cfg.CommercialSales, "commercial sales overrides disabled"},
+		{"VICE_ALLOWLIST_PATH", &cfg.ViceAllowlistPath, "vice allowlist disabled"},
+	}
+	for _, file := range files {
+		if *file.path == "" {
+			continue
+		}
+		info, err := os.Stat(*file.path)
+		if err == nil && !info.IsDir() {
+			continue
+		}
+		logger := logrus.WithFields(logrus.Fields{"path": *file.path, "env": file.env})
+		if err == nil {
+			err = errors.New("is a directory")
+		}
+		logger.WithError(err).Warnf("data file unavailable; %s", file.without)
+		*file.path = ""
+	}
+}
+
+// envString returns the trimmed value of name, or fallback when it is unset or blank.
+func envString(name, fallback string) string {
+	if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// envFlag reports whether name is set to "true", ignoring case.
+func envFlag(name string) bool {
+	return strings.EqualFold(strings.TrimSpace(os.Getenv(name)), "true")
+}
+
+// envInt parses name as an integer, returning fallback when it is unset, malformed, or below min.
+func envInt(name string, fallback, min int) int {
+	parsed, err := strconv.Atoi(strings.TrimSpace(os.Getenv(name)))
+	if err != nil || parsed < min {
+		return fallback
+	}
+	return parsed
+}
+
+// envFloat parses name as a float, returning fallback when it is unset, malformed, or below min.
+func envFloat(name string, fallback, min float64) float64 {
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(os.Getenv(name)), 64)
+	if err != nil || parsed < min {
+		return fallback
+	}
+	return parsed
+}
+
+// envDuration parses name as a positive duration such as "30s", returning fallback otherwise.
+func envDuration(name string, fallback time.Duration) time.Duration {
+	parsed, err := time.ParseDuration(strings.TrimSpace(os.Getenv(name)))
+	if err != nil || parsed <= 0 {
+		return fallback
+	}
+	return parsed
+}
+
+// envList splits name on commas, dropping blank entries and applying transform, when set, to
+// each trimmed entry.
+func envList(name string, transform func(string) string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(name), ",") {
+		item = strings.TrimSpace(item)
+		if transform != nil {
+			item = transform(item)
+		}
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,112 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// BatchExplainer is implemented by explainers that can score several domains in one request.
+// Decisions are returned in input order; callers fall back to Explain when a batch fails.
+type BatchExplainer interface {
+	Explainer
+	ExplainBatch(ctx context.Context, inputs []ExplanationInput) ([]Decision, error)
+}
+
+// batchInstruction extends the single-domain contract so the model returns one decision per case.
+const batchInstruction = " When several cases are supplied, apply these rules to each case independently and reply with a JSON object {\"decisions\": [...]} holding one decision object per case, in the order given, each with an integer case field matching its case number."
+
+type batchResponse struct {
+	Decisions []batchDecision `json:"decisions"`
+}
+
+type batchDecision struct {
+	Case int `json:"case"`
+	Decision
+}
+
+// ExplainBatch requests decisions for several domains in a single round trip.
+func (c *Client) ExplainBatch(ctx context.Context, inputs []ExplanationInput) ([]Decision, error) {
+	if c == nil || !c.Enabled() {
+		return nil, ErrDisabled
+	}
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+
+	system, _, err := c.prompts.render(inputs[0], c.systemPrompt(), c.buildUserPrompt(inputs[0]))
+	if err != nil {
+		return nil, err
+	}
+	user, err := c.buildBatchPrompt(inputs)
+	if err != nil {
+		return nil, err
+	}
+	payload := c.payloadFor(system+batchInstruction, user)
+	if c.maxTokens > 0 {
+		payload["max_tokens"] = c.maxTokens * len(inputs)
+	}
+	content, err := c.complete(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded batchResponse
+	if err := json.Unmarshal([]byte(content), &decoded); err != nil {
+		return nil, fmt.Errorf("parse ai batch response: %w", err)
+	}
+	if len(decoded.Decisions) != len(inputs) {
+		return nil, fmt.Errorf("ai batch returned %d decisions for %d cases", len(decoded.Decisions), len(inputs))
+	}
+
+	decisions := make([]Decision, len(inputs))
+	filled := make([]bool, len(inputs))
+	for i, item := range decoded.Decisions {
+		idx := i
+		if item.Case >= 1 && item.Case <= len(inputs) {
+			idx = item.Case - 1
+		}
+		if filled[idx] {
+			return nil, fmt.Errorf("ai batch repeated case %d", idx+1)
+		}
+		decision := item.Decision
+		if err := validateDecision(&decision); err != nil {
+			return nil, fmt.Errorf("case %d: %w", idx+1, err)
+		}
+		decisions[idx] = decision
+		filled[idx] = true
+	}
+	return decisions, nil
+}
+
+func (c *Client) buildBatchPrompt(inputs []ExplanationInput) (string, error) {
+	builder := &strings.Builder{}
+	fmt.Fprintf(builder, "Evaluate the following %d cases independently.\n", len(inputs))
+	for i, input := range inputs {
+		_, user, err := c.prompts.render(input, c.systemPrompt(), c.buildUserPrompt(input))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(builder, "\n### Case %d\n", i+1)
+		builder.WriteString(user)
+		builder.WriteString("\n")
+	}
+	builder.WriteString("\nReturn {\"decisions\": [...]} with exactly one decision per case, in case order.\n")
+	return builder.String(), nil
+}
+
+// ExplainBatch delegates to the primary explainer when it supports batching. Failures are
+// returned to the caller, whose per-domain fallback goes through Explain and therefore still
+// reaches the fallback explainer.
+func (c *explainerChain) ExplainBatch(ctx context.Context, inputs []ExplanationInput) ([]Decision, error) {
+	if c == nil {
+		return nil, ErrDisabled
+	}
+	batcher, ok := c.primary.(BatchExplainer)
+	if !ok || !c.primary.Enabled() {
+		return nil, errors.New("primary explainer does not support batching")
+	}
+	return batcher.ExplainBatch(ctx, inputs)
+}
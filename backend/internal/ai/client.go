@@ -11,7 +11,11 @@ import (
 	"strings"
 	"time"
 
+	"golang.org/x/text/language"
+
+	"domain-risk-eval/backend/internal/match"
 	"domain-risk-eval/backend/internal/scoring"
+	"domain-risk-eval/backend/internal/util"
 )
 
 // Explainer exposes AI-backed explanations for evaluation results.
@@ -22,11 +26,30 @@ type Explainer interface {
 
 // Config holds OpenAI configuration parameters.
 type Config struct {
-	APIKey      string
-	Model       string
-	BaseURL     string
-	Temperature float64
+	APIKey  string
+	Model   string
+	BaseURL string
+	// Temperature defaults to 0.2 when nil; an explicit value, including 0, is sent as-is.
+	Temperature *float64
 	MaxTokens   int
+	// TopP and Seed are sent only when non-zero. A seed combined with an explicit temperature of 0
+	// yields near-deterministic narratives, which helps diff-test prompt changes.
+	TopP float64
+	Seed int64
+	// PromptTemplatePath optionally points to a text/template file defining "system" and/or
+	// "user" templates rendered with PromptData; undefined templates keep the built-in prompts.
+	PromptTemplatePath string
+	// JSONMode sends response_format json_object so the API guarantees parseable JSON. Leave it
+	// off for endpoints that reject the parameter.
+	JSONMode bool
+	// Timeout bounds each chat completion request, defaulting to 30s. MaxIdleConnsPerHost sizes
+	// the client's connection pool (util.DefaultMaxIdleConnsPerHost when zero).
+	Timeout             time.Duration
+	MaxIdleConnsPerHost int
+	// Language is the BCP 47 tag narratives are written in (see ParseLanguage); empty means
+	// English. The two-sentence contract and English JSON keys apply in every language, and the
+	// TemplateNarrative fallback stays in English.
+	Language string
 }
 
 // ExplanationInput describes the signals that feed the AI explanation.
@@ -42,13 +65,23 @@ type ExplanationInput struct {
 	TopLevel             string
 	DomainTokens         []string
 	ViceTerms            []string
-	Recommendation       string
+	Recommendation       scoring.Recommendation
 	AllowOverride        bool
 	HasSubstringAlerts   bool
 	CommercialOverride   bool
 	CommercialSource     string
 	CommercialSimilarity float64
 	CommercialPrice      float64
+	// Randomness flags brand tokens that look algorithmically generated (DGA-style).
+	Randomness scoring.RandomnessResult
+	// Spam flags core labels built like spam: many hyphens, mostly digits, or many segments.
+	Spam match.SpamSignal
+	// SubdomainBrand is set when a subdomain label carries a brand that the registrable domain
+	// does not, a common phishing pattern.
+	SubdomainBrand *scoring.SubdomainBrand
+	// RepeatedNarrative holds a rejected narrative that was too similar to recent output, so the
+	// regeneration can steer away from it.
+	RepeatedNarrative string
 }
 
 // Client implements the Explainer interface against the OpenAI API.
@@ -59,6 +92,13 @@ type Client struct {
 	baseURL     string
 	temperature float64
 	maxTokens   int
+	topP        float64
+	seed        int64
+	jsonMode    bool
+	prompts     *promptTemplates
+	// language and langName are the narrative language and its English display name.
+	language language.Tag
+	langName string
 }
 
 var ErrDisabled = errors.New("ai explainer disabled")
@@ -73,23 +113,45 @@ func NewClient(cfg Config) (*Client, error) {
 	if cfg.BaseURL == "" {
 		cfg.BaseURL = "https://api.openai.com/v1"
 	}
+	lang, langName, err := ParseLanguage(cfg.Language)
+	if err != nil {
+		return nil, err
+	}
 	if strings.TrimSpace(cfg.APIKey) == "" {
 		return nil, ErrDisabled
 	}
-	temp := cfg.Temperature
-	if temp <= 0 {
-		temp = 0.2
+	temp := 0.2
+	if cfg.Temperature != nil && *cfg.Temperature >= 0 {
+		temp = *cfg.Temperature
 	}
 	if cfg.MaxTokens <= 0 {
 		cfg.MaxTokens = 1500
 	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	var prompts *promptTemplates
+	if path := strings.TrimSpace(cfg.PromptTemplatePath); path != "" {
+		loaded, err := loadPromptTemplates(path)
+		if err != nil {
+			return nil, err
+		}
+		prompts = loaded
+	}
 	client := &Client{
-		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		httpClient:  util.NewHTTPClient(timeout, cfg.MaxIdleConnsPerHost),
 		apiKey:      strings.TrimSpace(cfg.APIKey),
 		model:       cfg.Model,
 		baseURL:     cfg.BaseURL,
 		temperature: temp,
 		maxTokens:   cfg.MaxTokens,
+		topP:        cfg.TopP,
+		seed:        cfg.Seed,
+		jsonMode:    cfg.JSONMode,
+		prompts:     prompts,
+		language:    lang,
+		langName:    langName,
 	}
 	return client, nil
 }
@@ -99,65 +161,94 @@ func (c *Client) Enabled() bool {
 	return c != nil && c.apiKey != ""
 }
 
+// Model returns the configured chat model name.
+func (c *Client) Model() string {
+	if c == nil {
+		return ""
+	}
+	return c.model
+}
+
 // Explain requests an AI-generated explanation for a domain evaluation.
 func (c *Client) Explain(ctx context.Context, input ExplanationInput) (Decision, error) {
 	if c == nil || !c.Enabled() {
 		return Decision{}, ErrDisabled
 	}
 
-	payload := c.buildPayload(input)
+	payload, err := c.buildPayload(input)
+	if err != nil {
+		return Decision{}, err
+	}
+	content, err := c.complete(ctx, payload)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	var decision Decision
+	if err := json.Unmarshal([]byte(content), &decision); err != nil {
+		return Decision{}, fmt.Errorf("parse ai response: %w", err)
+	}
+
+	if err := validateDecision(&decision); err != nil {
+		return Decision{}, err
+	}
+	return decision, nil
+}
+
+// complete posts a chat completion payload and returns the JSON content of the first choice.
+func (c *Client) complete(ctx context.Context, payload map[string]any) (string, error) {
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return Decision{}, fmt.Errorf("marshal request: %w", err)
+		return "", fmt.Errorf("marshal request: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
 	if err != nil {
-		return Decision{}, fmt.Errorf("create request: %w", err)
+		return "", fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return Decision{}, fmt.Errorf("openai request: %w", err)
+		return "", fmt.Errorf("openai request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		var apiErr map[string]any
 		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
-		return Decision{}, fmt.Errorf("openai status %d: %v", resp.StatusCode, apiErr)
+		return "", fmt.Errorf("openai status %d: %v", resp.StatusCode, apiErr)
 	}
 
 	var decoded chatCompletionResponse
 	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
-		return Decision{}, fmt.Errorf("decode response: %w", err)
+		return "", fmt.Errorf("decode response: %w", err)
 	}
 
 	if len(decoded.Choices) == 0 {
-		return Decision{}, errors.New("openai empty response")
+		return "", errors.New("openai empty response")
 	}
 
+	// JSON mode should already return a bare object; normalizeJSONBlock still strips fences for
+	// endpoints without response_format support.
 	content := normalizeJSONBlock(decoded.Choices[0].Message.Content)
 	if content == "" {
-		return Decision{}, errors.New("openai empty narrative")
-	}
-
-	var decision Decision
-	if err := json.Unmarshal([]byte(content), &decision); err != nil {
-		return Decision{}, fmt.Errorf("parse ai response: %w", err)
+		return "", errors.New("openai empty narrative")
 	}
+	return content, nil
+}
 
-	sanitizeDecision(&decision)
+// validateDecision sanitizes the decision and rejects responses missing required fields.
+func validateDecision(decision *Decision) error {
+	sanitizeDecision(decision)
 	if decision.Narrative == "" {
-		return Decision{}, errors.New("ai narrative missing")
+		return errors.New("ai narrative missing")
 	}
 	if decision.Recommendation == "" {
-		return Decision{}, errors.New("ai recommendation missing")
+		return errors.New("ai recommendation missing")
 	}
-
-	return decision, nil
+	return checkNarrative(decision.Narrative)
 }
 
 func normalizeJSONBlock(input string) string {
@@ -183,16 +274,27 @@ func normalizeJSONBlock(input string) string {
 	return trimmed
 }
 
-func (c *Client) buildPayload(input ExplanationInput) map[string]any {
-	userPrompt := c.buildUserPrompt(input)
+// systemPrompt defines the narrative contract shared by single and batch requests.
+const systemPrompt = "You are a domain risk analyst. Reply with a strict JSON object containing keys narrative, trademark_score, vice_score, recommendation, and confidence. Evaluate trademark_score and vice_score as integers 0-5 (5 = severe conflict, 0 = clean) using the supplied evidence; only assign 4-5 for clear exact-match conflicts or severe vice activity. Narrative must contain exactly two sentences separated by a newline, and the first sentence must reference the second-level label or its meaning directly. Do not start any sentence with 'The term', 'Overall', 'I', 'I'd', 'Feels like', or 'It comes across', and avoid repeating the same opening clause across responses. Do not prefix the second sentence with labels such as 'Stance:' or 'Recommendation:'; instead, lead with a varied action-oriented phrase that makes the decision sound human. Vary vocabulary and sentence structure between cases so successive narratives do not sound alike. recommendation must be one of BLOCK, REVIEW, ALLOW_WITH_CAUTION, or ALLOW. confidence must be a decimal between 0 and 1. Emit nothing outside the JSON object."
+
+func (c *Client) buildPayload(input ExplanationInput) (map[string]any, error) {
+	system, user, err := c.prompts.render(input, c.systemPrompt(), c.buildUserPrompt(input))
+	if err != nil {
+		return nil, err
+	}
+	return c.payloadFor(system, user), nil
+}
+
+// payloadFor assembles the chat completion payload with the configured sampling options.
+func (c *Client) payloadFor(system, user string) map[string]any {
 	messages := []map[string]string{
 		{
 			"role":    "system",
-			"content": "You are a domain risk analyst. Reply with a strict JSON object containing keys narrative, trademark_score, vice_score, recommendation, and confidence. Evaluate trademark_score and vice_score as integers 0-5 (5 = severe conflict, 0 = clean) using the supplied evidence; only assign 4-5 for clear exact-match conflicts or severe vice activity. Narrative must contain exactly two sentences separated by a newline, and the first sentence must reference the second-level label or its meaning directly. Do not start any sentence with 'The term', 'Overall', 'I', 'I'd', 'Feels like', or 'It comes across', and avoid repeating the same opening clause across responses. Do not prefix the second sentence with labels such as 'Stance:' or 'Recommendation:'; instead, lead with a varied action-oriented phrase that makes the decision sound human. Vary vocabulary and sentence structure between cases so successive narratives do not sound alike. recommendation must be one of BLOCK, REVIEW, ALLOW_WITH_CAUTION, or ALLOW. confidence must be a decimal between 0 and 1. Emit nothing outside the JSON object.",
+			"content": system,
 		},
 		{
 			"role":    "user",
-			"content": userPrompt,
+			"content": user,
 		},
 	}
 	payload := map[string]any{
@@ -203,6 +305,15 @@ func (c *Client) buildPayload(input ExplanationInput) map[string]any {
 	if c.maxTokens > 0 {
 		payload["max_tokens"] = c.maxTokens
 	}
+	if c.topP > 0 {
+		payload["top_p"] = c.topP
+	}
+	if c.seed != 0 {
+		payload["seed"] = c.seed
+	}
+	if c.jsonMode {
+		payload["response_format"] = map[string]string{"type": "json_object"}
+	}
 	return payload
 }
 
@@ -226,6 +337,9 @@ func (c *Client) buildUserPrompt(input ExplanationInput) string {
 	if len(input.ViceTerms) > 0 {
 		fmt.Fprintf(builder, "Vice Terms: %s\n", strings.Join(input.ViceTerms, ", "))
 	}
+	if len(input.Vice.Languages) > 0 {
+		fmt.Fprintf(builder, "Vice Term Languages: %s\n", strings.Join(input.Vice.Languages, ", "))
+	}
 	fmt.Fprintf(builder, "Vice Confidence: %.2f\n", input.Vice.Confidence)
 	fmt.Fprintf(builder, "Overall Recommendation: %s (confidence %.2f)\n", input.Overall.Recommendation, input.Overall.Confidence)
 	if input.MarksCount > 0 {
@@ -238,7 +352,7 @@ func (c *Client) buildUserPrompt(input ExplanationInput) string {
 		fmt.Fprintf(builder, "Closest trademark references: %s\n", strings.Join(input.CloseMatches, "; "))
 	}
 	if input.Recommendation != "" {
-		fmt.Fprintf(builder, "Default recommendation: %s\n", strings.ToUpper(input.Recommendation))
+		fmt.Fprintf(builder, "Default recommendation: %s\n", input.Recommendation)
 	}
 	if input.AllowOverride {
 		builder.WriteString("You may override the default recommendation if contextual evidence supports doing so.\n")
@@ -255,6 +369,18 @@ func (c *Client) buildUserPrompt(input ExplanationInput) string {
 	} else if input.CommercialOverride && input.CommercialPrice > 0 {
 		builder.WriteString(fmt.Sprintf("Commercial signal: historical sale around $%.0f supports market demand.\n", input.CommercialPrice))
 	}
+	if input.Randomness.Random {
+		fmt.Fprintf(builder, "Randomness signal: the label looks algorithmically generated (entropy %.2f, %.0f%% uncommon letter pairs); weigh DGA or throwaway-registration intent.\n",
+			input.Randomness.Entropy, input.Randomness.RareBigramRatio*100)
+	}
+	if input.Spam.Spammy {
+		fmt.Fprintf(builder, "Spam-pattern signal: the label is built like bulk spam (%s); weigh low-quality or abusive registration intent.\n",
+			input.Spam.Describe())
+	}
+	if brand := input.SubdomainBrand; brand != nil {
+		fmt.Fprintf(builder, "Subdomain signal: the subdomain label %q carries the %s mark %s, but the registrable domain %s does not; weigh impersonation or phishing intent.\n",
+			brand.Label, brand.Trademark.Type, brand.Trademark.MatchedTrademark, brand.Registrable)
+	}
 	builder.WriteString("Heuristic trademark score suggestion (0-5): ")
 	fmt.Fprintf(builder, "%d\n", input.Trademark.Score)
 	builder.WriteString("Heuristic vice score suggestion (0-5): ")
@@ -275,6 +401,12 @@ func (c *Client) buildUserPrompt(input ExplanationInput) string {
 	builder.WriteString("Open the first sentence with a vivid description or plausible use case rather than a stock phrase.\n")
 	builder.WriteString("Let the second sentence start with an action-oriented verb or directive (e.g., 'Greenlight', 'Flag', 'Escalate for legal eyes') while justifying the decision; never use the exact same starter twice.\n")
 	builder.WriteString("Explain the likely use of the name, cite any trademark or vice evidence you spot, and mention commercial signals if they matter.\n")
+	if repeated := strings.TrimSpace(input.RepeatedNarrative); repeated != "" {
+		fmt.Fprintf(builder, "A previous draft closely repeated earlier narratives (%q); use a different opening, structure, and vocabulary this time.\n", repeated)
+	}
+	if c.languageInstruction() != "" {
+		fmt.Fprintf(builder, "Respond in %s: write both narrative sentences in %s while keeping the JSON keys and recommendation values in English.\n", c.langName, c.langName)
+	}
 	builder.WriteString("Populate the JSON fields with your final judgement. Narrative must include two sentences separated by a newline; vary how you introduce the recommendation in the second sentence while clearly stating the action and justification.\n")
 	return builder.String()
 }
@@ -291,7 +423,7 @@ func sanitizeDecision(decision *Decision) {
 	if decision == nil {
 		return
 	}
-	decision.Narrative = strings.TrimSpace(decision.Narrative)
+	decision.Narrative = normalizeNarrative(decision.Narrative)
 	if decision.TrademarkScore != nil {
 		val := clampInt(*decision.TrademarkScore, 0, 5)
 		decision.TrademarkScore = &val
@@ -300,12 +432,8 @@ func sanitizeDecision(decision *Decision) {
 		val := clampInt(*decision.ViceScore, 0, 5)
 		decision.ViceScore = &val
 	}
-	decision.Recommendation = strings.ToUpper(strings.TrimSpace(decision.Recommendation))
-	switch decision.Recommendation {
-	case "BLOCK", "REVIEW", "ALLOW_WITH_CAUTION", "ALLOW":
-	default:
-		decision.Recommendation = ""
-	}
+	// Unknown values are dropped so validateDecision rejects the response.
+	decision.Recommendation, _ = scoring.ParseRecommendation(string(decision.Recommendation))
 	if decision.Confidence != nil {
 		val := clampFloat(*decision.Confidence, 0, 1)
 		decision.Confidence = &val
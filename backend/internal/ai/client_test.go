@@ -0,0 +1,41 @@
+package ai
+
+import "testing"
+
+func TestBuildPayloadSamplingOptions(t *testing.T) {
+	zero := 0.0
+	tests := []struct {
+		name     string
+		cfg      Config
+		wantTemp float64
+		wantTopP any
+		wantSeed any
+	}{
+		{"defaults", Config{}, 0.2, nil, nil},
+		{"seed alone keeps the default temperature", Config{Seed: 42}, 0.2, nil, int64(42)},
+		{"explicit zero temperature", Config{Temperature: &zero, Seed: 42}, 0, nil, int64(42)},
+		{"top_p", Config{TopP: 0.9}, 0.2, 0.9, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.cfg.APIKey = "test"
+			client, err := NewClient(tc.cfg)
+			if err != nil {
+				t.Fatalf("new client: %v", err)
+			}
+			payload, err := client.buildPayload(ExplanationInput{Domain: "alpha.com", SecondLevel: "alpha", TopLevel: "com"})
+			if err != nil {
+				t.Fatalf("build payload: %v", err)
+			}
+			if payload["temperature"] != tc.wantTemp {
+				t.Fatalf("expected temperature %v, got %v", tc.wantTemp, payload["temperature"])
+			}
+			if payload["top_p"] != tc.wantTopP {
+				t.Fatalf("expected top_p %v, got %v", tc.wantTopP, payload["top_p"])
+			}
+			if payload["seed"] != tc.wantSeed {
+				t.Fatalf("expected seed %v, got %v", tc.wantSeed, payload["seed"])
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package ai
+
+import "context"
+
+// FakeExplainer is an offline Explainer returning deterministic decisions, so the evaluation
+// pipeline can be run end to end, including the score and recommendation overrides that only
+// apply when an explainer is enabled, without an OpenAI key. It also implements BatchExplainer.
+type FakeExplainer struct {
+	// Decide, when set, produces the decision for each input; nil uses EchoDecision.
+	Decide func(ExplanationInput) (Decision, error)
+}
+
+// NewFakeExplainer returns a FakeExplainer answering with EchoDecision.
+func NewFakeExplainer() *FakeExplainer {
+	return &FakeExplainer{}
+}
+
+// EchoDecision agrees with the heuristic scoring: it repeats the input's scores, recommendation,
+// and confidence alongside the template narrative.
+func EchoDecision(input ExplanationInput) (Decision, error) {
+	trademark, vice, confidence := input.Trademark.Score, input.Vice.Score, input.Overall.Confidence
+	return Decision{
+		Narrative:      TemplateNarrative(input),
+		TrademarkScore: &trademark,
+		ViceScore:      &vice,
+		Recommendation: input.Recommendation,
+		Confidence:     &confidence,
+	}, nil
+}
+
+// Enabled reports true for any non-nil fake.
+func (f *FakeExplainer) Enabled() bool {
+	return f != nil
+}
+
+// Model names the fake in /api/config.
+func (f *FakeExplainer) Model() string {
+	return "fake"
+}
+
+// Explain returns the decision for input, or the context's error once it is done.
+func (f *FakeExplainer) Explain(ctx context.Context, input ExplanationInput) (Decision, error) {
+	if f == nil {
+		return Decision{}, ErrDisabled
+	}
+	if err := ctx.Err(); err != nil {
+		return Decision{}, err
+	}
+	if f.Decide != nil {
+		return f.Decide(input)
+	}
+	return EchoDecision(input)
+}
+
+// ExplainBatch explains each input in turn.
+func (f *FakeExplainer) ExplainBatch(ctx context.Context, inputs []ExplanationInput) ([]Decision, error) {
+	decisions := make([]Decision, 0, len(inputs))
+	for _, input := range inputs {
+		decision, err := f.Explain(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		decisions = append(decisions, decision)
+	}
+	return decisions, nil
+}
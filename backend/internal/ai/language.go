@@ -0,0 +1,56 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// ParseLanguage validates a BCP 47 narrative language tag such as "fr" or "pt-BR" and returns
+// its canonical form with an English display name for prompts. Empty selects English.
+func ParseLanguage(value string) (language.Tag, string, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return language.English, "English", nil
+	}
+	tag, err := language.Parse(trimmed)
+	if err != nil {
+		return language.Und, "", fmt.Errorf("narrative language %q: %w", value, err)
+	}
+	name := display.English.Tags().Name(tag)
+	if tag == language.Und || name == "" {
+		return language.Und, "", fmt.Errorf("narrative language %q is not a recognized language", value)
+	}
+	return tag, name, nil
+}
+
+// Language returns the narrative language tag, e.g. "fr".
+func (c *Client) Language() string {
+	if c == nil {
+		return ""
+	}
+	return c.language.String()
+}
+
+// isEnglish reports whether tag is English or a regional variant of it.
+func isEnglish(tag language.Tag) bool {
+	base, _ := tag.Base()
+	english, _ := language.English.Base()
+	return base == english
+}
+
+// languageInstruction is appended to the system prompt when narratives are not in English. JSON
+// keys and enum values must stay in English so the response still parses.
+func (c *Client) languageInstruction() string {
+	if c.langName == "" || isEnglish(c.language) {
+		return ""
+	}
+	return fmt.Sprintf(" Write the narrative in %s; keep the JSON keys and the recommendation value in English exactly as specified.", c.langName)
+}
+
+// systemPrompt returns the built-in system prompt with the narrative language applied.
+func (c *Client) systemPrompt() string {
+	return systemPrompt + c.languageInstruction()
+}
@@ -0,0 +1,244 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"domain-risk-eval/backend/internal/scoring"
+)
+
+// TemplateNarrative builds a deterministic two-sentence narrative from the evaluation signals.
+// It makes no external calls and is used whenever the AI explainer is disabled or exhausts its
+// retries, so AI-off runs still describe why each domain landed in its bucket.
+func TemplateNarrative(input ExplanationInput) string {
+	label := strings.TrimSpace(input.SecondLevel)
+	if label == "" {
+		label = strings.TrimSpace(input.Domain)
+	}
+	tld := strings.TrimSpace(input.TopLevel)
+
+	first := &strings.Builder{}
+	if tld != "" {
+		fmt.Fprintf(first, "The label \"%s\" on .%s", label, tld)
+	} else {
+		fmt.Fprintf(first, "The label \"%s\"", label)
+	}
+	switch {
+	case input.Trademark.Type == scoring.TrademarkTypeEmbedded && input.Trademark.MatchedTrademark != "":
+		fmt.Fprintf(first, " contains the mark %s as a component (trademark score %d/5)", input.Trademark.MatchedTrademark, input.Trademark.Score)
+	case input.Trademark.MatchedTrademark != "" && input.Trademark.Score > 0:
+		fmt.Fprintf(first, " matches the %s mark %s (trademark score %d/5)", typeLabel(input.Trademark.Type), input.Trademark.MatchedTrademark, input.Trademark.Score)
+	case input.Trademark.MatchedTrademark != "":
+		fmt.Fprintf(first, " matches the %s mark %s, which carries no meaningful trademark weight", typeLabel(input.Trademark.Type), input.Trademark.MatchedTrademark)
+	case len(input.CloseMatches) > 0:
+		fmt.Fprintf(first, " has no exact trademark conflict, though nearby marks include %s", strings.Join(limitStrings(input.CloseMatches, 3), ", "))
+	default:
+		first.WriteString(" shows no trademark conflict in the indexed marks")
+	}
+	if len(input.Vice.Categories) > 0 {
+		fmt.Fprintf(first, "; vice terms %s set the vice score to %d/5", strings.Join(input.Vice.Categories, ", "), input.Vice.Score)
+	} else {
+		first.WriteString("; no vice terms were detected")
+	}
+	if source := strings.TrimSpace(input.CommercialSource); source != "" {
+		fmt.Fprintf(first, ", and a comparable %s (similarity %.2f) signals legitimate demand", source, input.CommercialSimilarity)
+	}
+	first.WriteString(".")
+
+	return first.String() + "\n" + actionSentence(input)
+}
+
+func actionSentence(input ExplanationInput) string {
+	rec := input.Overall.Recommendation
+	if rec == "" {
+		rec = input.Recommendation
+	}
+	var sentence string
+	switch rec {
+	case scoring.RecommendationBlock:
+		sentence = "Block this name, since the heuristic matrix rates the combined risk as severe"
+	case scoring.RecommendationReview:
+		sentence = "Route it to manual review before approval, as the signals are material but not conclusive"
+	case scoring.RecommendationAllowWithCaution:
+		sentence = "Allow it with caution and monitor usage, because minor risk signals remain"
+	default:
+		sentence = "Allow it, since no material risk signals were found"
+	}
+	if input.CommercialOverride {
+		sentence += "; the comparable sale already softened this outcome"
+	}
+	return sentence + "."
+}
+
+func typeLabel(markType string) string {
+	switch strings.TrimSpace(markType) {
+	case "", "none":
+		return "indexed"
+	default:
+		return markType
+	}
+}
+
+func limitStrings(items []string, max int) []string {
+	if len(items) <= max {
+		return items
+	}
+	return items[:max]
+}
+
+// ErrNarrativeContract marks AI narratives that break the two-sentence contract from the system
+// prompt. Callers treat it as retryable.
+var ErrNarrativeContract = errors.New("ai narrative violates contract")
+
+// narrativeLabels are the label prefixes the prompt forbids; they are stripped rather than rejected.
+var narrativeLabels = []string{"recommended stance:", "stance:", "recommendation:", "decision:", "verdict:"}
+
+// bannedOpeners are sentence openings the prompt forbids outright.
+var bannedOpeners = []string{"the term", "overall", "i'd", "i ", "feels like", "it comes across"}
+
+// normalizeNarrative strips forbidden label prefixes and repairs single-line narratives holding two
+// sentences by splitting them onto separate lines.
+func normalizeNarrative(narrative string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(narrative), "\n") {
+		line = stripNarrativeLabel(strings.TrimSpace(line))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 1 {
+		if first, second, ok := splitSentences(lines[0]); ok {
+			lines = []string{first, second}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func stripNarrativeLabel(line string) string {
+	for {
+		lower := strings.ToLower(line)
+		stripped := false
+		for _, label := range narrativeLabels {
+			if strings.HasPrefix(lower, label) {
+				line = strings.TrimSpace(strings.TrimLeft(line[len(label):], " -–—"))
+				stripped = true
+				break
+			}
+		}
+		if !stripped {
+			return line
+		}
+	}
+}
+
+// narrativeAbbreviations are words whose trailing period does not end a sentence, e.g. "Inc." in
+// "Acme Inc. Holdings". Initialisms such as "U.S." and single initials are recognised separately.
+var narrativeAbbreviations = map[string]struct{}{
+	"co": {}, "corp": {}, "dr": {}, "inc": {}, "jr": {}, "ltd": {}, "mr": {}, "mrs": {}, "ms": {}, "no": {}, "sr": {}, "st": {}, "vs": {},
+}
+
+// splitSentences splits text holding exactly two sentences at the boundary between them. A
+// boundary is ".", "!", or "?" followed by a space and an uppercase or uncased letter, unless the
+// period ends an abbreviation, or a full-width "。", "！", or "？" followed by more text, as
+// written in Chinese and Japanese.
+func splitSentences(text string) (string, string, bool) {
+	var bounds []int
+	runes := []rune(text)
+	for i := 0; i+1 < len(runes); i++ {
+		switch runes[i] {
+		case '.', '!', '?':
+			if i+2 < len(runes) && runes[i+1] == ' ' && startsSentence(runes[i+2]) && !(runes[i] == '.' && endsAbbreviation(runes[:i])) {
+				bounds = append(bounds, i+1)
+			}
+		case '。', '！', '？':
+			bounds = append(bounds, i+1)
+		}
+	}
+	if len(bounds) != 1 {
+		return "", "", false
+	}
+	return strings.TrimSpace(string(runes[:bounds[0]])), strings.TrimSpace(string(runes[bounds[0]:])), true
+}
+
+// startsSentence reports whether r can open a sentence: an uppercase letter, or a letter from a
+// script without case.
+func startsSentence(r rune) bool {
+	return unicode.IsUpper(r) || (unicode.IsLetter(r) && !unicode.IsLower(r))
+}
+
+// endsAbbreviation reports whether the word ending text, just before a period, is an
+// abbreviation: a single letter, an initialism of single letters separated by periods ("U.S",
+// "e.g"), or a narrativeAbbreviations entry. Dotted words such as "apple.com" are not.
+func endsAbbreviation(text []rune) bool {
+	start := len(text)
+	for start > 0 && !unicode.IsSpace(text[start-1]) {
+		start--
+	}
+	word := strings.TrimLeftFunc(string(text[start:]), func(r rune) bool { return !unicode.IsLetter(r) })
+	if isInitialism(word) {
+		return true
+	}
+	_, ok := narrativeAbbreviations[strings.ToLower(word)]
+	return ok
+}
+
+// isInitialism reports whether word is one or more single letters separated by periods.
+func isInitialism(word string) bool {
+	if word == "" {
+		return false
+	}
+	for _, part := range strings.Split(word, ".") {
+		runes := []rune(part)
+		if len(runes) != 1 || !unicode.IsLetter(runes[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkNarrative verifies a normalized narrative has exactly two lines and no banned openers.
+func checkNarrative(narrative string) error {
+	lines := strings.Split(narrative, "\n")
+	if len(lines) != 2 {
+		return fmt.Errorf("%w: expected 2 sentences, got %d", ErrNarrativeContract, len(lines))
+	}
+	for i, line := range lines {
+		lower := strings.ToLower(line)
+		for _, opener := range bannedOpeners {
+			if strings.HasPrefix(lower, opener) {
+				return fmt.Errorf("%w: sentence %d opens with %q", ErrNarrativeContract, i+1, strings.TrimSpace(opener))
+			}
+		}
+	}
+	return nil
+}
+
+// DefaultMaxNarrativeLength is the narrative length, in characters, TruncateNarrative callers use
+// when none is configured; a normal two-sentence narrative is well under it.
+const DefaultMaxNarrativeLength = 2000
+
+// TruncateNarrative shortens narrative to at most max characters, cutting after the last
+// sentence that fits. When no sentence ends in the first half it cuts at the last space instead
+// and appends an ellipsis. It reports whether the narrative was shortened; max <= 0 disables the
+// limit.
+func TruncateNarrative(narrative string, max int) (string, bool) {
+	runes := []rune(narrative)
+	if max <= 0 || len(runes) <= max {
+		return narrative, false
+	}
+	for i := max - 1; i >= max/2; i-- {
+		if strings.ContainsRune(".!?", runes[i]) && (i+1 == len(runes) || unicode.IsSpace(runes[i+1])) {
+			return strings.TrimSpace(string(runes[:i+1])), true
+		}
+	}
+	cut := max - 1
+	for i := cut; i > 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			cut = i
+			break
+		}
+	}
+	return strings.TrimSpace(string(runes[:cut])) + "…", true
+}
@@ -0,0 +1,114 @@
+package ai
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"domain-risk-eval/backend/internal/scoring"
+)
+
+func TestTemplateNarrative(t *testing.T) {
+	vice := scoring.ViceResult{Score: 4, Categories: []string{"gambling"}}
+	tests := []struct {
+		name  string
+		input ExplanationInput
+		want  []string
+	}{
+		{"embedded", ExplanationInput{SecondLevel: "applestore", TopLevel: "com",
+			Trademark: scoring.TrademarkResult{Type: scoring.TrademarkTypeEmbedded, MatchedTrademark: "APPLE", Score: 4},
+			Overall:   scoring.OverallResult{Recommendation: scoring.RecommendationBlock}},
+			[]string{`The label "applestore" on .com contains the mark APPLE as a component (trademark score 4/5); no vice terms were detected.`, "Block this name"}},
+		{"scored match", ExplanationInput{SecondLevel: "zorblax",
+			Trademark: scoring.TrademarkResult{Type: "fanciful", MatchedTrademark: "ZORBLAX", Score: 5}},
+			[]string{`The label "zorblax" matches the fanciful mark ZORBLAX (trademark score 5/5)`, "Allow it, since"}},
+		{"zero-weight match", ExplanationInput{Domain: "cloud.io",
+			Trademark: scoring.TrademarkResult{Type: "none", MatchedTrademark: "CLOUD"},
+			Overall:   scoring.OverallResult{Recommendation: scoring.RecommendationAllowWithCaution}},
+			[]string{`The label "cloud.io" matches the indexed mark CLOUD, which carries no meaningful trademark weight`, "Allow it with caution"}},
+		{"close matches and vice", ExplanationInput{SecondLevel: "betzone", TopLevel: "net", Vice: vice,
+			CloseMatches:   []string{"BETZ", "BETONE", "ZONEBET", "BETZONE PRO"},
+			Recommendation: scoring.RecommendationReview},
+			[]string{"though nearby marks include BETZ, BETONE, ZONEBET; vice terms gambling set the vice score to 4/5.", "Route it to manual review"}},
+		{"no conflict with commercial override", ExplanationInput{SecondLevel: "meadow", TopLevel: "com",
+			CommercialSource: "sale $25000", CommercialSimilarity: 0.91, CommercialOverride: true,
+			Overall: scoring.OverallResult{Recommendation: scoring.RecommendationReview}},
+			[]string{"shows no trademark conflict in the indexed marks; no vice terms were detected, and a comparable sale $25000 (similarity 0.91) signals legitimate demand.", "the comparable sale already softened this outcome."}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := TemplateNarrative(tc.input)
+			if lines := strings.Split(got, "\n"); len(lines) != 2 {
+				t.Fatalf("expected exactly 2 lines, got %d: %q", len(lines), got)
+			}
+			for _, part := range tc.want {
+				if !strings.Contains(got, part) {
+					t.Fatalf("expected %q in %q", part, got)
+				}
+			}
+			if err := checkNarrative(got); err != nil {
+				t.Fatalf("template breaks the narrative contract: %v", err)
+			}
+		})
+	}
+}
+
+func TestNormalizeAndCheckNarrative(t *testing.T) {
+	tests := []struct {
+		name      string
+		narrative string
+		want      string
+		wantErr   bool
+	}{
+		{"two lines", "The label is clean.\nAllow it.", "The label is clean.\nAllow it.", false},
+		{"stance label", "Stance: The label is clean.\nRecommendation: Allow it.", "The label is clean.\nAllow it.", false},
+		{"repeated labels", "Recommended stance: - Verdict: The label is clean.\nAllow it.", "The label is clean.\nAllow it.", false},
+		{"one line split", "The label is clean. Allow it.", "The label is clean.\nAllow it.", false},
+		{"abbreviation kept", "The label echoes U.S. Bank closely.", "The label echoes U.S. Bank closely.", true},
+		{"abbreviation then split", "It mirrors Acme Inc. Holdings. Route it to review.", "It mirrors Acme Inc. Holdings.\nRoute it to review.", false},
+		{"domain before split", "Looks like apple.com. Block it.", "Looks like apple.com.\nBlock it.", false},
+		{"lowercase continuation", "Scores are low, e.g. under 2. route it.", "Scores are low, e.g. under 2. route it.", true},
+		{"banned opener on sentence 2", "The label is clean.\nOverall, allow it.", "The label is clean.\nOverall, allow it.", true},
+		{"banned opener on sentence 1", "The term is clean.\nAllow it.", "The term is clean.\nAllow it.", true},
+		{"three sentences", "The label is clean. It has no vice terms. Allow it.", "The label is clean. It has no vice terms. Allow it.", true},
+		{"three lines", "One.\nTwo.\nThree.", "One.\nTwo.\nThree.", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := normalizeNarrative(tc.narrative)
+			if got != tc.want {
+				t.Fatalf("expected %q got %q", tc.want, got)
+			}
+			err := checkNarrative(got)
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrNarrativeContract) {
+				t.Fatalf("expected ErrNarrativeContract, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNormalizeNarrativeSplitsUncasedScripts(t *testing.T) {
+	tests := []struct {
+		name      string
+		narrative string
+		want      string
+	}{
+		{"japanese", "このラベルは商標と一致しません。手動審査は不要です。", "このラベルは商標と一致しません。\n手動審査は不要です。"},
+		{"chinese with space", "该标签没有商标冲突！ 可以批准。", "该标签没有商标冲突！\n可以批准。"},
+		{"uncased after period", "Label ok. 可以批准。", "Label ok.\n可以批准。"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := normalizeNarrative(tc.narrative)
+			if got != tc.want {
+				t.Fatalf("expected %q got %q", tc.want, got)
+			}
+			if err := checkNarrative(got); err != nil {
+				t.Fatalf("check: %v", err)
+			}
+		})
+	}
+}
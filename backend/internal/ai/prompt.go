@@ -0,0 +1,103 @@
+package ai
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"domain-risk-eval/backend/internal/scoring"
+)
+
+// PromptData is the template data for custom prompts. ExplanationInput fields are promoted, and
+// DefaultSystem/DefaultUser hold the built-in prompts so a template can extend rather than replace them.
+type PromptData struct {
+	ExplanationInput
+	DefaultSystem string
+	DefaultUser   string
+}
+
+// promptTemplates holds the optional "system" and "user" templates; a missing one keeps the
+// built-in prompt.
+type promptTemplates struct {
+	system *template.Template
+	user   *template.Template
+}
+
+var promptFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+}
+
+// loadPromptTemplates parses a text/template file defining "system" and/or "user" templates and
+// renders them once against sample data so field typos fail at startup.
+func loadPromptTemplates(path string) (*promptTemplates, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read prompt template: %w", err)
+	}
+	root, err := template.New(filepath.Base(path)).Funcs(promptFuncs).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse prompt template: %w", err)
+	}
+	prompts := &promptTemplates{
+		system: root.Lookup("system"),
+		user:   root.Lookup("user"),
+	}
+	if prompts.system == nil && prompts.user == nil {
+		return nil, fmt.Errorf("prompt template %s must define a \"system\" or \"user\" template", path)
+	}
+
+	sample := ExplanationInput{
+		Domain:       "example.com",
+		SecondLevel:  "example",
+		TopLevel:     "com",
+		DomainTokens: []string{"example"},
+		Trademark:    scoring.TrademarkResult{Score: 1, Type: "generic"},
+		Overall:      scoring.OverallResult{Recommendation: scoring.RecommendationAllow, Confidence: 0.9},
+	}
+	if _, _, err := prompts.render(sample, systemPrompt, "sample user prompt"); err != nil {
+		return nil, err
+	}
+	return prompts, nil
+}
+
+// render executes the configured templates, falling back to the defaults for undefined ones.
+func (p *promptTemplates) render(input ExplanationInput, defaultSystem, defaultUser string) (string, string, error) {
+	if p == nil {
+		return defaultSystem, defaultUser, nil
+	}
+	data := PromptData{ExplanationInput: input, DefaultSystem: defaultSystem, DefaultUser: defaultUser}
+	system, user := defaultSystem, defaultUser
+	if p.system != nil {
+		out, err := executePrompt(p.system, data)
+		if err != nil {
+			return "", "", err
+		}
+		system = out
+	}
+	if p.user != nil {
+		out, err := executePrompt(p.user, data)
+		if err != nil {
+			return "", "", err
+		}
+		user = out
+	}
+	return system, user, nil
+}
+
+func executePrompt(tmpl *template.Template, data PromptData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render %s prompt template: %w", tmpl.Name(), err)
+	}
+	out := strings.TrimSpace(buf.String())
+	if out == "" {
+		return "", fmt.Errorf("%s prompt template rendered empty", tmpl.Name())
+	}
+	return out, nil
+}
@@ -1,10 +1,12 @@
 package ai
 
+import "domain-risk-eval/backend/internal/scoring"
+
 // Decision captures the structured response expected from the AI explainer.
 type Decision struct {
-	Narrative      string   `json:"narrative"`
-	TrademarkScore *int     `json:"trademark_score,omitempty"`
-	ViceScore      *int     `json:"vice_score,omitempty"`
-	Recommendation string   `json:"recommendation"`
-	Confidence     *float64 `json:"confidence,omitempty"`
+	Narrative      string                 `json:"narrative"`
+	TrademarkScore *int                   `json:"trademark_score,omitempty"`
+	ViceScore      *int                   `json:"vice_score,omitempty"`
+	Recommendation scoring.Recommendation `json:"recommendation"`
+	Confidence     *float64               `json:"confidence,omitempty"`
 }
@@ -0,0 +1,304 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"domain-risk-eval/backend/internal/scoring"
+	"domain-risk-eval/backend/internal/store"
+	xmlparser "domain-risk-eval/backend/internal/xml"
+)
+
+// ingestProgressInterval throttles ingest progress broadcasts to every N marks.
+const ingestProgressInterval = 5000
+
+// errIngestTooLarge reports an ingest file over the upload size limit.
+var errIngestTooLarge = errors.New("ingest file exceeds the upload size limit")
+
+// PopularRefreshRequest overrides the configured popular-mark limits for a refresh.
+type PopularRefreshRequest struct {
+	Limit    int `json:"limit"`
+	MinCount int `json:"min_count"`
+}
+
+// requireAdmin rejects requests without the configured admin bearer token. Admin routes stay
+// closed when no token is configured.
+func (s *Server) requireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.adminToken == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin endpoints disabled: configure ADMIN_TOKEN"})
+			return
+		}
+		header := strings.TrimSpace(c.GetHeader("Authorization"))
+		token := strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+		if header == token || subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid admin token"})
+			return
+		}
+		c.Next()
+	}
+}
+
+func (s *Server) handleRefreshPopular(c *gin.Context) {
+	if s.db.MarksReadOnly() {
+		s.renderError(c, http.StatusConflict, store.ErrMarksReadOnly)
+		return
+	}
+	var req PopularRefreshRequest
+	if c.Request.Body != nil {
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			s.renderError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+	if req.Limit <= 0 {
+		req.Limit = s.popularLimit
+	}
+	if req.MinCount <= 0 {
+		req.MinCount = s.popularMinCount
+	}
+	if req.Limit <= 0 || req.MinCount <= 0 {
+		s.renderError(c, http.StatusBadRequest, errors.New("limit and min_count must be positive"))
+		return
+	}
+
+	if !s.popularMu.TryLock() {
+		s.renderError(c, http.StatusConflict, errors.New("popular refresh already running"))
+		return
+	}
+	defer s.popularMu.Unlock()
+
+	start := time.Now()
+	count, err := scoring.LoadPopularTokens(s.db, req.Limit, req.MinCount)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	s.invalidateTrademarkMarks()
+	duration := time.Since(start).Round(time.Millisecond)
+	requestLogger(c).WithFields(logrus.Fields{
+		"limit":          req.Limit,
+		"min_count":      req.MinCount,
+		"popular_tokens": count,
+		"duration":       duration,
+	}).Info("refreshed popular mark tokens")
+
+	c.JSON(http.StatusOK, gin.H{
+		"popular_tokens": count,
+		"limit":          req.Limit,
+		"min_count":      req.MinCount,
+		"duration_ms":    duration.Milliseconds(),
+	})
+}
+
+// IngestRequest selects the USPTO XML/ZIP source for an admin ingest. A multipart "file" upload
+// takes precedence over Path (a file on the server) and URL (downloaded before ingesting).
+type IngestRequest struct {
+	Path           string `json:"path" form:"path"`
+	URL            string `json:"url" form:"url"`
+	RefreshPopular bool   `json:"refresh_popular" form:"refresh_popular"`
+}
+
+func (s *Server) handleAdminIngest(c *gin.Context) {
+	if s.db.MarksReadOnly() {
+		s.renderError(c, http.StatusConflict, store.ErrMarksReadOnly)
+		return
+	}
+	var req IngestRequest
+	if c.Request.Body != nil {
+		if err := c.ShouldBind(&req); err != nil && !errors.Is(err, io.EOF) {
+			s.renderError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	if !s.ingestMu.TryLock() {
+		s.renderError(c, http.StatusConflict, errors.New("ingest already running"))
+		return
+	}
+	locked := true
+	defer func() {
+		if locked {
+			s.ingestMu.Unlock()
+		}
+	}()
+
+	var (
+		path    string
+		cleanup func()
+		source  string
+	)
+	if header, err := c.FormFile("file"); err == nil {
+		if header.Size > s.uploadMax {
+			s.renderError(c, http.StatusRequestEntityTooLarge, fmt.Errorf("ingest file exceeds %d bytes", s.uploadMax))
+			return
+		}
+		path, cleanup, err = saveFormFile(header)
+		if err != nil {
+			s.renderError(c, http.StatusInternalServerError, err)
+			return
+		}
+		source = header.Filename
+	} else if trimmed := strings.TrimSpace(req.Path); trimmed != "" {
+		if _, err := os.Stat(trimmed); err != nil {
+			s.renderError(c, http.StatusBadRequest, fmt.Errorf("ingest path: %w", err))
+			return
+		}
+		path, source = trimmed, trimmed
+	} else if trimmed := strings.TrimSpace(req.URL); trimmed != "" {
+		path, cleanup, err = downloadIngestFile(c.Request.Context(), trimmed, s.uploadMax)
+		if errors.Is(err, errIngestTooLarge) {
+			s.renderError(c, http.StatusRequestEntityTooLarge, fmt.Errorf("%w of %d bytes", err, s.uploadMax))
+			return
+		}
+		if err != nil {
+			s.renderError(c, http.StatusBadGateway, err)
+			return
+		}
+		source = trimmed
+	} else {
+		s.renderError(c, http.StatusBadRequest, errors.New("file, path, or url is required"))
+		return
+	}
+
+	jobID := uuid.NewString()
+	locked = false
+	go s.runIngest(jobID, requestIDFrom(c), path, source, cleanup, req.RefreshPopular)
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"job_id": jobID,
+		"source": source,
+	})
+}
+
+// runIngest streams the XML into the live database and reports progress over the evaluation
+// notifier. The caller must hold s.ingestMu; it is released when the ingest finishes.
+func (s *Server) runIngest(jobID, correlationID, path, source string, cleanup func(), refreshPopular bool) {
+	defer s.ingestMu.Unlock()
+	if cleanup != nil {
+		defer cleanup()
+	}
+
+	start := time.Now()
+	logger := logrus.WithFields(logrus.Fields{"job": jobID, "source": source, requestIDKey: correlationID})
+	logger.Info("admin ingest started")
+	s.evalNotifier.Broadcast(EvaluationEvent{
+		Type:    "ingest_started",
+		JobID:   jobID,
+		Message: fmt.Sprintf("ingesting %s", source),
+	})
+
+	count, err := xmlparser.Ingest(xmlparser.IngestOptions{
+		Path:    path,
+		DB:      s.db,
+		Decider: s.fancifulDecider,
+		Progress: func(count int) {
+			if count%ingestProgressInterval == 0 {
+				s.evalNotifier.Broadcast(EvaluationEvent{
+					Type:      "ingest_progress",
+					JobID:     jobID,
+					Processed: count,
+				})
+			}
+		},
+	})
+	if err != nil {
+		logger.WithError(err).WithField("marks", count).Error("admin ingest failed")
+		if count > 0 {
+			// Marks written before the failure are already in the store.
+			s.invalidateTrademarkMarks()
+		}
+		s.evalNotifier.Broadcast(EvaluationEvent{
+			Type:      "ingest_error",
+			JobID:     jobID,
+			Processed: count,
+			Message:   err.Error(),
+		})
+		return
+	}
+
+	message := fmt.Sprintf("ingested %d marks in %s", count, time.Since(start).Round(time.Second))
+	if refreshPopular && s.popularLimit > 0 && s.popularMinCount > 0 {
+		s.popularMu.Lock()
+		tokens, err := scoring.LoadPopularTokens(s.db, s.popularLimit, s.popularMinCount)
+		s.popularMu.Unlock()
+		if err != nil {
+			logger.WithError(err).Warn("refresh popular tokens after ingest")
+		} else {
+			message += fmt.Sprintf("; %d popular tokens", tokens)
+		}
+	}
+	s.invalidateTrademarkMarks()
+
+	logger.WithFields(logrus.Fields{
+		"marks":    count,
+		"duration": time.Since(start).Round(time.Second),
+	}).Info("admin ingest complete")
+	s.evalNotifier.Broadcast(EvaluationEvent{
+		Type:      "ingest_complete",
+		JobID:     jobID,
+		Processed: count,
+		Message:   message,
+	})
+}
+
+// downloadIngestFile fetches a remote XML/ZIP into a temp file, keeping its extension so the
+// ingester can detect ZIP archives. Bodies over maxBytes fail with errIngestTooLarge.
+func downloadIngestFile(ctx context.Context, rawURL string, maxBytes int64) (string, func(), error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return "", nil, fmt.Errorf("invalid ingest url: %s", rawURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return "", nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("download %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("download %s: status %d", rawURL, resp.StatusCode)
+	}
+	if resp.ContentLength > maxBytes {
+		return "", nil, errIngestTooLarge
+	}
+
+	tmp, err := os.CreateTemp("", "ingest-*"+path.Ext(parsed.Path))
+	if err != nil {
+		return "", nil, err
+	}
+	// Read one byte past the limit so a body without a Content-Length is still caught.
+	written, err := io.Copy(tmp, io.LimitReader(resp.Body, maxBytes+1))
+	if err == nil && written > maxBytes {
+		err = errIngestTooLarge
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		if errors.Is(err, errIngestTooLarge) {
+			return "", nil, err
+		}
+		return "", nil, fmt.Errorf("download %s: %w", rawURL, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", nil, err
+	}
+	cleanup := func() { _ = os.Remove(tmp.Name()) }
+	return tmp.Name(), cleanup, nil
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDownloadIngestFileLimit(t *testing.T) {
+	body := strings.Repeat("x", 64)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/chunked.xml" {
+			// Flushing before writing the body drops the Content-Length.
+			w.(http.Flusher).Flush()
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	for _, name := range []string{"/sized.xml", "/chunked.xml"} {
+		if _, _, err := downloadIngestFile(context.Background(), srv.URL+name, 32); !errors.Is(err, errIngestTooLarge) {
+			t.Fatalf("%s: expected errIngestTooLarge, got %v", name, err)
+		}
+	}
+
+	path, cleanup, err := downloadIngestFile(context.Background(), srv.URL+"/chunked.xml", 64)
+	if err != nil {
+		t.Fatalf("download at the limit: %v", err)
+	}
+	defer cleanup()
+	if data, err := os.ReadFile(path); err != nil || string(data) != body || !strings.HasSuffix(path, ".xml") {
+		t.Fatalf("expected the full body in an .xml file, got %q at %s (%v)", data, path, err)
+	}
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"domain-risk-eval/backend/internal/ai"
+)
+
+// aiBatchWindow bounds how long the batcher waits for more domains before sending a partial batch.
+const aiBatchWindow = 250 * time.Millisecond
+
+type aiBatchRequest struct {
+	ctx   context.Context
+	input ai.ExplanationInput
+	reply chan aiBatchReply
+}
+
+type aiBatchReply struct {
+	decision ai.Decision
+	err      error
+}
+
+// aiBatcher groups explanation requests from the evaluation workers into batch calls. Workers
+// block on Explain while the batcher collects up to size inputs; when a batch fails, each
+// domain is retried individually through the single-domain path. Each batch call takes one
+// slot from acquire, like a single-domain call.
+type aiBatcher struct {
+	explainer ai.BatchExplainer
+	size      int
+	requests  chan aiBatchRequest
+	single    func(ctx context.Context, input ai.ExplanationInput) (ai.Decision, error)
+	acquire   func(ctx context.Context) (func(), error)
+}
+
+func newAIBatcher(explainer ai.BatchExplainer, size int, single func(context.Context, ai.ExplanationInput) (ai.Decision, error), acquire func(context.Context) (func(), error)) *aiBatcher {
+	return &aiBatcher{
+		explainer: explainer,
+		size:      size,
+		requests:  make(chan aiBatchRequest),
+		single:    single,
+		acquire:   acquire,
+	}
+}
+
+// run collects pending requests until ctx is cancelled.
+func (b *aiBatcher) run(ctx context.Context) {
+	for {
+		var first aiBatchRequest
+		select {
+		case <-ctx.Done():
+			return
+		case first = <-b.requests:
+		}
+
+		pending := []aiBatchRequest{first}
+		timer := time.NewTimer(aiBatchWindow)
+	collect:
+		for len(pending) < b.size {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case req := <-b.requests:
+				pending = append(pending, req)
+			case <-timer.C:
+				break collect
+			}
+		}
+		timer.Stop()
+		go b.dispatch(ctx, pending)
+	}
+}
+
+func (b *aiBatcher) dispatch(ctx context.Context, pending []aiBatchRequest) {
+	if len(pending) > 1 {
+		inputs := make([]ai.ExplanationInput, len(pending))
+		for i, req := range pending {
+			inputs[i] = req.input
+		}
+		release, err := b.acquire(ctx)
+		if err != nil {
+			for _, req := range pending {
+				req.reply <- aiBatchReply{err: err}
+			}
+			return
+		}
+		decisions, err := b.explainer.ExplainBatch(ctx, inputs)
+		release()
+		if err == nil {
+			for i, req := range pending {
+				req.reply <- aiBatchReply{decision: decisions[i]}
+			}
+			return
+		}
+		logrus.WithError(err).WithField("size", len(pending)).Warn("ai batch failed; falling back to per-domain calls")
+	}
+	for _, req := range pending {
+		decision, err := b.single(req.ctx, req.input)
+		req.reply <- aiBatchReply{decision: decision, err: err}
+	}
+}
+
+// Explain queues the input for the next batch and waits for its decision.
+func (b *aiBatcher) Explain(ctx context.Context, input ai.ExplanationInput) (ai.Decision, error) {
+	req := aiBatchRequest{ctx: ctx, input: input, reply: make(chan aiBatchReply, 1)}
+	select {
+	case <-ctx.Done():
+		return ai.Decision{}, ctx.Err()
+	case b.requests <- req:
+	}
+	select {
+	case <-ctx.Done():
+		return ai.Decision{}, ctx.Err()
+	case reply := <-req.reply:
+		return reply.decision, reply.err
+	}
+}
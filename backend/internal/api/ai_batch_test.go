@@ -0,0 +1,193 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"domain-risk-eval/backend/internal/ai"
+	"domain-risk-eval/backend/internal/scoring"
+)
+
+// recordingBatchExplainer records the size of every batch call and fails them with err when set.
+type recordingBatchExplainer struct {
+	*ai.FakeExplainer
+	err error
+
+	mu    sync.Mutex
+	sizes []int
+}
+
+func (r *recordingBatchExplainer) ExplainBatch(ctx context.Context, inputs []ai.ExplanationInput) ([]ai.Decision, error) {
+	r.mu.Lock()
+	r.sizes = append(r.sizes, len(inputs))
+	r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.FakeExplainer.ExplainBatch(ctx, inputs)
+}
+
+func (r *recordingBatchExplainer) batchSizes() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int(nil), r.sizes...)
+}
+
+func noopAcquire(context.Context) (func(), error) { return func() {}, nil }
+
+// startBatcher runs a batcher for the test and counts single-domain fallbacks.
+func startBatcher(t *testing.T, explainer *recordingBatchExplainer, size int, acquire func(context.Context) (func(), error)) (*aiBatcher, func() int) {
+	t.Helper()
+	var (
+		mu      sync.Mutex
+		singles int
+	)
+	single := func(ctx context.Context, input ai.ExplanationInput) (ai.Decision, error) {
+		mu.Lock()
+		singles++
+		mu.Unlock()
+		return explainer.Explain(ctx, input)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	batcher := newAIBatcher(explainer, size, single, acquire)
+	go batcher.run(ctx)
+	return batcher, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return singles
+	}
+}
+
+type batchResult struct {
+	domain   string
+	decision ai.Decision
+	err      error
+}
+
+// explainAll sends one request per domain concurrently and waits for every reply.
+func explainAll(t *testing.T, batcher *aiBatcher, domains []string) []batchResult {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	results := make([]batchResult, len(domains))
+	var wg sync.WaitGroup
+	for i, domain := range domains {
+		wg.Add(1)
+		go func(i int, domain string) {
+			defer wg.Done()
+			input := ai.ExplanationInput{Domain: domain, Recommendation: scoring.RecommendationReview}
+			decision, err := batcher.Explain(ctx, input)
+			results[i] = batchResult{domain: domain, decision: decision, err: err}
+		}(i, domain)
+	}
+	wg.Wait()
+	return results
+}
+
+func TestAIBatcherFlushesOnSize(t *testing.T) {
+	explainer := &recordingBatchExplainer{FakeExplainer: ai.NewFakeExplainer()}
+	batcher, singles := startBatcher(t, explainer, 3, noopAcquire)
+
+	domains := []string{"a.com", "b.com", "c.com"}
+	start := time.Now()
+	results := explainAll(t, batcher, domains)
+	if elapsed := time.Since(start); elapsed >= aiBatchWindow {
+		t.Fatalf("expected a full batch to flush before the %s window, took %s", aiBatchWindow, elapsed)
+	}
+	for _, result := range results {
+		if result.err != nil {
+			t.Fatalf("%s: unexpected error %v", result.domain, result.err)
+		}
+		if result.decision.Recommendation != scoring.RecommendationReview {
+			t.Fatalf("%s: expected the echoed recommendation, got %q", result.domain, result.decision.Recommendation)
+		}
+	}
+	if sizes := explainer.batchSizes(); fmt.Sprint(sizes) != "[3]" {
+		t.Fatalf("expected one batch of 3, got %v", sizes)
+	}
+	if n := singles(); n != 0 {
+		t.Fatalf("expected no single-domain calls, got %d", n)
+	}
+}
+
+func TestAIBatcherFlushesOnTimeout(t *testing.T) {
+	explainer := &recordingBatchExplainer{FakeExplainer: ai.NewFakeExplainer()}
+	batcher, singles := startBatcher(t, explainer, 10, noopAcquire)
+
+	start := time.Now()
+	results := explainAll(t, batcher, []string{"a.com", "b.com"})
+	if elapsed := time.Since(start); elapsed < aiBatchWindow {
+		t.Fatalf("expected a partial batch to wait for the %s window, took %s", aiBatchWindow, elapsed)
+	}
+	for _, result := range results {
+		if result.err != nil {
+			t.Fatalf("%s: unexpected error %v", result.domain, result.err)
+		}
+	}
+	if sizes := explainer.batchSizes(); fmt.Sprint(sizes) != "[2]" {
+		t.Fatalf("expected one partial batch of 2, got %v", sizes)
+	}
+
+	// A lone request skips the batch call and goes through the single-domain path.
+	explainAll(t, batcher, []string{"c.com"})
+	if sizes := explainer.batchSizes(); len(sizes) != 1 {
+		t.Fatalf("expected a lone request not to batch, got %v", sizes)
+	}
+	if n := singles(); n != 1 {
+		t.Fatalf("expected one single-domain call, got %d", n)
+	}
+}
+
+func TestAIBatcherErrorFanOut(t *testing.T) {
+	domains := []string{"a.com", "b.com", "c.com"}
+
+	t.Run("acquire error reaches every caller", func(t *testing.T) {
+		errBusy := errors.New("no ai slot")
+		explainer := &recordingBatchExplainer{FakeExplainer: ai.NewFakeExplainer()}
+		acquire := func(context.Context) (func(), error) { return nil, errBusy }
+		batcher, singles := startBatcher(t, explainer, len(domains), acquire)
+
+		for _, result := range explainAll(t, batcher, domains) {
+			if !errors.Is(result.err, errBusy) {
+				t.Fatalf("%s: expected the acquire error, got %v", result.domain, result.err)
+			}
+		}
+		if sizes := explainer.batchSizes(); len(sizes) != 0 {
+			t.Fatalf("expected no batch call without a slot, got %v", sizes)
+		}
+		if n := singles(); n != 0 {
+			t.Fatalf("expected no single-domain fallback, got %d", n)
+		}
+	})
+
+	t.Run("batch error falls back per domain", func(t *testing.T) {
+		explainer := &recordingBatchExplainer{FakeExplainer: ai.NewFakeExplainer(), err: errors.New("bad batch")}
+		explainer.Decide = func(input ai.ExplanationInput) (ai.Decision, error) {
+			if input.Domain == "b.com" {
+				return ai.Decision{}, fmt.Errorf("explain %s", input.Domain)
+			}
+			return ai.EchoDecision(input)
+		}
+		batcher, singles := startBatcher(t, explainer, len(domains), noopAcquire)
+
+		var failed []string
+		for _, result := range explainAll(t, batcher, domains) {
+			if result.err != nil {
+				failed = append(failed, result.err.Error())
+			}
+		}
+		sort.Strings(failed)
+		if fmt.Sprint(failed) != "[explain b.com]" {
+			t.Fatalf("expected only b.com's own error, got %v", failed)
+		}
+		if n := singles(); n != len(domains) {
+			t.Fatalf("expected %d single-domain retries, got %d", len(domains), n)
+		}
+	})
+}
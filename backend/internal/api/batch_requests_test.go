@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"domain-risk-eval/backend/internal/store"
+)
+
+func TestHandleListBatchRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := newOfflineServer(t)
+
+	batch, err := s.db.CreateCSVBatch("history", "", "history.csv")
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+	start := time.Now().Add(-time.Hour)
+	first := &store.BatchRequest{BatchID: batch.ID, Type: "evaluate", Status: "running", StartedAt: start.Add(time.Minute)}
+	second := &store.BatchRequest{BatchID: batch.ID, Type: "evaluate", Status: "running", StartedAt: start}
+	other := &store.BatchRequest{BatchID: batch.ID + 1, Type: "evaluate", Status: "running", StartedAt: start}
+	for _, request := range []*store.BatchRequest{first, second, other} {
+		if err := s.db.CreateBatchRequest(request); err != nil {
+			t.Fatalf("create request: %v", err)
+		}
+	}
+	if err := s.db.UpdateBatchRequest(second.ID, "failed"); err != nil {
+		t.Fatalf("update request: %v", err)
+	}
+
+	list := func(query string) (int, BatchRequestsResponse) {
+		id := strconv.FormatUint(uint64(batch.ID), 10)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/batches/"+id+"/requests"+query, nil)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		s.handleListBatchRequests(c)
+		var resp BatchRequestsResponse
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+		}
+		return w.Code, resp
+	}
+
+	code, resp := list("")
+	if code != http.StatusOK || resp.Total != 2 {
+		t.Fatalf("expected 2 requests, got %d (status %d)", resp.Total, code)
+	}
+	if resp.Items[0].ID != second.ID || resp.Items[1].ID != first.ID {
+		t.Fatalf("expected requests ordered by start time, got %d then %d", resp.Items[0].ID, resp.Items[1].ID)
+	}
+	if resp.Items[0].DurationMs == nil || resp.Items[1].DurationMs != nil {
+		t.Fatalf("expected a duration only for the finished request, got %v and %v", resp.Items[0].DurationMs, resp.Items[1].DurationMs)
+	}
+
+	if _, resp := list("?status=FAILED"); resp.Total != 1 || resp.Items[0].ID != second.ID {
+		t.Fatalf("expected only the failed request, got %+v", resp.Items)
+	}
+	if _, resp := list("?type=retry"); resp.Total != 0 || resp.Items == nil {
+		t.Fatalf("expected an empty list, got %+v", resp)
+	}
+
+	// Re-evaluations are recorded without a batch; those filtered to this batch or to none are
+	// part of its history.
+	reevaluations := map[string]*store.BatchRequest{
+		"this batch":  {Type: "reevaluate", ParamsJSON: `{"batch_id":` + strconv.FormatUint(uint64(batch.ID), 10) + `}`},
+		"all batches": {Type: "reevaluate", ParamsJSON: `{"batch_id":0}`},
+		"other batch": {Type: "reevaluate", ParamsJSON: `{"batch_id":` + strconv.FormatUint(uint64(batch.ID+1), 10) + `}`},
+		"no params":   {Type: "reevaluate"},
+	}
+	for name, request := range reevaluations {
+		request.Status, request.StartedAt = "completed", start.Add(2*time.Minute)
+		if err := s.db.CreateBatchRequest(request); err != nil {
+			t.Fatalf("create %s re-evaluation: %v", name, err)
+		}
+	}
+	_, resp = list("?type=reevaluate")
+	ids := map[uint]bool{}
+	for _, item := range resp.Items {
+		ids[item.ID] = true
+	}
+	if resp.Total != 2 || !ids[reevaluations["this batch"].ID] || !ids[reevaluations["all batches"].ID] {
+		t.Fatalf("expected the batch's and the global re-evaluation, got %+v", resp.Items)
+	}
+
+	_, resp = list("?pageSize=3")
+	if resp.Total != 4 || len(resp.Items) != 3 || !resp.HasNext || resp.Items[0].ID != second.ID {
+		t.Fatalf("expected the first 3 of 4 requests, got total %d, %d items, has_next %v", resp.Total, len(resp.Items), resp.HasNext)
+	}
+	_, resp = list("?page=1&pageSize=3")
+	if resp.Total != 4 || len(resp.Items) != 1 || resp.HasNext || resp.Page != 1 {
+		t.Fatalf("expected the last request on page 1, got total %d, %d items, has_next %v", resp.Total, len(resp.Items), resp.HasNext)
+	}
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"domain-risk-eval/backend/internal/store"
+)
+
+func TestHandleRecomputeBatchStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := newOfflineServer(t)
+
+	prior := &store.Evaluation{Domain: "alpha.com", DomainNormalized: "alpha.com", CreatedAt: time.Now().Add(-time.Hour)}
+	if err := s.db.SaveEvaluation(prior); err != nil {
+		t.Fatalf("save prior evaluation: %v", err)
+	}
+	batch, err := s.db.CreateCSVBatch("drift", "", "drift.csv")
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+	rows := []store.DomainBatch{
+		{BatchID: batch.ID, Domain: "alpha.com", DomainNormalized: "alpha.com", RowIndex: 1},
+		{BatchID: batch.ID, Domain: "beta.com", DomainNormalized: "beta.com", RowIndex: 2},
+		{BatchID: batch.ID, Domain: "Alpha.com", DomainNormalized: "alpha.com", RowIndex: 3},
+		{BatchID: batch.ID, Domain: "gamma.com", DomainNormalized: "gamma.com", RowIndex: 4},
+	}
+	if err := s.db.ReplaceDomainBatch(batch.ID, rows); err != nil {
+		t.Fatalf("store rows: %v", err)
+	}
+	if err := s.db.SaveEvaluation(&store.Evaluation{Domain: "beta.com", DomainNormalized: "beta.com"}); err != nil {
+		t.Fatalf("save evaluation: %v", err)
+	}
+	if err := s.db.UpdateCSVBatchStats(batch.ID, 9, 9, 9, 9, 0); err != nil {
+		t.Fatalf("seed stale stats: %v", err)
+	}
+
+	id := strconv.FormatUint(uint64(batch.ID), 10)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/batches/"+id+"/recompute-stats", nil)
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	s.handleRecomputeBatchStats(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Batch    BatchDTO `json:"batch"`
+		Previous BatchDTO `json:"previous"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	got := resp.Batch
+	if got.RowCount != 4 || got.UniqueDomains != 3 || got.DuplicateRows != 1 || got.ExistingDomains != 1 || got.ProcessedDomains != 2 {
+		t.Fatalf("unexpected stats %+v", got)
+	}
+	if resp.Previous.RowCount != 9 {
+		t.Fatalf("expected previous row count 9, got %d", resp.Previous.RowCount)
+	}
+}
+
+func TestHandleResetBatchRequiresForceForSharedDomains(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := newOfflineServer(t)
+
+	var batches []*store.CSVBatch
+	for _, name := range []string{"first", "second"} {
+		batch, err := s.db.CreateCSVBatch(name, "", name+".csv")
+		if err != nil {
+			t.Fatalf("create batch: %v", err)
+		}
+		if err := s.db.ReplaceDomainBatch(batch.ID, []store.DomainBatch{{BatchID: batch.ID, Domain: "alpha.com", DomainNormalized: "alpha.com", RowIndex: 1}}); err != nil {
+			t.Fatalf("store rows: %v", err)
+		}
+		batches = append(batches, batch)
+	}
+	if err := s.db.SaveEvaluation(&store.Evaluation{Domain: "alpha.com", DomainNormalized: "alpha.com"}); err != nil {
+		t.Fatalf("save evaluation: %v", err)
+	}
+
+	reset := func(query string) *httptest.ResponseRecorder {
+		id := strconv.FormatUint(uint64(batches[0].ID), 10)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/batches/"+id+"/reset"+query, nil)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		s.handleResetBatch(c)
+		return w
+	}
+
+	w := reset("")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 got %d: %s", w.Code, w.Body.String())
+	}
+	var conflict struct {
+		AffectedBatches []uint `json:"affected_batches"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &conflict); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(conflict.AffectedBatches) != 1 || conflict.AffectedBatches[0] != batches[1].ID {
+		t.Fatalf("expected batch %d to be reported, got %v", batches[1].ID, conflict.AffectedBatches)
+	}
+	if count, _ := s.db.CountBatchResults(batches[1].ID); count != 1 {
+		t.Fatalf("expected the shared evaluation to survive, got %d results", count)
+	}
+
+	if w := reset("?force=true"); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d: %s", w.Code, w.Body.String())
+	}
+	if count, _ := s.db.CountBatchResults(batches[1].ID); count != 0 {
+		t.Fatalf("expected the forced reset to clear the shared evaluation, got %d results", count)
+	}
+}
@@ -0,0 +1,25 @@
+package api
+
+import (
+	"bufio"
+	"io"
+
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// decodeCSV returns a UTF-8 view of an uploaded CSV. A UTF-8, UTF-16LE, or UTF-16BE byte order
+// mark selects the encoding and is dropped, so header detection never sees it. Excel's "Unicode
+// Text" exports are UTF-16 and always carry one, but UTF-16 without a BOM is also recognised
+// when the first two characters are ASCII. Anything else is passed through unchanged.
+func decodeCSV(r io.Reader) io.Reader {
+	buffered := bufio.NewReader(r)
+	head, _ := buffered.Peek(4)
+	switch {
+	case len(head) == 4 && head[0] != 0 && head[1] == 0 && head[2] != 0 && head[3] == 0:
+		return transform.NewReader(buffered, unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder())
+	case len(head) == 4 && head[0] == 0 && head[1] != 0 && head[2] == 0 && head[3] != 0:
+		return transform.NewReader(buffered, unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewDecoder())
+	}
+	return transform.NewReader(buffered, unicode.BOMOverride(transform.Nop))
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// eachCSVRecord reads the uploaded CSV at path and calls row for each data record with the
+// domain column and the record's line in the file. The file is decoded by decodeCSV, rows may
+// be ragged, leading spaces are trimmed, and blank lines and lines starting with comment (0 for
+// none) are skipped. The first record is a header when detectDomainColumn finds a domain column
+// in it: header is then called with it and the column, and is not called otherwise, leaving the
+// domain column 0. An error from either callback stops the read and is returned as is.
+func eachCSVRecord(path string, comment rune, header func(record []string, domainCol int) error, row func(record []string, domainCol, line int) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(decodeCSV(f))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = comment
+
+	domainCol := 0
+	first := true
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read csv: %w", err)
+		}
+		if len(record) == 0 {
+			continue
+		}
+		if first {
+			first = false
+			if col := detectDomainColumn(record); col >= 0 {
+				domainCol = col
+				if err := header(record, col); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		line, _ := reader.FieldPos(0)
+		if err := row(record, domainCol, line); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package api
+
+import (
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/encoding/unicode"
+
+	"domain-risk-eval/backend/internal/match"
+	"domain-risk-eval/backend/internal/store"
+)
+
+func TestParseDomainCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		comment rune
+		domains []string
+		header  string
+		skipped int
+	}{
+		{"commented", "# exported 2024-01-01\ndomain,price\nalpha.com,10\n# beta.com,20\ngamma.com,30\n", '#', []string{"alpha.com", "gamma.com"}, "domain", 0},
+		{"comments disabled", "#alpha.com\nbeta.com\n", 0, []string{"#alpha.com", "beta.com"}, "", 0},
+		{"custom comment", ";note\nalpha.com\n", ';', []string{"alpha.com"}, "", 0},
+		{"quoted", "\"Price, USD\",\"Domain\"\n\"1,000\",\"alpha.com\"\n2000,\" beta.com \"\n3,\"gam\"\"ma.com\"\n", '#', []string{"alpha.com", "beta.com", "gam\"ma.com"}, "Domain", 0},
+		{"ragged", "id,domain\n1,alpha.com\n2\n3,beta.com,extra\n", '#', []string{"alpha.com", "beta.com"}, "domain", 1},
+		{"trailing empty column", "domain,\nalpha.com,\nbeta.com,,\n,\n", '#', []string{"alpha.com", "beta.com"}, "domain", 1},
+		{"no header", "alpha.com,1\nbeta.com\n", '#', []string{"alpha.com", "beta.com"}, "", 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "domains.csv")
+			if err := os.WriteFile(path, []byte(tc.body), 0o600); err != nil {
+				t.Fatalf("write csv: %v", err)
+			}
+			parsed, err := parseDomainCSV(path, 0, tc.comment)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			var got []string
+			for _, row := range parsed.domainBatches {
+				got = append(got, row.Domain)
+			}
+			if !reflect.DeepEqual(got, tc.domains) {
+				t.Fatalf("expected %q got %q", tc.domains, got)
+			}
+			if parsed.domainHeader != tc.header {
+				t.Fatalf("expected header %q got %q", tc.header, parsed.domainHeader)
+			}
+			if parsed.skippedRows != tc.skipped {
+				t.Fatalf("expected %d skipped rows got %d", tc.skipped, parsed.skippedRows)
+			}
+		})
+	}
+}
+
+func TestParseDomainCSVReportsStrayQuotes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "domains.csv")
+	if err := os.WriteFile(path, []byte("domain\n\"a.com\nb.com\n"), 0o600); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	_, err := parseDomainCSV(path, 0, defaultCSVComment)
+	var parseErr *csv.ParseError
+	if !errors.As(err, &parseErr) || parseErr.StartLine != 2 {
+		t.Fatalf("expected a parse error starting on line 2, got %v", err)
+	}
+}
+
+func TestParseDomainCSVCollapsesPunycode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "domains.csv")
+	if err := os.WriteFile(path, []byte("domain\ncafé.com\nXN--CAF-DMA.com\ncafe.com\n"), 0o600); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	parsed, err := parseDomainCSV(path, 0, defaultCSVComment)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if parsed.rowCount != 3 || len(parsed.domainModels) != 2 || parsed.duplicateRows != 1 {
+		t.Fatalf("expected 3 rows, 2 unique, 1 duplicate; got %d, %d, %d", parsed.rowCount, len(parsed.domainModels), parsed.duplicateRows)
+	}
+	if key := parsed.domainBatches[1].DomainNormalized; key != "café.com" {
+		t.Fatalf("expected punycode row keyed as café.com, got %q", key)
+	}
+}
+
+func TestOpenRekeysPunycodeDomains(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rekey.sqlite")
+	legacy, err := store.Open(path, true, store.Config{})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	for _, domain := range []string{"xn--caf-dma.com", "xn--nave-6pa.com", "naïve.com"} {
+		if err := legacy.SaveEvaluation(&store.Evaluation{Domain: domain}); err != nil {
+			t.Fatalf("save %s: %v", domain, err)
+		}
+	}
+	batch, err := legacy.CreateCSVBatch("idn", "", "idn.csv")
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+	if err := legacy.ReplaceDomainBatch(batch.ID, []store.DomainBatch{{BatchID: batch.ID, Domain: "xn--caf-dma.com", DomainNormalized: "xn--caf-dma.com", RowIndex: 1}}); err != nil {
+		t.Fatalf("store rows: %v", err)
+	}
+	legacy.Close()
+
+	db, err := store.Open(path, true, store.Config{DecodeDomain: match.DecodePunycode})
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer db.Close()
+	var keys []string
+	if err := db.GORM().Model(&store.Evaluation{}).Order("domain_normalized").Pluck("domain_normalized", &keys).Error; err != nil {
+		t.Fatalf("list evaluations: %v", err)
+	}
+	if want := []string{"café.com", "naïve.com"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("expected evaluation keys %v, got %v", want, keys)
+	}
+	rows, err := db.ListBatchDomainsForEval(batch.ID, 0, 10)
+	if err != nil {
+		t.Fatalf("list batch: %v", err)
+	}
+	if len(rows) != 1 || rows[0].DomainNormalized != "café.com" || !rows[0].HasResult {
+		t.Fatalf("expected batch row rekeyed to café.com with a result, got %+v", rows)
+	}
+}
+
+func TestParseDomainCSVEncodings(t *testing.T) {
+	const body = "Domain,Price\ncafé.com,10\nbeta.com,20\n"
+	utf16 := func(endian unicode.Endianness, bom unicode.BOMPolicy) []byte {
+		encoded, err := unicode.UTF16(endian, bom).NewEncoder().Bytes([]byte(body))
+		if err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+		return encoded
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"utf-8", []byte(body)},
+		{"utf-8 bom", append([]byte("\ufeff"), body...)},
+		{"utf-16le bom", utf16(unicode.LittleEndian, unicode.UseBOM)},
+		{"utf-16be bom", utf16(unicode.BigEndian, unicode.UseBOM)},
+		{"utf-16le no bom", utf16(unicode.LittleEndian, unicode.IgnoreBOM)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "domains.csv")
+			if err := os.WriteFile(path, tc.data, 0o600); err != nil {
+				t.Fatalf("write csv: %v", err)
+			}
+			parsed, err := parseDomainCSV(path, 0, defaultCSVComment)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			var got []string
+			for _, row := range parsed.domainBatches {
+				got = append(got, row.Domain)
+			}
+			if want := []string{"café.com", "beta.com"}; !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected %q got %q", want, got)
+			}
+			if parsed.domainHeader != "Domain" {
+				t.Fatalf("expected header %q got %q", "Domain", parsed.domainHeader)
+			}
+		})
+	}
+}
+
+func TestParseCSVComment(t *testing.T) {
+	tests := []struct {
+		value string
+		want  rune
+		ok    bool
+	}{
+		{"", '#', true},
+		{"none", 0, true},
+		{";", ';', true},
+		{",", 0, false},
+		{"##", 0, false},
+	}
+	for _, tc := range tests {
+		got, err := parseCSVComment(tc.value)
+		if (err == nil) != tc.ok || got != tc.want {
+			t.Fatalf("%q: expected %q (ok %v) got %q (%v)", tc.value, tc.want, tc.ok, got, err)
+		}
+	}
+}
+
+func TestOpenMarksSeparatesAndProtectsMarks(t *testing.T) {
+	dir := t.TempDir()
+	marksPath := filepath.Join(dir, "marks.sqlite")
+
+	// Build the marks database the way a prebuilt artifact would be produced.
+	builder, err := store.Open(filepath.Join(dir, "builder.sqlite"), true, store.Config{})
+	if err != nil {
+		t.Fatalf("open builder: %v", err)
+	}
+	if err := builder.OpenMarks(marksPath, false, true); err != nil {
+		t.Fatalf("open writable marks: %v", err)
+	}
+	if err := builder.UpsertMark(&store.Mark{Serial: "1", Mark: "Zorblax", MarkNormalized: "zorblax", MarkNoSpaces: "zorblax"}); err != nil {
+		t.Fatalf("upsert mark: %v", err)
+	}
+	var inMain int64
+	if err := builder.GORM().Model(&store.Mark{}).Count(&inMain).Error; err != nil || inMain != 0 {
+		t.Fatalf("expected no marks in the main database, got %d (%v)", inMain, err)
+	}
+	builder.Close()
+
+	db, err := store.Open(filepath.Join(dir, "main.sqlite"), true, store.Config{})
+	if err != nil {
+		t.Fatalf("open main: %v", err)
+	}
+	defer db.Close()
+	if err := db.OpenMarks(marksPath, true, true); err != nil {
+		t.Fatalf("open read-only marks: %v", err)
+	}
+	if count, err := db.CountMarks(); err != nil || count != 1 {
+		t.Fatalf("expected 1 mark from the marks database, got %d (%v)", count, err)
+	}
+	if !db.MarksReadOnly() {
+		t.Fatal("expected MarksReadOnly")
+	}
+	if err := db.UpsertMark(&store.Mark{Serial: "2", Mark: "Quibbly"}); !errors.Is(err, store.ErrMarksReadOnly) {
+		t.Fatalf("expected ErrMarksReadOnly from UpsertMark, got %v", err)
+	}
+	if err := db.ReplacePopularMarks(nil); !errors.Is(err, store.ErrMarksReadOnly) {
+		t.Fatalf("expected ErrMarksReadOnly from ReplacePopularMarks, got %v", err)
+	}
+
+	empty := filepath.Join(dir, "empty.sqlite")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatalf("write empty marks file: %v", err)
+	}
+	other, err := store.Open(filepath.Join(dir, "other.sqlite"), true, store.Config{})
+	if err != nil {
+		t.Fatalf("open other: %v", err)
+	}
+	defer other.Close()
+	if err := other.OpenMarks(empty, true, true); err == nil {
+		t.Fatal("expected a read-only marks database without tables to be rejected")
+	}
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"domain-risk-eval/backend/internal/ai"
+	"domain-risk-eval/backend/internal/commercial"
+	"domain-risk-eval/backend/internal/match"
+	"domain-risk-eval/backend/internal/scoring"
+	"domain-risk-eval/backend/internal/store"
+	"domain-risk-eval/backend/internal/usp"
+)
+
+// NormalizeResponse exposes how a domain string is normalized and tokenized for matching.
+type NormalizeResponse struct {
+	Original        string   `json:"original"`
+	Host            string   `json:"host"`
+	Core            string   `json:"core"`
+	BrandToken      string   `json:"brand_token"`
+	Tokens          []string `json:"tokens"`
+	AltSplits       []string `json:"alt_splits"`
+	SecondLevel     string   `json:"second_level"`
+	TopLevel        string   `json:"top_level"`
+	SecondLevelKey  string   `json:"second_level_key"`
+	CollectedTokens []string `json:"collected_tokens"`
+	Registrable     string   `json:"registrable"`
+	Subdomains      []string `json:"subdomains,omitempty"`
+	Path            string   `json:"path,omitempty"`
+}
+
+// normalizeDebug builds the normalization preview shared by the debug endpoints.
+func normalizeDebug(domain string) (match.DomainProfile, NormalizeResponse) {
+	profile := match.NormalizeDomain(domain)
+	secondLevel, topLevel := splitDomainParts(profile.Host)
+	return profile, NormalizeResponse{
+		Original:        profile.Original,
+		Host:            profile.Host,
+		Core:            profile.Core,
+		BrandToken:      profile.BrandToken,
+		Tokens:          profile.Tokens,
+		AltSplits:       profile.AltSplits,
+		SecondLevel:     secondLevel,
+		TopLevel:        topLevel,
+		SecondLevelKey:  secondLevelToken(profile),
+		CollectedTokens: collectDomainTokens(profile),
+		Registrable:     profile.Registrable,
+		Subdomains:      profile.Subdomains,
+		Path:            profile.Path,
+	}
+}
+
+func (s *Server) handleDebugNormalize(c *gin.Context) {
+	domain := strings.TrimSpace(c.Query("domain"))
+	if domain == "" {
+		s.renderError(c, http.StatusBadRequest, errors.New("domain is required"))
+		return
+	}
+	_, resp := normalizeDebug(domain)
+	c.JSON(http.StatusOK, resp)
+}
+
+// DebugEvaluateRequest selects the domain and stages for a traced, unpersisted evaluation.
+type DebugEvaluateRequest struct {
+	Domain         string `json:"domain"`
+	SkipVice       bool   `json:"skip_vice"`
+	SkipUSPTO      bool   `json:"skip_uspto"`
+	SkipCommercial bool   `json:"skip_commercial"`
+	SkipAI         bool   `json:"skip_ai"`
+}
+
+// EvaluationTrace records the intermediate values of one pass through evaluateDomain.
+type EvaluationTrace struct {
+	Normalization      NormalizeResponse        `json:"normalization"`
+	HeuristicTrademark scoring.TrademarkResult  `json:"heuristic_trademark"`
+	USPTOChecked       bool                     `json:"uspto_checked"`
+	USPTO              *usp.LookupResult        `json:"uspto,omitempty"`
+	Trademark          scoring.TrademarkResult  `json:"trademark"`
+	CloseMatches       []string                 `json:"close_matches"`
+	Vice               scoring.ViceResult       `json:"vice"`
+	Randomness         scoring.RandomnessResult `json:"randomness"`
+	Spam               match.SpamSignal         `json:"spam"`
+	SubdomainBrand     *scoring.SubdomainBrand  `json:"subdomain_brand,omitempty"`
+	Commercial         *commercial.Match        `json:"commercial,omitempty"`
+	CommercialOverride bool                     `json:"commercial_override"`
+	// RecommendationBeforeAI is the heuristic recommendation handed to the explainer.
+	RecommendationBeforeAI scoring.Recommendation `json:"recommendation_before_ai"`
+	// DecisionSource is "ai", "template" (AI skipped or disabled), or "fallback" (AI failed).
+	DecisionSource string       `json:"decision_source"`
+	AIError        string       `json:"ai_error,omitempty"`
+	AIDecision     *ai.Decision `json:"ai_decision,omitempty"`
+	// RecommendationAfterAI is the final recommendation, after AI overrides and review routing.
+	RecommendationAfterAI string        `json:"recommendation_after_ai"`
+	Evaluation            EvaluationDTO `json:"evaluation"`
+}
+
+// handleDebugEvaluate runs the full pipeline for one domain and returns the trace. Nothing is
+// persisted, and the run does not touch the active job's caches.
+func (s *Server) handleDebugEvaluate(c *gin.Context) {
+	var req DebugEvaluateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+	domain := strings.TrimSpace(req.Domain)
+	if domain == "" {
+		s.renderError(c, http.StatusBadRequest, errors.New("domain is required"))
+		return
+	}
+
+	scorer, marks, err := s.loadTrademarkScorer()
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	trace := &EvaluationTrace{}
+	_, trace.Normalization = normalizeDebug(domain)
+	opts := newEvaluationOptions(EvaluateRequest{
+		SkipVice:       req.SkipVice,
+		SkipUSPTO:      req.SkipUSPTO,
+		SkipCommercial: req.SkipCommercial,
+		SkipAI:         req.SkipAI,
+	})
+	opts.trace = trace
+
+	task := store.BatchDomain{Domain: domain, DomainNormalized: strings.ToLower(domain)}
+	res := s.evaluateDomain(c.Request.Context(), task, scorer, marks, 1, make(map[string]usp.LookupResult), nil, opts)
+	if errors.Is(res.Err, match.ErrSkippable) {
+		s.renderError(c, http.StatusUnprocessableEntity, res.Err)
+		return
+	}
+	if res.Err != nil {
+		s.renderError(c, http.StatusInternalServerError, res.Err)
+		return
+	}
+	trace.Evaluation = FromModel(res.Evaluation)
+	trace.RecommendationAfterAI = res.Evaluation.OverallRecommendation
+	c.JSON(http.StatusOK, trace)
+}
@@ -8,7 +8,8 @@ import (
 	"domain-risk-eval/backend/internal/store"
 )
 
-// UploadResponse reports batch statistics after processing a CSV upload.
+// UploadResponse reports batch statistics after processing a CSV upload. For uploads merged into
+// an existing batch the statistics cover the whole batch and AddedDomains counts the new domains.
 type UploadResponse struct {
 	BatchID         uint   `json:"batch_id"`
 	BatchName       string `json:"batch_name"`
@@ -19,21 +20,179 @@ type UploadResponse struct {
 	DuplicateRows   int    `json:"duplicate_rows"`
 	Processed       int    `json:"processed_domains"`
 	MarksCount      int    `json:"marks_count"`
+	Merged          bool   `json:"merged"`
+	AddedDomains    int    `json:"added_domains"`
+	// Duplicates reports which raw rows collapsed onto the same normalized domain within the
+	// uploaded file; DuplicatesTruncated is set when more groups existed than were returned.
+	Duplicates          []DuplicateGroup `json:"duplicates"`
+	DuplicatesTruncated bool             `json:"duplicates_truncated"`
+	// Replayed is set when an idempotent retry returned the batch of an earlier upload.
+	Replayed bool `json:"replayed"`
+	// NewDomains and KnownDomains split the unique domains of this upload by whether any earlier
+	// upload already stored them, regardless of batch or evaluation. Replays report zero.
+	NewDomains   int `json:"new_domains"`
+	KnownDomains int `json:"known_domains"`
+	// SkippedRows counts CSV rows without a value in the domain column.
+	SkippedRows int `json:"skipped_rows"`
 }
 
-// EvaluateRequest controls pagination for evaluation runs.
+// UploadValidationResponse previews how /api/upload would parse a CSV. DomainColumn is the
+// zero-based column read for domains and DomainHeader its header cell, empty when the file has
+// no recognised header. Unscoreable counts unique domains evaluation would skip.
+type UploadValidationResponse struct {
+	Filename            string           `json:"filename"`
+	RowCount            int              `json:"row_count"`
+	UniqueDomains       int              `json:"unique_domains"`
+	DuplicateRows       int              `json:"duplicate_rows"`
+	DomainColumn        int              `json:"domain_column"`
+	DomainHeader        string           `json:"domain_header"`
+	Sample              []string         `json:"sample"`
+	Unscoreable         int              `json:"unscoreable"`
+	Duplicates          []DuplicateGroup `json:"duplicates"`
+	DuplicatesTruncated bool             `json:"duplicates_truncated"`
+	SkippedRows         int              `json:"skipped_rows"`
+}
+
+// DuplicateGroup lists the rows of an upload that normalized to the same domain. Count covers
+// every row even when Variants is capped.
+type DuplicateGroup struct {
+	Normalized string             `json:"normalized"`
+	Count      int                `json:"count"`
+	Variants   []DuplicateVariant `json:"variants"`
+}
+
+// DuplicateVariant is one raw CSV value and its 1-based data row.
+type DuplicateVariant struct {
+	Row    int    `json:"row"`
+	Domain string `json:"domain"`
+}
+
+// UploadForm holds the text fields of a domain CSV upload. BatchName and OwnerName may be omitted
+// when BatchID names a batch to merge into.
+type UploadForm struct {
+	BatchID   uint   `form:"batch_id"`
+	BatchName string `form:"batch_name" binding:"required_without=BatchID"`
+	OwnerName string `form:"owner_name" binding:"required_without=BatchID"`
+}
+
+// EvaluateRequest controls pagination for evaluation runs. The skip flags disable individual
+// scoring stages for targeted re-runs; every stage runs by default. DedupeNarratives regenerates
+// AI narratives that closely repeat recent ones and flags any that remain repetitive. Prewarm
+// resolves the batch's USPTO and commercial lookups before evaluating. The binding tags are
+// checked on every request; violations are reported per field with 422.
 type EvaluateRequest struct {
-	BatchID uint `json:"batch_id"`
-	Limit   int  `json:"limit"`
-	Offset  int  `json:"offset"`
-	Resume  bool `json:"resume"`
-	Force   bool `json:"force"`
+	BatchID          uint `json:"batch_id" binding:"required"`
+	Limit            int  `json:"limit" binding:"gte=0"`
+	Offset           int  `json:"offset" binding:"gte=0"`
+	Resume           bool `json:"resume"`
+	Force            bool `json:"force"`
+	SkipVice         bool `json:"skip_vice"`
+	SkipUSPTO        bool `json:"skip_uspto"`
+	SkipCommercial   bool `json:"skip_commercial"`
+	SkipAI           bool `json:"skip_ai"`
+	DedupeNarratives bool `json:"dedupe_narratives"`
+	Prewarm          bool `json:"prewarm"`
+	// ReuseGlobal emits the stored evaluation of any domain already scored by another batch
+	// instead of re-scoring it. Force disables reuse.
+	ReuseGlobal bool `json:"reuse_global"`
+	// CustomMarks and MarkOwner add marks for this job only, layered over the shared trademark
+	// index: CustomMarks lists them by name and scores them as fanciful, and MarkOwner pulls in
+	// every stored mark of that exact owner (up to maxOwnerMarks), keeping its classification.
+	CustomMarks []string `json:"custom_marks" binding:"max=500,dive,max=256"`
+	MarkOwner   string   `json:"mark_owner" binding:"max=256"`
+}
+
+// ReevaluateRequest selects stored evaluations with the filters GET /results accepts and re-scores
+// those domains as a job. The remaining fields mirror EvaluateRequest; stored evaluations are
+// always replaced, never resumed or reused.
+type ReevaluateRequest struct {
+	Query              string     `json:"q" binding:"max=256"`
+	MinScore           int        `json:"min_score" binding:"gte=0"`
+	MinViceScore       int        `json:"min_vice_score" binding:"gte=0"`
+	TLD                string     `json:"tld" binding:"max=63"`
+	Recommendation     string     `json:"recommendation"`
+	Recommendations    []string   `json:"recommendations" binding:"max=10"`
+	ViceCategory       string     `json:"vice_category" binding:"max=128"`
+	BatchID            uint       `json:"batch_id"`
+	MinCommercialPrice float64    `json:"min_commercial_price" binding:"gte=0"`
+	CommercialOverride *bool      `json:"commercial_override"`
+	UpdatedSince       *time.Time `json:"updated_since"`
+
+	Limit            int      `json:"limit" binding:"gte=0"`
+	SkipVice         bool     `json:"skip_vice"`
+	SkipUSPTO        bool     `json:"skip_uspto"`
+	SkipCommercial   bool     `json:"skip_commercial"`
+	SkipAI           bool     `json:"skip_ai"`
+	DedupeNarratives bool     `json:"dedupe_narratives"`
+	Prewarm          bool     `json:"prewarm"`
+	CustomMarks      []string `json:"custom_marks" binding:"max=500,dive,max=256"`
+	MarkOwner        string   `json:"mark_owner" binding:"max=256"`
+}
+
+// GroundTruthReport compares a batch's stored recommendations with analyst labels.
+// Confusion[expected][actual] counts labeled domains; Unevaluated counts labels with no stored
+// evaluation in the batch, and DuplicateLabels counts repeated domains (the first label wins).
+type GroundTruthReport struct {
+	BatchID             uint                      `json:"batch_id"`
+	Labeled             int                       `json:"labeled"`
+	Compared            int                       `json:"compared"`
+	Correct             int                       `json:"correct"`
+	Accuracy            *float64                  `json:"accuracy"`
+	Unevaluated         int                       `json:"unevaluated"`
+	DuplicateLabels     int                       `json:"duplicate_labels"`
+	Confusion           map[string]map[string]int `json:"confusion"`
+	Classes             []ClassMetrics            `json:"classes"`
+	Mismatches          []GroundTruthMismatch     `json:"mismatches"`
+	MismatchesTruncated bool                      `json:"mismatches_truncated"`
+}
+
+// ClassMetrics holds precision and recall for one recommendation; either is null when nothing
+// was predicted as, or labeled as, that recommendation.
+type ClassMetrics struct {
+	Recommendation string   `json:"recommendation"`
+	Support        int      `json:"support"`
+	Predicted      int      `json:"predicted"`
+	TruePositives  int      `json:"true_positives"`
+	Precision      *float64 `json:"precision"`
+	Recall         *float64 `json:"recall"`
+}
+
+// GroundTruthMismatch is a labeled domain whose stored recommendation differs from its label. Row
+// is the domain's line in the labels file.
+type GroundTruthMismatch struct {
+	Domain         string `json:"domain"`
+	Row            int    `json:"row"`
+	Expected       string `json:"expected"`
+	Actual         string `json:"actual"`
+	TrademarkScore int    `json:"trademark_score"`
+	ViceScore      int    `json:"vice_score"`
 }
 
 // EvaluateResponse holds evaluation items and totals.
 type EvaluateResponse struct {
-	Items []EvaluationDTO `json:"items"`
-	Total int64           `json:"total"`
+	Items    []EvaluationDTO `json:"items"`
+	Total    int64           `json:"total"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"page_size"`
+	HasNext  bool            `json:"has_next"`
+}
+
+// SkippedDomainDTO is a batch row evaluation skipped because it could not be scored.
+type SkippedDomainDTO struct {
+	Domain    string    `json:"domain"`
+	RowIndex  int       `json:"row_index"`
+	Reason    string    `json:"reason"`
+	SkippedAt time.Time `json:"skipped_at"`
+}
+
+// SkippedDomainsResponse is a page of a batch's skipped rows.
+type SkippedDomainsResponse struct {
+	BatchID  uint               `json:"batch_id"`
+	Items    []SkippedDomainDTO `json:"items"`
+	Total    int64              `json:"total"`
+	Page     int                `json:"page"`
+	PageSize int                `json:"page_size"`
+	HasNext  bool               `json:"has_next"`
 }
 
 // StartEvaluationResponse describes the asynchronous evaluation kickoff payload.
@@ -41,6 +200,7 @@ type StartEvaluationResponse struct {
 	JobID     string    `json:"job_id"`
 	BatchID   uint      `json:"batch_id"`
 	RequestID uint      `json:"request_id"`
+	RetryOf   uint      `json:"retry_of,omitempty"`
 	Total     int64     `json:"total"`
 	StartedAt time.Time `json:"started_at"`
 }
@@ -52,17 +212,35 @@ type EvaluationDTO struct {
 	TrademarkScore        int       `json:"trademark_score"`
 	TrademarkType         string    `json:"trademark_type"`
 	MatchedTrademark      string    `json:"matched_trademark"`
+	MatchedOwner          string    `json:"matched_owner"`
+	MatchedSerial         string    `json:"matched_serial,omitempty"`
+	MatchedRegistration   string    `json:"matched_registration,omitempty"`
+	TrademarkSource       string    `json:"trademark_source"`
 	TrademarkConfidence   float64   `json:"trademark_confidence"`
+	TrademarkConflict     bool      `json:"trademark_conflict"`
 	ViceScore             int       `json:"vice_score"`
 	ViceCategories        []string  `json:"vice_categories"`
 	ViceConfidence        float64   `json:"vice_confidence"`
 	OverallRecommendation string    `json:"overall_recommendation"`
 	Confidence            float64   `json:"confidence"`
 	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
 	Explanation           string    `json:"explanation"`
 	CommercialOverride    bool      `json:"commercial_override"`
 	CommercialSource      string    `json:"commercial_source"`
-	CommercialSimilarity  float64   `json:"commercial_similarity"`
+	// CommercialMatch is the comparable sale behind CommercialSource, including the matched SLD;
+	// null when none matched or the row predates it.
+	CommercialMatch      *store.CommercialMatch `json:"commercial_match"`
+	CommercialSimilarity float64                `json:"commercial_similarity"`
+	CommercialPrice      float64                `json:"commercial_price"`
+	Reasons              []string               `json:"reasons"`
+	// CloseMatches lists the near-miss trademarks considered alongside the matched one.
+	CloseMatches []string `json:"close_matches"`
+	// ProcessingMs is the scoring time of the domain; LookupMs and AIMs are the parts spent on
+	// the USPTO lookup and the AI call, null unless timing persistence was enabled.
+	ProcessingMs int64  `json:"processing_ms"`
+	LookupMs     *int64 `json:"lookup_ms"`
+	AIMs         *int64 `json:"ai_ms"`
 }
 
 // BatchDTO represents metadata for an uploaded CSV dataset.
@@ -78,12 +256,68 @@ type BatchDTO struct {
 	ProcessedDomains int        `json:"processed_domains"`
 	CreatedAt        time.Time  `json:"created_at"`
 	LastEvaluatedAt  *time.Time `json:"last_evaluated_at"`
+	// LastRun is the most recent evaluation request with its summary; only set by GET
+	// /api/batches/:id.
+	LastRun *BatchRequestDTO `json:"last_run,omitempty"`
+}
+
+// MarkDTO is the API representation of an ingested trademark.
+type MarkDTO struct {
+	Serial         string            `json:"serial"`
+	Registration   string            `json:"registration"`
+	Mark           string            `json:"mark"`
+	MarkNormalized string            `json:"mark_normalized"`
+	Owner          string            `json:"owner"`
+	Owners         []store.MarkOwner `json:"owners"`
+	Classes        []string          `json:"classes"`
+	IsFanciful     bool              `json:"is_fanciful"`
+	StatusCode     string            `json:"status_code,omitempty"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// MarksResponse is the paginated response for mark searches.
+type MarksResponse struct {
+	Items []MarkDTO `json:"items"`
+	Total int64     `json:"total"`
+}
+
+// MarkFromModel converts a store.Mark into its DTO.
+func MarkFromModel(m store.Mark) MarkDTO {
+	return MarkDTO{
+		Serial:         m.Serial,
+		Registration:   m.Registration,
+		Mark:           m.Mark,
+		MarkNormalized: m.MarkNormalized,
+		Owner:          m.Owner,
+		Owners:         m.Owners(),
+		Classes:        m.Classes(),
+		IsFanciful:     m.IsFanciful,
+		StatusCode:     m.StatusCode,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+// PopularMarkDTO is one aggregated mark usage count from the popular_marks table.
+type PopularMarkDTO struct {
+	Normalized string    `json:"normalized"`
+	Mark       string    `json:"mark"`
+	Total      int       `json:"total"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// PopularMarksResponse lists the most frequent popular marks, highest total first.
+type PopularMarksResponse struct {
+	Items []PopularMarkDTO `json:"items"`
+	Limit int              `json:"limit"`
 }
 
 // BatchesResponse is the paginated response for CSV batches.
 type BatchesResponse struct {
-	Items []BatchDTO `json:"items"`
-	Total int64      `json:"total"`
+	Items    []BatchDTO `json:"items"`
+	Total    int64      `json:"total"`
+	Page     int        `json:"page"`
+	PageSize int        `json:"page_size"`
+	HasNext  bool       `json:"has_next"`
 }
 
 // BatchRequestDTO represents evaluation request tracking metadata.
@@ -95,6 +329,21 @@ type BatchRequestDTO struct {
 	JobID      string     `json:"job_id"`
 	StartedAt  time.Time  `json:"started_at"`
 	FinishedAt *time.Time `json:"finished_at"`
+	// DurationMs is the wall time from start to finish, or the recorded run duration for
+	// cancelled and interrupted requests; null while the request is running.
+	DurationMs *int64            `json:"duration_ms"`
+	Summary    *store.RunSummary `json:"summary,omitempty"`
+	RetryOf    *uint             `json:"retry_of,omitempty"`
+}
+
+// BatchRequestsResponse is a page of a batch's evaluation request history, oldest first.
+type BatchRequestsResponse struct {
+	BatchID  uint              `json:"batch_id"`
+	Items    []BatchRequestDTO `json:"items"`
+	Total    int64             `json:"total"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"page_size"`
+	HasNext  bool              `json:"has_next"`
 }
 
 // FromModel converts a store.Evaluation into the DTO representation.
@@ -105,17 +354,30 @@ func FromModel(e store.Evaluation) EvaluationDTO {
 		TrademarkScore:        e.TrademarkScore,
 		TrademarkType:         e.TrademarkType,
 		MatchedTrademark:      e.MatchedTrademark,
+		MatchedOwner:          e.MatchedOwner,
+		MatchedSerial:         e.MatchedSerial,
+		MatchedRegistration:   e.MatchedRegistration,
+		TrademarkSource:       e.TrademarkSource,
 		TrademarkConfidence:   round2(e.TrademarkConfidence),
+		TrademarkConflict:     e.TrademarkConflict,
 		ViceScore:             e.ViceScore,
 		ViceCategories:        e.ViceCategories(),
 		ViceConfidence:        round2(e.ViceConfidence),
 		OverallRecommendation: e.OverallRecommendation,
 		Confidence:            round2(minFloat(e.TrademarkConfidence, e.ViceConfidence)),
 		CreatedAt:             e.CreatedAt,
+		UpdatedAt:             e.UpdatedAt,
 		Explanation:           strings.TrimSpace(e.Explanation),
 		CommercialOverride:    e.CommercialOverride,
 		CommercialSource:      e.CommercialSource,
+		CommercialMatch:       e.CommercialMatch(),
 		CommercialSimilarity:  round2(e.CommercialSimilarity),
+		CommercialPrice:       e.CommercialPrice,
+		Reasons:               e.Reasons(),
+		CloseMatches:          e.CloseMatches(),
+		ProcessingMs:          e.ProcessingTimeMs,
+		LookupMs:              e.LookupMs,
+		AIMs:                  e.AIMs,
 	}
 }
 
@@ -138,7 +400,7 @@ func BatchFromModel(b store.CSVBatch) BatchDTO {
 
 // BatchRequestFromModel converts a store.BatchRequest into a DTO.
 func BatchRequestFromModel(r store.BatchRequest) BatchRequestDTO {
-	return BatchRequestDTO{
+	dto := BatchRequestDTO{
 		ID:         r.ID,
 		BatchID:    r.BatchID,
 		Type:       r.Type,
@@ -146,7 +408,16 @@ func BatchRequestFromModel(r store.BatchRequest) BatchRequestDTO {
 		JobID:      r.JobID,
 		StartedAt:  r.StartedAt,
 		FinishedAt: r.FinishedAt,
+		Summary:    r.Summary(),
+		RetryOf:    r.RetryOf,
+	}
+	if r.FinishedAt != nil && !r.StartedAt.IsZero() {
+		duration := r.FinishedAt.Sub(r.StartedAt).Milliseconds()
+		dto.DurationMs = &duration
+	} else if dto.Summary != nil {
+		dto.DurationMs = &dto.Summary.DurationMs
 	}
+	return dto
 }
 
 func round2(v float64) float64 {
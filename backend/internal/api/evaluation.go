@@ -2,13 +2,16 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"runtime"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -22,11 +25,10 @@ import (
 )
 
 const (
-	evaluationThrottle            = 500 * time.Millisecond
-	commercialSimilarityThreshold = 0.8
-	aiMaxRetries                  = 3
-	aiInitialBackoff              = 2 * time.Second
-	aiMaxBackoff                  = 10 * time.Second
+	evaluationThrottle = 500 * time.Millisecond
+	aiMaxRetries       = 3
+	aiInitialBackoff   = 2 * time.Second
+	aiMaxBackoff       = 10 * time.Second
 )
 
 // evaluationJob tracks the state of a running evaluation.
@@ -38,42 +40,181 @@ type evaluationJob struct {
 	batchID   uint
 	batchName string
 	requestID uint
+	// done is closed once runEvaluation has recorded the final request status.
+	done chan struct{}
+	// interrupted marks jobs cancelled by server shutdown rather than by a user.
+	interrupted atomic.Bool
+	// correlationID is the ID of the HTTP request that started the job.
+	correlationID string
+	// listDomains pages through the rows the job evaluates: a batch's domains, or the snapshot
+	// a re-evaluation selected.
+	listDomains func(offset, limit int) ([]store.BatchDomain, error)
+}
+
+// logger returns a log entry tagged with the job, batch, and originating request IDs.
+func (j *evaluationJob) logger() *logrus.Entry {
+	return logrus.WithFields(logrus.Fields{
+		"job":        j.id,
+		"batch_id":   j.batchID,
+		requestIDKey: j.correlationID,
+	})
+}
+
+// evaluationOptions carries the per-run settings applied to every domain in a job.
+type evaluationOptions struct {
+	skipVice       bool
+	skipUSPTO      bool
+	skipCommercial bool
+	skipAI         bool
+	// aiBatch, when set, routes AI explanations through the run's batcher.
+	aiBatch *aiBatcher
+	// narratives, when set, tracks recent AI narratives to catch near-duplicates.
+	narratives *narrativeWindow
+	// trace, when set, collects intermediate stage results for the debug endpoint.
+	trace *EvaluationTrace
+}
+
+func newEvaluationOptions(req EvaluateRequest) evaluationOptions {
+	opts := evaluationOptions{
+		skipVice:       req.SkipVice,
+		skipUSPTO:      req.SkipUSPTO,
+		skipCommercial: req.SkipCommercial,
+		skipAI:         req.SkipAI,
+	}
+	if req.DedupeNarratives && !req.SkipAI {
+		opts.narratives = newNarrativeWindow(narrativeWindowSize)
+	}
+	return opts
+}
+
+// maxOwnerMarks caps the marks EvaluateRequest.MarkOwner can layer over the shared index.
+const maxOwnerMarks = 5000
+
+// customMarks returns the job-only marks requested by req: its CustomMarks names, scored as
+// fanciful, followed by the stored marks of MarkOwner, which keep their own classification. Names
+// win over stored marks normalizing to the same key.
+func (s *Server) customMarks(req EvaluateRequest) ([]store.Mark, error) {
+	var marks []store.Mark
+	for _, name := range req.CustomMarks {
+		normalized := strings.Join(strings.Fields(match.Fold(name)), " ")
+		if normalized == "" {
+			continue
+		}
+		marks = append(marks, store.Mark{
+			Serial:         scoring.CustomMarkSerialPrefix + normalized,
+			Mark:           strings.TrimSpace(name),
+			MarkNormalized: normalized,
+			MarkNoSpaces:   strings.ReplaceAll(normalized, " ", ""),
+			Owner:          strings.TrimSpace(req.MarkOwner),
+			IsFanciful:     true,
+		})
+	}
+	if strings.TrimSpace(req.MarkOwner) != "" {
+		owned, err := s.db.MarksByOwner(req.MarkOwner, maxOwnerMarks)
+		if err != nil {
+			return nil, err
+		}
+		marks = append(marks, owned...)
+	}
+	return marks, nil
+}
+
+// skippedStages lists the disabled stages in pipeline order.
+func (o evaluationOptions) skippedStages() []string {
+	var stages []string
+	if o.skipUSPTO {
+		stages = append(stages, "uspto")
+	}
+	if o.skipVice {
+		stages = append(stages, "vice")
+	}
+	if o.skipCommercial {
+		stages = append(stages, "commercial")
+	}
+	if o.skipAI {
+		stages = append(stages, "ai")
+	}
+	return stages
 }
 
 type domainResult struct {
+	// Reused marks a stored evaluation replayed by reuse_global rather than a fresh score.
+	Reused         bool
 	Evaluation     store.Evaluation
 	LookupDuration time.Duration
 	AiDuration     time.Duration
 	TotalDuration  time.Duration
-	Err            error
+	// Err wrapping match.ErrSkippable means Source could not be scored and is recorded as
+	// skipped; any other error fails the job.
+	Err    error
+	Source store.BatchDomain
+}
+
+// startEvaluation launches a new asynchronous evaluation job on behalf of the HTTP request
+// identified by correlationID. retryOf, when non-zero, is the batch request being retried. The
+// caller must hold s.jobMu prior to invoking this function.
+func (s *Server) startEvaluation(req EvaluateRequest, batch *store.CSVBatch, totalDomains int64, correlationID string, retryOf uint) (*evaluationJob, error) {
+	job := &evaluationJob{
+		total:         totalDomains,
+		batchID:       batch.ID,
+		batchName:     batch.Name,
+		correlationID: correlationID,
+		listDomains: func(offset, limit int) ([]store.BatchDomain, error) {
+			return s.db.ListBatchDomainsForEval(batch.ID, offset, limit)
+		},
+	}
+	request := &store.BatchRequest{BatchID: batch.ID, Type: "evaluate"}
+	if retryOf != 0 {
+		request.Type = "retry"
+		request.RetryOf = &retryOf
+	}
+	return s.launchJob(job, req, request)
+}
+
+// startReevaluation launches a job that re-scores the given domains, typically the stored
+// evaluations an EvaluationQuery selected. The rows are a snapshot: re-saving an evaluation can
+// move it out of the query that picked it, so paging the live query would skip domains. The
+// caller must hold s.jobMu.
+func (s *Server) startReevaluation(req EvaluateRequest, rows []store.BatchDomain, correlationID string) (*evaluationJob, error) {
+	job := &evaluationJob{
+		total:         int64(len(rows)),
+		batchName:     "re-evaluation",
+		correlationID: correlationID,
+		listDomains: func(offset, limit int) ([]store.BatchDomain, error) {
+			if offset >= len(rows) {
+				return nil, nil
+			}
+			return rows[offset:min(offset+limit, len(rows))], nil
+		},
+	}
+	return s.launchJob(job, req, &store.BatchRequest{Type: "reevaluate"})
 }
 
-// startEvaluation launches a new asynchronous evaluation job. The caller must
-// hold s.jobMu prior to invoking this function.
-func (s *Server) startEvaluation(req EvaluateRequest, batch *store.CSVBatch, totalDomains int64) (*evaluationJob, error) {
+// launchJob records request for job and starts running it. The caller must hold s.jobMu.
+func (s *Server) launchJob(job *evaluationJob, req EvaluateRequest, request *store.BatchRequest) (*evaluationJob, error) {
 	if s.activeJob != nil {
 		return nil, errors.New("evaluation already running")
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
-	job := &evaluationJob{
-		id:        uuid.NewString(),
-		cancel:    cancel,
-		startedAt: time.Now().UTC(),
-		total:     totalDomains,
-		batchID:   batch.ID,
-		batchName: batch.Name,
+	job.id = uuid.NewString()
+	job.cancel = cancel
+	job.startedAt = time.Now().UTC()
+	job.done = make(chan struct{})
+
+	request.Status = "running"
+	request.JobID = job.id
+	if params, err := json.Marshal(req); err == nil {
+		request.ParamsJSON = string(params)
 	}
-
-	request, err := s.db.CreateBatchRequest(batch.ID, "evaluate", "running", job.id)
-	if err != nil {
+	if err := s.db.CreateBatchRequest(request); err != nil {
 		job.cancel()
 		return nil, fmt.Errorf("create batch request: %w", err)
 	}
 	job.requestID = request.ID
 
 	s.activeJob = job
-	go s.runEvaluation(ctx, job, req, batch)
+	go s.runEvaluation(ctx, job, req)
 	return job, nil
 }
 
@@ -85,32 +226,84 @@ func (s *Server) cancelEvaluation() {
 	s.activeJob.cancel()
 }
 
-func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req EvaluateRequest, batch *store.CSVBatch) {
+// shutdownGrace is how long shutdownEvaluation keeps waiting for a job after marking its request
+// interrupted, so saves already in flight can finish before the database closes.
+const shutdownGrace = 5 * time.Second
+
+// shutdownEvaluation cancels the active job for a server shutdown and waits until it has
+// recorded its request status or ctx expires, in which case the request is marked directly and
+// the job gets shutdownGrace more to stop. It reports whether the job has stopped.
+func (s *Server) shutdownEvaluation(ctx context.Context) bool {
+	s.jobMu.Lock()
+	job := s.activeJob
+	s.jobMu.Unlock()
+	if job == nil {
+		return true
+	}
+
+	job.interrupted.Store(true)
+	job.cancel()
+	select {
+	case <-job.done:
+		job.logger().Info("evaluation job drained for shutdown")
+		return true
+	case <-ctx.Done():
+	}
+	job.logger().Warn("evaluation job did not drain before shutdown deadline")
+	if job.requestID != 0 {
+		if err := s.db.UpdateBatchRequest(job.requestID, "interrupted"); err != nil {
+			job.logger().WithError(err).Warn("update batch request")
+		}
+	}
+	select {
+	case <-job.done:
+		return true
+	case <-time.After(shutdownGrace):
+		return false
+	}
+}
+
+func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req EvaluateRequest) {
 	finishStatus := "completed"
 	var finishErr error
+	// The summary is saved on the batch request however the job ends, so partial runs keep one.
+	summary := store.RunSummary{Recommendations: make(map[string]int)}
+	var processingMs int64
+	finishSummary := func() store.RunSummary {
+		if summary.Evaluated > 0 {
+			summary.AvgProcessingMs = round2(float64(processingMs) / float64(summary.Evaluated))
+		}
+		summary.DurationMs = time.Since(job.startedAt).Milliseconds()
+		return summary
+	}
 
 	defer func() {
+		defer close(job.done)
 		if job.requestID != 0 {
+			if err := s.db.SaveBatchRequestSummary(job.requestID, finishSummary()); err != nil {
+				job.logger().WithError(err).Warn("save batch request summary")
+			}
 			status := finishStatus
 			if finishErr != nil && status == "completed" {
 				status = "failed"
 			}
+			if job.interrupted.Load() && status == "cancelled" {
+				status = "interrupted"
+			}
 			if err := s.db.UpdateBatchRequest(job.requestID, status); err != nil {
-				logrus.WithError(err).WithField("batch_id", job.batchID).Warn("update batch request")
+				job.logger().WithError(err).Warn("update batch request")
 			}
 		}
-		if err := s.db.UpdateBatchProcessingInfo(job.batchID); err != nil {
-			logrus.WithError(err).WithField("batch_id", job.batchID).Warn("refresh batch processing info")
+		if job.batchID != 0 {
+			if err := s.db.UpdateBatchProcessingInfo(job.batchID); err != nil {
+				job.logger().WithError(err).Warn("refresh batch processing info")
+			}
 		}
 		s.jobMu.Lock()
 		s.activeJob = nil
 		s.jobMu.Unlock()
 	}()
 
-	if req.Limit <= 0 {
-		req.Limit = 5000
-	}
-
 	totalDomains := job.total
 	if totalDomains <= 0 {
 		finishStatus = "failed"
@@ -123,7 +316,7 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 		return
 	}
 
-	marks, err := s.loadTrademarkMarks()
+	trademarkScorer, marks, err := s.loadTrademarkScorer()
 	if err != nil {
 		finishStatus = "failed"
 		finishErr = err
@@ -131,19 +324,17 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 			Type:    "error",
 			JobID:   job.id,
 			BatchID: job.batchID,
-			Message: fmt.Sprintf("load marks: %v", err),
+			Message: fmt.Sprintf("trademark scorer: %v", err),
 		})
-		logrus.WithError(err).Error("load marks")
+		job.logger().WithError(err).Error("trademark scorer")
 		return
 	}
-	logrus.WithFields(logrus.Fields{
-		"job":          job.id,
-		"batch_id":     job.batchID,
+	job.logger().WithFields(logrus.Fields{
 		"marks_loaded": len(marks),
 		"marks_limit":  s.marksLimit,
 	}).Info("trademark marks ready for evaluation")
 
-	trademarkScorer, err := scoring.NewTrademarkScorer(marks, s.seedPath)
+	custom, err := s.customMarks(req)
 	if err != nil {
 		finishStatus = "failed"
 		finishErr = err
@@ -151,17 +342,28 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 			Type:    "error",
 			JobID:   job.id,
 			BatchID: job.batchID,
-			Message: fmt.Sprintf("trademark scorer: %v", err),
+			Message: fmt.Sprintf("custom marks: %v", err),
 		})
-		logrus.WithError(err).Error("trademark scorer")
+		job.logger().WithError(err).Error("custom marks")
 		return
 	}
+	if len(custom) > 0 {
+		trademarkScorer = trademarkScorer.WithCustomMarks(custom)
+		job.logger().WithFields(logrus.Fields{
+			"custom_marks": len(custom),
+			"mark_owner":   req.MarkOwner,
+		}).Info("custom marks layered over trademark index")
+	} else if strings.TrimSpace(req.MarkOwner) != "" {
+		job.logger().WithField("mark_owner", req.MarkOwner).Warn("no marks found for owner")
+	}
 
+	opts := newEvaluationOptions(req)
 	skipExisting := req.Resume && !req.Force
+	reuseExisting := req.ReuseGlobal && !req.Force && !skipExisting
 	existing := make(map[string]struct{})
 	totalProcessed := 0
 
-	if skipExisting {
+	if skipExisting || reuseExisting {
 		evaluated, err := s.db.EvaluatedDomainsForBatch(job.batchID)
 		if err != nil {
 			finishStatus = "failed"
@@ -172,7 +374,7 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 				BatchID: job.batchID,
 				Message: fmt.Sprintf("load existing evaluations: %v", err),
 			})
-			logrus.WithError(err).Error("load existing evaluations")
+			job.logger().WithError(err).Error("load existing evaluations")
 			return
 		}
 		for _, dom := range evaluated {
@@ -181,17 +383,19 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 				existing[key] = struct{}{}
 			}
 		}
-		totalProcessed = len(existing)
+		if skipExisting {
+			totalProcessed = len(existing)
+		}
 	}
 
-	logrus.WithFields(logrus.Fields{
-		"job":        job.id,
-		"batch_id":   job.batchID,
+	job.logger().WithFields(logrus.Fields{
 		"batch_name": job.batchName,
 		"total":      job.total,
 		"processed":  totalProcessed,
 		"resume":     req.Resume,
+		"reuse":      reuseExisting,
 		"force":      req.Force,
+		"skipped":    opts.skippedStages(),
 	}).Info("evaluation job started")
 
 	s.evalNotifier.Broadcast(EvaluationEvent{
@@ -204,20 +408,21 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 	})
 
 	workerCount := determineWorkerCount()
-	logrus.WithFields(logrus.Fields{
-		"job":      job.id,
-		"batch_id": job.batchID,
-		"workers":  workerCount,
+	job.logger().WithFields(logrus.Fields{
+		"workers":        workerCount,
+		"ai_concurrency": s.aiConcurrency(workerCount),
 	}).Info("evaluation worker pool configured")
 
-	chunkSize := req.Limit
-	if chunkSize <= 0 {
-		chunkSize = 1000
-	}
-	if chunkSize > 5000 {
-		chunkSize = 5000
+	if batchExplainer, ok := s.explainer.(ai.BatchExplainer); ok && s.aiBatchSize > 1 && !opts.skipAI && batchExplainer.Enabled() {
+		opts.aiBatch = newAIBatcher(batchExplainer, s.aiBatchSize, s.callAIWithRetry, s.acquireAI)
+		go opts.aiBatch.run(ctx)
+		job.logger().WithFields(logrus.Fields{
+			"batch_size": s.aiBatchSize,
+		}).Info("ai batch mode enabled")
 	}
 
+	chunkSize := s.evalLimit.clamp(req.Limit)
+
 	taskCh := make(chan store.BatchDomain, workerCount*4)
 	resultCh := make(chan domainResult, workerCount*4)
 	errCh := make(chan error, 1)
@@ -238,9 +443,7 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 		ev := pendingEvent
 		s.evalNotifier.Broadcast(ev)
 		lastEmit = time.Now()
-		logrus.WithFields(logrus.Fields{
-			"job":       job.id,
-			"batch_id":  job.batchID,
+		job.logger().WithFields(logrus.Fields{
 			"type":      ev.Type,
 			"processed": ev.Processed,
 			"total":     job.total,
@@ -253,6 +456,21 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 		usptoCache   = make(map[string]usp.LookupResult)
 	)
 
+	if req.Prewarm {
+		if s.prewarmMax > 0 && job.total > int64(s.prewarmMax) {
+			job.logger().WithFields(logrus.Fields{
+				"total": job.total,
+				"max":   s.prewarmMax,
+			}).Info("skipping cache prewarm for large batch")
+		} else {
+			var skip map[string]struct{}
+			if skipExisting || reuseExisting {
+				skip = existing
+			}
+			s.prewarmCaches(ctx, job, opts, skip, usptoCache, &usptoCacheMu)
+		}
+	}
+
 	var workerWG sync.WaitGroup
 	for i := 0; i < workerCount; i++ {
 		workerWG.Add(1)
@@ -264,25 +482,29 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 					return
 				default:
 				}
-				res := s.evaluateDomain(ctx, task, trademarkScorer, marks, totalDomains, usptoCache, &usptoCacheMu)
+				res := s.evaluateDomain(ctx, task, trademarkScorer, marks, totalDomains, usptoCache, &usptoCacheMu, opts)
 				select {
 				case resultCh <- res:
 				case <-ctx.Done():
 					return
 				}
-				if res.Err != nil {
+				if res.Err != nil && !errors.Is(res.Err, match.ErrSkippable) {
 					return
 				}
 			}
 		}()
 	}
 
+	// The feeder also sends reused evaluations to resultCh, so it joins the wait group that
+	// guards closing it.
+	workerWG.Add(1)
 	go func() {
 		workerWG.Wait()
 		close(resultCh)
 	}()
 
 	go func() {
+		defer workerWG.Done()
 		defer close(taskCh)
 		defer close(errCh)
 		offset := req.Offset
@@ -292,7 +514,7 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 				return
 			default:
 			}
-			rows, err := s.db.ListBatchDomainsForEval(job.batchID, offset, chunkSize)
+			rows, err := job.listDomains(offset, chunkSize)
 			if err != nil {
 				errCh <- fmt.Errorf("list batch domains: %w", err)
 				return
@@ -300,17 +522,26 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 			if len(rows) == 0 {
 				return
 			}
+			if s.usptoClient != nil && !opts.skipUSPTO {
+				var skip map[string]struct{}
+				if skipExisting || reuseExisting {
+					skip = existing
+				}
+				s.prefetchUSPTO(ctx, rows, skip, usptoCache, &usptoCacheMu)
+			}
+			var reuseKeys []string
 			for _, row := range rows {
+				// Blank rows still go to the workers so they are recorded as skipped.
 				domainValue := strings.TrimSpace(row.Domain)
-				if domainValue == "" {
-					continue
-				}
 				normalizedKey := strings.TrimSpace(row.DomainNormalized)
 				if normalizedKey == "" {
-					normalizedKey = strings.ToLower(domainValue)
+					normalizedKey = domainKey(domainValue)
 				}
-				if skipExisting {
+				if skipExisting || reuseExisting {
 					if _, ok := existing[normalizedKey]; ok {
+						if reuseExisting {
+							reuseKeys = append(reuseKeys, normalizedKey)
+						}
 						continue
 					}
 				}
@@ -320,6 +551,20 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 					RowIndex:         row.RowIndex,
 				}
 			}
+			if len(reuseKeys) > 0 {
+				reused, err := s.db.EvaluationsByDomain(reuseKeys)
+				if err != nil {
+					errCh <- fmt.Errorf("load reusable evaluations: %w", err)
+					return
+				}
+				for _, eval := range reused {
+					select {
+					case resultCh <- domainResult{Reused: true, Evaluation: eval}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
 			offset += len(rows)
 			if len(rows) < chunkSize {
 				return
@@ -334,6 +579,12 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 	for activeResultCh != nil || activeErrCh != nil {
 		select {
 		case <-ctx.Done():
+			if done {
+				// The job cancels its own context once every domain is processed; that is a
+				// completion, not a cancellation.
+				activeResultCh, activeErrCh = nil, nil
+				continue
+			}
 			flush(true)
 			finishStatus = "cancelled"
 			s.evalNotifier.Broadcast(EvaluationEvent{
@@ -344,7 +595,7 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 				Processed: totalProcessed,
 				Message:   "evaluation cancelled",
 			})
-			logrus.WithField("job", job.id).WithField("batch_id", job.batchID).Warn("evaluation job cancelled via context")
+			job.logger().Warn("evaluation job cancelled via context")
 			return
 		case err, ok := <-activeErrCh:
 			if !ok {
@@ -361,7 +612,7 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 					BatchID: job.batchID,
 					Message: err.Error(),
 				})
-				logrus.WithError(err).Error("list batch domains")
+				job.logger().WithError(err).Error("list batch domains")
 				job.cancel()
 				return
 			}
@@ -373,6 +624,30 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 			if done {
 				continue
 			}
+			if errors.Is(res.Err, match.ErrSkippable) {
+				reason := match.SkipReason(res.Err)
+				skipped := store.SkippedDomain{
+					BatchID:  job.batchID,
+					RowIndex: res.Source.RowIndex,
+					Domain:   res.Source.Domain,
+					Reason:   reason,
+				}
+				if err := s.db.SaveSkippedDomain(&skipped); err != nil {
+					job.logger().WithError(err).WithField("domain", res.Source.Domain).Warn("record skipped domain")
+				}
+				job.logger().WithFields(logrus.Fields{
+					"domain": res.Source.Domain,
+					"row":    res.Source.RowIndex,
+					"reason": reason,
+				}).Info("skipped domain")
+				summary.Skipped++
+				totalProcessed++
+				if int64(totalProcessed) >= job.total {
+					done = true
+					job.cancel()
+				}
+				continue
+			}
 			if res.Err != nil {
 				flush(true)
 				finishStatus = "failed"
@@ -383,14 +658,16 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 					BatchID: job.batchID,
 					Message: fmt.Sprintf("evaluate domain: %v", res.Err),
 				})
-				logrus.WithError(res.Err).Error("evaluate domain")
+				job.logger().WithError(res.Err).Error("evaluate domain")
 				job.cancel()
 				return
 			}
 
 			saveStart := time.Now()
 			eval := res.Evaluation
-			if err := s.db.SaveEvaluation(&eval); err != nil {
+			if res.Reused {
+				summary.Reused++
+			} else if err := s.db.SaveEvaluation(&eval); err != nil {
 				flush(true)
 				finishStatus = "failed"
 				finishErr = err
@@ -400,11 +677,18 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 					BatchID: job.batchID,
 					Message: fmt.Sprintf("save evaluation: %v", err),
 				})
-				logrus.WithError(err).Error("save evaluation")
+				job.logger().WithError(err).Error("save evaluation")
 				job.cancel()
 				return
+			} else {
+				summary.Evaluated++
+				processingMs += eval.ProcessingTimeMs
 			}
 			saveDuration := time.Since(saveStart)
+			summary.Recommendations[eval.OverallRecommendation]++
+			if eval.CommercialOverride {
+				summary.CommercialOverrides++
+			}
 
 			if skipExisting {
 				existing[eval.DomainNormalized] = struct{}{}
@@ -420,12 +704,11 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 				Total:      job.total,
 				Processed:  totalProcessed,
 				Evaluation: &dto,
+				Reused:     res.Reused,
 			}
 			hasPending = true
 			totalElapsed := res.TotalDuration + saveDuration
-			logrus.WithFields(logrus.Fields{
-				"job":           job.id,
-				"batch_id":      job.batchID,
+			job.logger().WithFields(logrus.Fields{
 				"domain":        eval.Domain,
 				"lookup_ms":     res.LookupDuration.Milliseconds(),
 				"ai_ms":         res.AiDuration.Milliseconds(),
@@ -447,22 +730,40 @@ func (s *Server) runEvaluation(ctx context.Context, job *evaluationJob, req Eval
 	flush(true)
 
 	duration := time.Since(job.startedAt).Round(time.Millisecond)
+	message := fmt.Sprintf("evaluation finished in %s", duration)
+	if summary.Reused > 0 {
+		message += fmt.Sprintf(" (%d reused)", summary.Reused)
+	}
+	if summary.Skipped > 0 {
+		message += fmt.Sprintf(" (%d skipped)", summary.Skipped)
+	}
+	runSummary := finishSummary()
 	s.evalNotifier.Broadcast(EvaluationEvent{
 		Type:      "complete",
 		JobID:     job.id,
 		BatchID:   job.batchID,
 		Total:     job.total,
 		Processed: totalProcessed,
-		Message:   fmt.Sprintf("evaluation finished in %s", duration),
+		Message:   message,
+		Summary:   &runSummary,
 	})
-	logrus.WithFields(logrus.Fields{
-		"job":       job.id,
-		"batch_id":  job.batchID,
-		"processed": totalProcessed,
-		"duration":  duration,
+	job.logger().WithFields(logrus.Fields{
+		"processed":       totalProcessed,
+		"reused":          summary.Reused,
+		"skipped":         summary.Skipped,
+		"recommendations": summary.Recommendations,
+		"duration":        duration,
 	}).Info("evaluation job completed")
 }
 
+// aiConcurrency reports how many AI calls can be in flight with the given worker count.
+func (s *Server) aiConcurrency(workers int) int {
+	if s.aiSlots != nil && cap(s.aiSlots) < workers {
+		return cap(s.aiSlots)
+	}
+	return workers
+}
+
 func determineWorkerCount() int {
 	workers := runtime.NumCPU()
 	if workers < 2 {
@@ -482,8 +783,9 @@ func (s *Server) evaluateDomain(
 	totalDomains int64,
 	cache map[string]usp.LookupResult,
 	cacheMu *sync.Mutex,
+	opts evaluationOptions,
 ) domainResult {
-	result := domainResult{}
+	result := domainResult{Source: domain}
 
 	if err := ctx.Err(); err != nil {
 		result.Err = err
@@ -491,26 +793,26 @@ func (s *Server) evaluateDomain(
 	}
 
 	domainValue := strings.TrimSpace(domain.Domain)
-	if domainValue == "" {
-		result.Err = errors.New("empty domain value")
+	profile := match.NormalizeDomain(domainValue)
+	if err := match.CheckProfile(profile); err != nil {
+		result.Err = err
 		return result
 	}
 
 	normalizedKey := strings.TrimSpace(domain.DomainNormalized)
 	if normalizedKey == "" {
-		normalizedKey = strings.ToLower(domainValue)
+		normalizedKey = domainKey(domainValue)
 	}
 
 	domainStart := time.Now()
 	timer := util.StartTimer()
-	profile := match.NormalizeDomain(domainValue)
 
 	fallbackResult := trademarkScorer.Score(profile)
 
 	lookupDuration := time.Duration(0)
 	var lookupResult usp.LookupResult
 	var lookupValid bool
-	if s.usptoClient != nil {
+	if s.usptoClient != nil && !opts.skipUSPTO {
 		lookupStart := time.Now()
 		if cacheMu != nil {
 			cacheMu.Lock()
@@ -522,35 +824,98 @@ func (s *Server) evaluateDomain(
 		lookupDuration = time.Since(lookupStart)
 	}
 
+	var reasons []string
+	if skipped := opts.skippedStages(); len(skipped) > 0 {
+		reasons = append(reasons, "stages skipped: "+strings.Join(skipped, ", "))
+	}
 	trademarkResult, closeMatches := s.resolveTrademark(profile, lookupValid, lookupResult, fallbackResult)
-	viceResult := s.viceScorer.Score(profile)
+	trademarkResult, ownerReason := s.owners.Apply(trademarkResult)
+	watchedOwner := trademarkResult.MatchedTrademark != "" && s.owners.Match(trademarkResult.Owner) != ""
+	if ownerReason != "" {
+		reasons = append(reasons, ownerReason)
+	}
+	if trace := opts.trace; trace != nil {
+		trace.HeuristicTrademark = fallbackResult
+		trace.USPTOChecked = lookupValid
+		if lookupValid {
+			trace.USPTO = &lookupResult
+		}
+		trace.Trademark = trademarkResult
+		trace.CloseMatches = closeMatches
+	}
+	var subdomainBrand *scoring.SubdomainBrand
+	if s.subdomains {
+		if hit, ok := trademarkScorer.ScoreSubdomains(profile); ok {
+			subdomainBrand = &hit
+			reasons = append(reasons, fmt.Sprintf("subdomain %q carries %s mark %s on unrelated domain %s",
+				hit.Label, hit.Trademark.Type, hit.Trademark.MatchedTrademark, hit.Registrable))
+		}
+		if opts.trace != nil {
+			opts.trace.SubdomainBrand = subdomainBrand
+		}
+	}
+	trademarkConflict, conflictReason := s.detectTrademarkConflict(profile, lookupValid, lookupResult, fallbackResult)
+	if trademarkConflict {
+		reasons = append(reasons, conflictReason)
+	}
+	randomness := scoring.DetectRandomness(profile.BrandToken)
+	if randomness.Random {
+		reasons = append(reasons, fmt.Sprintf("label looks algorithmically generated (entropy %.2f, %.0f%% uncommon letter pairs)",
+			randomness.Entropy, randomness.RareBigramRatio*100))
+	}
+	spam := match.DetectSpamPattern(profile, s.spamThresholds)
+	if spam.Spammy {
+		reasons = append(reasons, "label has a spam-like structure ("+spam.Describe()+")")
+	}
+	var viceResult scoring.ViceResult
+	if !opts.skipVice {
+		viceResult = s.viceScorer.Score(profile)
+	}
+	if viceResult.Allowlisted != "" {
+		reasons = append(reasons, fmt.Sprintf("vice allowlist (%s) reduced severity %d to %d: %s",
+			viceResult.Allowlisted, viceResult.OriginalScore, viceResult.Score, strings.Join(viceResult.Suppressed, ", ")))
+	}
+	secondLevel, topLevel := splitDomainParts(domainValue)
 	overall := scoring.CombineRecommendation(trademarkResult, viceResult)
+	if opts.skipVice {
+		overall.Confidence = trademarkResult.Confidence
+	}
+	overall, _ = s.tldRisk.Adjust(topLevel, overall)
 
 	commercialOverride := false
 	commercialSource := ""
 	commercialSimilarity := 0.0
 	commercialPrice := 0.0
+	var commercialMatch *store.CommercialMatch
 
-	secondLevel, topLevel := splitDomainParts(domainValue)
-	if s.commercial != nil {
-		if match, ok := s.commercial.BestMatch(secondLevel); ok && match.Similarity >= commercialSimilarityThreshold {
+	if s.commercial != nil && !opts.skipCommercial {
+		match, ok := s.commercial.BestMatch(secondLevel)
+		if opts.trace != nil && ok {
+			opts.trace.Commercial = &match
+		}
+		if ok && match.Similarity >= s.salesPolicy.MinSimilarity {
 			commercialSimilarity = match.Similarity
 			commercialPrice = match.Price
-			commercialSource = fmt.Sprintf("sale $%.0f", match.Price)
-			if viceResult.Score <= 2 && trademarkResult.Score <= 3 {
+			commercialMatch = &store.CommercialMatch{Source: "sale", SLD: match.SLD, Price: match.Price, Similarity: match.Similarity}
+			commercialSource = commercialMatch.Display()
+			if tier, ok := s.salesPolicy.EligibleAt(trademarkResult.Score, viceResult.Score, match.Similarity, match.Price); ok {
 				commercialOverride = true
-				switch overall.Recommendation {
-				case "BLOCK":
-					overall.Recommendation = "REVIEW"
-				case "REVIEW":
-					overall.Recommendation = "ALLOW_WITH_CAUTION"
-				}
+				overall.Recommendation = s.salesPolicy.Apply(overall.Recommendation)
+				reasons = append(reasons, fmt.Sprintf("commercial override: %s tier (sale $%.0f, at least $%.0f)", tier.Name, match.Price, tier.MinPrice))
 			}
 		}
 	}
 
+	if trace := opts.trace; trace != nil {
+		trace.Vice = viceResult
+		trace.Randomness = randomness
+		trace.Spam = spam
+		trace.CommercialOverride = commercialOverride
+		trace.RecommendationBeforeAI = overall.Recommendation
+	}
+
 	aiStart := time.Now()
-	decision, err := s.generateDecision(
+	decision, notes, err := s.generateDecision(
 		ctx,
 		profile,
 		domainValue,
@@ -566,32 +931,72 @@ func (s *Server) evaluateDomain(
 		commercialSource,
 		commercialSimilarity,
 		commercialPrice,
+		randomness,
+		spam,
+		subdomainBrand,
+		opts,
 	)
 	aiDuration := time.Since(aiStart)
 	if err != nil {
 		result.Err = err
 		return result
 	}
+	reasons = append(reasons, notes...)
 
+	// The AI may not relax hard-rule verdicts: scores at or above the guardrail stay, and the
+	// recommendation stays at REVIEW or stricter.
+	heuristicTrademark, heuristicVice := trademarkResult.Score, viceResult.Score
+	var rejected []string
 	if decision.TrademarkScore != nil {
-		trademarkResult.Score = clampScore(*decision.TrademarkScore)
+		score, reason := scoring.GuardAIScore("trademark", heuristicTrademark, clampScore(*decision.TrademarkScore), s.aiGuardrail)
+		trademarkResult.Score = score
+		if reason != "" {
+			rejected = append(rejected, reason)
+		}
 	}
 	if decision.ViceScore != nil {
-		viceResult.Score = clampScore(*decision.ViceScore)
+		score, reason := scoring.GuardAIScore("vice", heuristicVice, clampScore(*decision.ViceScore), s.aiGuardrail)
+		viceResult.Score = score
+		if reason != "" {
+			rejected = append(rejected, reason)
+		}
 	}
 
 	overall = scoring.CombineRecommendation(trademarkResult, viceResult)
+	if opts.skipVice {
+		overall.Confidence = trademarkResult.Confidence
+	}
+	overall, tldReason := s.tldRisk.Adjust(topLevel, overall)
+	if tldReason != "" {
+		reasons = append(reasons, tldReason)
+	}
 	if commercialOverride {
-		switch overall.Recommendation {
-		case "BLOCK":
-			overall.Recommendation = "REVIEW"
-		case "REVIEW":
-			overall.Recommendation = "ALLOW_WITH_CAUTION"
-		}
+		overall.Recommendation = s.salesPolicy.Apply(overall.Recommendation)
 	}
+	preAI := overall.Recommendation
 
-	if rec := strings.ToUpper(strings.TrimSpace(decision.Recommendation)); rec != "" {
+	if decision.Recommendation != "" {
+		rec, reason := scoring.GuardAIRecommendation(decision.Recommendation, overall.Recommendation, heuristicTrademark, heuristicVice, s.aiGuardrail)
 		overall.Recommendation = rec
+		if reason != "" {
+			rejected = append(rejected, reason)
+		}
+	}
+	// A risky TLD still nudges a milder recommendation the AI picks, one step up from the AI's
+	// pick rather than back to the pre-AI recommendation.
+	if overall.Recommendation.Severity() < preAI.Severity() {
+		var aiTLDReason string
+		overall, aiTLDReason = s.tldRisk.Adjust(topLevel, overall)
+		if aiTLDReason != "" && aiTLDReason != tldReason {
+			reasons = append(reasons, aiTLDReason)
+		}
+	}
+	if len(rejected) > 0 {
+		logrus.WithFields(logrus.Fields{
+			"domain":   domainValue,
+			"rejected": rejected,
+		}).Warn("ai override rejected by guardrail")
+		reasons = append(reasons, rejected...)
 	}
 	if decision.Confidence != nil {
 		conf := clampConfidence(*decision.Confidence)
@@ -599,6 +1004,34 @@ func (s *Server) evaluateDomain(
 		trademarkResult.Confidence = conf
 		viceResult.Confidence = conf
 	}
+	// Softening runs once, on the final confidence, so a BLOCK drops at most one step.
+	if s.softenLow {
+		var softenReason string
+		overall, softenReason = scoring.SoftenLowConfidence(overall, s.lowConfidence)
+		if softenReason != "" {
+			reasons = append(reasons, softenReason)
+		}
+	}
+	if trademarkConflict && s.reviewConflicts && overall.Recommendation.Severity() < scoring.RecommendationReview.Severity() {
+		reasons = append(reasons, fmt.Sprintf("trademark conflict routed %s to REVIEW", overall.Recommendation))
+		overall.Recommendation = scoring.RecommendationReview
+	}
+	if watchedOwner && (overall.Recommendation == scoring.RecommendationAllow || overall.Recommendation == scoring.RecommendationAllowWithCaution) {
+		reasons = append(reasons, fmt.Sprintf("high-value owner routed %s to REVIEW", overall.Recommendation))
+		overall.Recommendation = scoring.RecommendationReview
+	}
+	if subdomainBrand != nil && (overall.Recommendation == scoring.RecommendationAllow || overall.Recommendation == scoring.RecommendationAllowWithCaution) {
+		reasons = append(reasons, fmt.Sprintf("brand in subdomain routed %s to REVIEW", overall.Recommendation))
+		overall.Recommendation = scoring.RecommendationReview
+	}
+	if randomness.Random && s.reviewRandom && overall.Recommendation == scoring.RecommendationAllowWithCaution {
+		reasons = append(reasons, "random-looking label routed ALLOW_WITH_CAUTION to REVIEW")
+		overall.Recommendation = scoring.RecommendationReview
+	}
+	if spam.Spammy && s.reviewSpam && overall.Recommendation == scoring.RecommendationAllowWithCaution {
+		reasons = append(reasons, "spam-like label routed ALLOW_WITH_CAUTION to REVIEW")
+		overall.Recommendation = scoring.RecommendationReview
+	}
 
 	eval := store.Evaluation{
 		Domain:                domainValue,
@@ -606,17 +1039,29 @@ func (s *Server) evaluateDomain(
 		TrademarkScore:        trademarkResult.Score,
 		TrademarkType:         trademarkResult.Type,
 		MatchedTrademark:      trademarkResult.MatchedTrademark,
+		MatchedOwner:          trademarkResult.Owner,
+		MatchedSerial:         trademarkResult.Serial,
+		MatchedRegistration:   trademarkResult.Registration,
+		TrademarkSource:       trademarkResult.Source,
 		TrademarkConfidence:   trademarkResult.Confidence,
+		TrademarkConflict:     trademarkConflict,
 		ViceScore:             viceResult.Score,
 		ViceConfidence:        viceResult.Confidence,
-		OverallRecommendation: overall.Recommendation,
+		OverallRecommendation: string(overall.Recommendation),
 		ProcessingTimeMs:      timer.ElapsedMs(),
 		Explanation:           strings.TrimSpace(decision.Narrative),
 		CommercialOverride:    commercialOverride,
-		CommercialSource:      commercialSource,
 		CommercialSimilarity:  commercialSimilarity,
+		CommercialPrice:       commercialPrice,
 	}
+	eval.SetCommercialMatch(commercialMatch)
 	eval.SetViceCategories(viceResult.Categories)
+	eval.SetReasons(reasons)
+	eval.SetCloseMatches(closeMatches)
+	if s.storeTimings {
+		lookupMs, aiMs := lookupDuration.Milliseconds(), aiDuration.Milliseconds()
+		eval.LookupMs, eval.AIMs = &lookupMs, &aiMs
+	}
 
 	result.Evaluation = eval
 	result.LookupDuration = lookupDuration
@@ -641,12 +1086,12 @@ func (s *Server) generateDecision(
 	commercialSource string,
 	commercialSimilarity float64,
 	commercialPrice float64,
-) (ai.Decision, error) {
-	decision := ai.Decision{Recommendation: strings.ToUpper(strings.TrimSpace(overall.Recommendation))}
-	if s.explainer == nil || !s.explainer.Enabled() {
-		decision.Narrative = buildFallbackNarrative(overall.Recommendation)
-		return decision, nil
-	}
+	randomness scoring.RandomnessResult,
+	spam match.SpamSignal,
+	subdomainBrand *scoring.SubdomainBrand,
+	opts evaluationOptions,
+) (ai.Decision, []string, error) {
+	decision := ai.Decision{Recommendation: overall.Recommendation}
 
 	tokens := collectDomainTokens(profile)
 	viceTerms := append([]string{}, viceResult.Categories...)
@@ -670,26 +1115,102 @@ func (s *Server) generateDecision(
 		CommercialSource:     commercialSource,
 		CommercialSimilarity: commercialSimilarity,
 		CommercialPrice:      commercialPrice,
+		Randomness:           randomness,
+		Spam:                 spam,
+		SubdomainBrand:       subdomainBrand,
+	}
+
+	if opts.skipAI || s.explainer == nil || !s.explainer.Enabled() {
+		decision.Narrative = ai.TemplateNarrative(input)
+		if opts.trace != nil {
+			opts.trace.DecisionSource = "template"
+		}
+		return decision, nil, nil
 	}
 
-	result, err := s.callAIWithRetry(ctx, input)
+	explain := s.callAIWithRetry
+	if opts.aiBatch != nil {
+		explain = opts.aiBatch.Explain
+	}
+	result, err := explain(ctx, input)
 	if err != nil {
 		logrus.WithError(err).Warn("ai explainer unavailable; falling back to heuristic output")
-		decision.Narrative = buildFallbackNarrative(overall.Recommendation)
-		return decision, nil
+		decision.Narrative = ai.TemplateNarrative(input)
+		if opts.trace != nil {
+			opts.trace.DecisionSource = "fallback"
+			opts.trace.AIError = err.Error()
+		}
+		return decision, nil, nil
+	}
+	if opts.trace != nil {
+		raw := result
+		opts.trace.DecisionSource = "ai"
+		opts.trace.AIDecision = &raw
+	}
+
+	var notes []string
+	if opts.narratives != nil {
+		result, notes = s.dedupeNarrative(ctx, explain, input, result, opts.narratives)
 	}
 
 	if strings.TrimSpace(result.Narrative) != "" {
 		decision.Narrative = strings.TrimSpace(result.Narrative)
+		if truncated, ok := ai.TruncateNarrative(decision.Narrative, s.narrativeMax); ok {
+			notes = append(notes, fmt.Sprintf("narrative truncated from %d to %d characters",
+				utf8.RuneCountInString(decision.Narrative), utf8.RuneCountInString(truncated)))
+			decision.Narrative = truncated
+		}
 	}
-	if rec := strings.ToUpper(strings.TrimSpace(result.Recommendation)); rec != "" {
+	if rec, err := scoring.ParseRecommendation(string(result.Recommendation)); err == nil {
 		decision.Recommendation = rec
 	}
 	decision.TrademarkScore = result.TrademarkScore
 	decision.ViceScore = result.ViceScore
 	decision.Confidence = result.Confidence
 
-	return decision, nil
+	return decision, notes, nil
+}
+
+// dedupeNarrative regenerates a narrative once when it closely repeats recent output and reports
+// the repetition when the regenerated narrative is still too similar.
+func (s *Server) dedupeNarrative(
+	ctx context.Context,
+	explain func(context.Context, ai.ExplanationInput) (ai.Decision, error),
+	input ai.ExplanationInput,
+	result ai.Decision,
+	window *narrativeWindow,
+) (ai.Decision, []string) {
+	var notes []string
+	score := window.similarity(result.Narrative)
+	if score >= narrativeRepeatThreshold {
+		input.RepeatedNarrative = result.Narrative
+		if retry, err := explain(ctx, input); err == nil {
+			if retryScore := window.similarity(retry.Narrative); retryScore < score {
+				result, score = retry, retryScore
+			}
+		} else {
+			logrus.WithError(err).WithField("domain", input.Domain).Warn("narrative regeneration failed")
+		}
+		if score >= narrativeRepeatThreshold {
+			notes = append(notes, fmt.Sprintf("narrative repeats recent output (%.0f%% n-gram overlap)", score*100))
+		}
+	}
+	window.add(result.Narrative)
+	return result, notes
+}
+
+// acquireAI waits for a slot under the AI concurrency limit and returns the function releasing
+// it. Without a limit it returns immediately.
+func (s *Server) acquireAI(ctx context.Context) (func(), error) {
+	if s.aiSlots == nil {
+		return func() {}, nil
+	}
+	select {
+	case s.aiSlots <- struct{}{}:
+		return func() { <-s.aiSlots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 }
 
 func (s *Server) callAIWithRetry(ctx context.Context, input ai.ExplanationInput) (ai.Decision, error) {
@@ -700,7 +1221,13 @@ func (s *Server) callAIWithRetry(ctx context.Context, input ai.ExplanationInput)
 	delay := aiInitialBackoff
 	var lastErr error
 	for attempt := 0; attempt < aiMaxRetries; attempt++ {
+		// The slot is held per attempt, not across the backoff, so waiting workers can use it.
+		release, err := s.acquireAI(ctx)
+		if err != nil {
+			return ai.Decision{}, err
+		}
 		decision, err := s.explainer.Explain(ctx, input)
+		release()
 		if err == nil {
 			return decision, nil
 		}
@@ -733,24 +1260,13 @@ func shouldRetryAI(err error) bool {
 	if err == nil {
 		return false
 	}
+	if errors.Is(err, ai.ErrNarrativeContract) {
+		return true
+	}
 	msg := strings.ToLower(err.Error())
 	return strings.Contains(msg, "status 429") || strings.Contains(msg, "status 500") || strings.Contains(msg, "status 503")
 }
 
-func buildFallbackNarrative(recommendation string) string {
-	rec := strings.ToUpper(strings.TrimSpace(recommendation))
-	switch rec {
-	case "BLOCK":
-		return "Heuristic risk scoring recommends BLOCK; AI explanation is temporarily unavailable."
-	case "REVIEW":
-		return "Heuristic signals recommend REVIEW; AI narrative waiting for retry."
-	case "ALLOW_WITH_CAUTION":
-		return "Heuristic evaluation suggests ALLOW WITH CAUTION; AI summary could not be retrieved."
-	default:
-		return "Heuristic evaluation completed; AI explanation unavailable at this time."
-	}
-}
-
 func splitDomainParts(domain string) (string, string) {
 	host := strings.ToLower(strings.TrimSpace(domain))
 	parts := strings.Split(host, ".")
@@ -819,7 +1335,7 @@ func hasSubstringAlerts(terms []string, core string, tokens []string) bool {
 	return false
 }
 
-func extractRecommendation(text string, fallback string) string {
+func extractRecommendation(text string, fallback scoring.Recommendation) scoring.Recommendation {
 	lines := strings.Split(text, "\n")
 	for i := len(lines) - 1; i >= 0; i-- {
 		line := strings.TrimSpace(lines[i])
@@ -841,15 +1357,11 @@ func extractRecommendation(text string, fallback string) string {
 		if len(upto) == 0 {
 			break
 		}
-		candidate := strings.ToUpper(strings.TrimSpace(upto[0]))
-		switch candidate {
-		case "BLOCK", "REVIEW", "ALLOW_WITH_CAUTION", "ALLOW":
+		if candidate, err := scoring.ParseRecommendation(strings.TrimRight(upto[0], ".,;:")); err == nil {
 			return candidate
-		case "ALLOWWITHCAUTION":
-			return "ALLOW_WITH_CAUTION"
 		}
 	}
-	return strings.ToUpper(strings.TrimSpace(fallback))
+	return fallback
 }
 
 func sortStrings(items []string) {
@@ -0,0 +1,250 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"domain-risk-eval/backend/internal/ai"
+	"domain-risk-eval/backend/internal/match"
+	"domain-risk-eval/backend/internal/scoring"
+	"domain-risk-eval/backend/internal/store"
+	"domain-risk-eval/backend/internal/usp"
+)
+
+// newOfflineServer starts a Server on a temporary database with the fake explainer.
+func newOfflineServer(t *testing.T) *Server {
+	t.Helper()
+	s, err := NewServer(Config{DBPath: filepath.Join(t.TempDir(), "test.db"), SilentDB: true, FakeAI: true})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	t.Cleanup(func() { s.db.Close() })
+	return s
+}
+
+func TestEvaluateDomainTruncatesLongNarrative(t *testing.T) {
+	s := newOfflineServer(t)
+	s.narrativeMax = 60
+	scorer, marks, err := s.loadTrademarkScorer()
+	if err != nil {
+		t.Fatalf("trademark scorer: %v", err)
+	}
+	s.explainer = &ai.FakeExplainer{Decide: func(ai.ExplanationInput) (ai.Decision, error) {
+		return ai.Decision{Narrative: "The label is clean. Allow it, since nothing matched.\n" + strings.Repeat("Runaway text ", 20)}, nil
+	}}
+	task := store.BatchDomain{Domain: "quietharbor.com", DomainNormalized: "quietharbor.com"}
+	opts := newEvaluationOptions(EvaluateRequest{SkipUSPTO: true, SkipCommercial: true})
+
+	res := s.evaluateDomain(context.Background(), task, scorer, marks, 1, map[string]usp.LookupResult{}, nil, opts)
+	if res.Err != nil {
+		t.Fatalf("evaluate: %v", res.Err)
+	}
+	if got := res.Evaluation.Explanation; got != "The label is clean. Allow it, since nothing matched." {
+		t.Fatalf("unexpected narrative %q", got)
+	}
+	if reasons := strings.Join(res.Evaluation.Reasons(), "; "); !strings.Contains(reasons, "narrative truncated") {
+		t.Fatalf("expected truncation reason, got %q", reasons)
+	}
+}
+
+func TestEvaluateDomainWithFakeExplainer(t *testing.T) {
+	s := newOfflineServer(t)
+	scorer, marks, err := s.loadTrademarkScorer()
+	if err != nil {
+		t.Fatalf("trademark scorer: %v", err)
+	}
+	task := store.BatchDomain{Domain: "quietharbor.com", DomainNormalized: "quietharbor.com"}
+	opts := newEvaluationOptions(EvaluateRequest{SkipUSPTO: true, SkipCommercial: true})
+
+	tests := []struct {
+		name           string
+		decide         func(ai.ExplanationInput) (ai.Decision, error)
+		recommendation scoring.Recommendation
+		viceScore      int
+		narrative      string
+	}{
+		{"echo keeps heuristics", nil, scoring.RecommendationAllow, 0, ""},
+		{"override applies", func(input ai.ExplanationInput) (ai.Decision, error) {
+			vice := 4
+			return ai.Decision{Narrative: "Flagged. Review it.", ViceScore: &vice, Recommendation: scoring.RecommendationBlock}, nil
+		}, scoring.RecommendationBlock, 4, "Flagged. Review it."},
+		{"failure falls back to template", func(ai.ExplanationInput) (ai.Decision, error) {
+			return ai.Decision{}, errors.New("boom")
+		}, scoring.RecommendationAllow, 0, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s.explainer = &ai.FakeExplainer{Decide: tc.decide}
+			res := s.evaluateDomain(context.Background(), task, scorer, marks, 1, map[string]usp.LookupResult{}, nil, opts)
+			if res.Err != nil {
+				t.Fatalf("evaluate: %v", res.Err)
+			}
+			eval := res.Evaluation
+			if scoring.Recommendation(eval.OverallRecommendation) != tc.recommendation || eval.ViceScore != tc.viceScore {
+				t.Fatalf("expected %s/vice %d got %s/vice %d", tc.recommendation, tc.viceScore, eval.OverallRecommendation, eval.ViceScore)
+			}
+			if eval.Explanation == "" || (tc.narrative != "" && eval.Explanation != tc.narrative) {
+				t.Fatalf("unexpected narrative %q", eval.Explanation)
+			}
+		})
+	}
+}
+
+func TestEvaluateDomainGuardsAIOverrides(t *testing.T) {
+	s := newOfflineServer(t)
+	scorer, marks, err := s.loadTrademarkScorer()
+	if err != nil {
+		t.Fatalf("trademark scorer: %v", err)
+	}
+	s.explainer = &ai.FakeExplainer{Decide: func(ai.ExplanationInput) (ai.Decision, error) {
+		vice := 0
+		return ai.Decision{Narrative: "Looks fine.", ViceScore: &vice, Recommendation: scoring.RecommendationAllow}, nil
+	}}
+	task := store.BatchDomain{Domain: "hitmanforhire.com", DomainNormalized: "hitmanforhire.com"}
+	opts := newEvaluationOptions(EvaluateRequest{SkipUSPTO: true, SkipCommercial: true})
+
+	res := s.evaluateDomain(context.Background(), task, scorer, marks, 1, map[string]usp.LookupResult{}, nil, opts)
+	if res.Err != nil {
+		t.Fatalf("evaluate: %v", res.Err)
+	}
+	eval := res.Evaluation
+	if eval.ViceScore != 5 || eval.OverallRecommendation == string(scoring.RecommendationAllow) {
+		t.Fatalf("expected the guardrail to keep vice 5 and reject ALLOW, got vice %d/%s", eval.ViceScore, eval.OverallRecommendation)
+	}
+	if reasons := strings.Join(eval.Reasons(), "; "); !strings.Contains(reasons, "AI recommendation ALLOW rejected") {
+		t.Fatalf("expected a rejection reason, got %q", reasons)
+	}
+
+	s.aiGuardrail = -1
+	res = s.evaluateDomain(context.Background(), task, scorer, marks, 1, map[string]usp.LookupResult{}, nil, opts)
+	if res.Err != nil || res.Evaluation.OverallRecommendation != string(scoring.RecommendationAllow) {
+		t.Fatalf("expected the disabled guardrail to let ALLOW through, got %s (%v)", res.Evaluation.OverallRecommendation, res.Err)
+	}
+}
+
+func TestEvaluateDomainKeepsTLDNudgeOverAI(t *testing.T) {
+	s := newOfflineServer(t)
+	scorer, marks, err := s.loadTrademarkScorer()
+	if err != nil {
+		t.Fatalf("trademark scorer: %v", err)
+	}
+	s.explainer = &ai.FakeExplainer{Decide: func(ai.ExplanationInput) (ai.Decision, error) {
+		return ai.Decision{Narrative: "Looks fine.", Recommendation: scoring.RecommendationAllow}, nil
+	}}
+	task := store.BatchDomain{Domain: "quietmeadow.zip", DomainNormalized: "quietmeadow.zip"}
+	opts := newEvaluationOptions(EvaluateRequest{SkipUSPTO: true, SkipCommercial: true})
+
+	res := s.evaluateDomain(context.Background(), task, scorer, marks, 1, map[string]usp.LookupResult{}, nil, opts)
+	if res.Err != nil {
+		t.Fatalf("evaluate: %v", res.Err)
+	}
+	if got := res.Evaluation.OverallRecommendation; got != string(scoring.RecommendationAllowWithCaution) {
+		t.Fatalf("expected the .zip nudge to ALLOW_WITH_CAUTION to survive the AI, got %s", got)
+	}
+	if reasons := strings.Join(res.Evaluation.Reasons(), "; "); !strings.Contains(reasons, "raised ALLOW to ALLOW_WITH_CAUTION") {
+		t.Fatalf("expected the TLD reason, got %q", reasons)
+	}
+
+	// Trademark 2 makes the pre-AI recommendation ALLOW_WITH_CAUTION, which .zip raises to
+	// REVIEW. The AI's ALLOW is nudged one step from there, not floored back at REVIEW.
+	s.explainer = &ai.FakeExplainer{Decide: func(ai.ExplanationInput) (ai.Decision, error) {
+		trademark := 2
+		return ai.Decision{Narrative: "Looks fine.", TrademarkScore: &trademark, Recommendation: scoring.RecommendationAllow}, nil
+	}}
+	res = s.evaluateDomain(context.Background(), task, scorer, marks, 1, map[string]usp.LookupResult{}, nil, opts)
+	if res.Err != nil {
+		t.Fatalf("evaluate: %v", res.Err)
+	}
+	if got := res.Evaluation.OverallRecommendation; got != string(scoring.RecommendationAllowWithCaution) {
+		t.Fatalf("expected the AI's ALLOW nudged to ALLOW_WITH_CAUTION, got %s", got)
+	}
+	reasons := strings.Join(res.Evaluation.Reasons(), "; ")
+	if !strings.Contains(reasons, "raised ALLOW_WITH_CAUTION to REVIEW") || !strings.Contains(reasons, "raised ALLOW to ALLOW_WITH_CAUTION") {
+		t.Fatalf("expected both TLD reasons, got %q", reasons)
+	}
+}
+
+func TestSimilarMarkResult(t *testing.T) {
+	s := &Server{similarReview: true, similarMin: defaultSimilarMarkThreshold}
+	similar := []usp.Mark{
+		{Mark: "PAYPAL", SerialNumber: "1", IsLive: true, Similarity: 5.0 / 6},
+		{Mark: "PAYPAL HOLDINGS", SerialNumber: "2", IsLive: true, Similarity: 0.4},
+		{Mark: "PAYPA", SerialNumber: "3", Similarity: 0.95},
+	}
+	result, ok := s.similarMarkResult(similar)
+	if !ok || result.MatchedTrademark != "PAYPAL" || result.Score != 3 || result.Source != scoring.MatchSourceUSPTOSimilar {
+		t.Fatalf("expected the live one-edit typosquat to escalate, got %v %+v", ok, result)
+	}
+	if _, ok := s.similarMarkResult(similar[1:2]); ok {
+		t.Fatal("expected a dissimilar mark to be ignored")
+	}
+	s.similarReview = false
+	if _, ok := s.similarMarkResult(similar); ok {
+		t.Fatal("expected nothing when similar-mark review is off")
+	}
+}
+
+func TestResolveTrademarkNoneSource(t *testing.T) {
+	profile := match.NormalizeDomain("zorblax.com")
+	none := scoring.TrademarkResult{Type: "none"}
+	tests := []struct {
+		name   string
+		lookup usp.LookupResult
+		source string
+	}{
+		{"similar marks", usp.LookupResult{Checked: true, Similar: []usp.Mark{{Mark: "ZORBLOX"}}}, scoring.MatchSourceUSPTOSimilar},
+		{"exact match on another token", usp.LookupResult{Checked: true, ExactMatches: []usp.Mark{{Mark: "ZORB"}}}, ""},
+		{"nothing found", usp.LookupResult{Checked: true}, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &Server{}
+			result, _ := s.resolveTrademark(profile, true, tc.lookup, none)
+			if result.Type != "none" || result.Source != tc.source {
+				t.Fatalf("expected none from %q, got %s from %q", tc.source, result.Type, result.Source)
+			}
+		})
+	}
+}
+
+func TestResolveTrademarkSimilarOnly(t *testing.T) {
+	profile := match.NormalizeDomain("zorblax.com")
+	none := scoring.TrademarkResult{Type: "none"}
+	live := usp.LookupResult{Checked: true, Similar: []usp.Mark{{Mark: "ZORBLOX", IsLive: true, Similarity: 0.86}}}
+	dead := usp.LookupResult{Checked: true, Similar: []usp.Mark{{Mark: "ZORBLOX", Similarity: 0.86}}}
+
+	tests := []struct {
+		name        string
+		similarOnly int
+		lookup      usp.LookupResult
+		wantType    string
+		wantScore   int
+	}{
+		{"disabled", 0, live, "none", 0},
+		{"live similar mark", 2, live, scoring.TrademarkTypeSimilarOnly, 2},
+		{"only dead marks", 2, dead, "none", 0},
+		{"lookup not checked", 2, usp.LookupResult{Similar: live.Similar}, "none", 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &Server{similarOnly: tc.similarOnly}
+			result, closeMatches := s.resolveTrademark(profile, true, tc.lookup, none)
+			if result.Type != tc.wantType || result.Score != tc.wantScore {
+				t.Fatalf("expected %s/%d got %s/%d", tc.wantType, tc.wantScore, result.Type, result.Score)
+			}
+			if result.MatchedTrademark != "" {
+				t.Fatalf("expected no matched trademark, got %q", result.MatchedTrademark)
+			}
+			if tc.lookup.Checked && (len(closeMatches) != 1 || closeMatches[0] != "ZORBLOX") {
+				t.Fatalf("expected the similar mark as a close match, got %v", closeMatches)
+			}
+		})
+	}
+
+	if _, err := NewServer(Config{DBPath: filepath.Join(t.TempDir(), "test.db"), SilentDB: true, FakeAI: true, SimilarOnlyScore: 3}); err == nil {
+		t.Fatal("expected a similar-only score above 2 to be rejected")
+	}
+}
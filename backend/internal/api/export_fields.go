@@ -0,0 +1,172 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// csvExportColumn is one column of the CSV export and how it is rendered from an evaluation.
+type csvExportColumn struct {
+	name  string
+	value func(EvaluationDTO) string
+}
+
+// csvExportColumns are the CSV export's columns in their default order.
+var csvExportColumns = []csvExportColumn{
+	{"domain", func(d EvaluationDTO) string { return d.Domain }},
+	{"trademark_score", func(d EvaluationDTO) string { return strconv.Itoa(d.TrademarkScore) }},
+	{"trademark_type", func(d EvaluationDTO) string { return d.TrademarkType }},
+	{"matched_trademark", func(d EvaluationDTO) string { return d.MatchedTrademark }},
+	{"vice_score", func(d EvaluationDTO) string { return strconv.Itoa(d.ViceScore) }},
+	{"vice_categories", func(d EvaluationDTO) string { return strings.Join(d.ViceCategories, "|") }},
+	{"overall_recommendation", func(d EvaluationDTO) string { return d.OverallRecommendation }},
+	{"confidence", func(d EvaluationDTO) string { return fmt.Sprintf("%.2f", d.Confidence) }},
+	{"ai_explanation", func(d EvaluationDTO) string { return d.Explanation }},
+	{"commercial_override", func(d EvaluationDTO) string { return strconv.FormatBool(d.CommercialOverride) }},
+	{"commercial_source", func(d EvaluationDTO) string { return d.CommercialSource }},
+	{"commercial_similarity", func(d EvaluationDTO) string { return fmt.Sprintf("%.2f", d.CommercialSimilarity) }},
+	// Columns added since the original export go last, in the order they were added, so
+	// consumers reading the CSV by position keep working.
+	{"trademark_source", func(d EvaluationDTO) string { return d.TrademarkSource }},
+	{"commercial_price", func(d EvaluationDTO) string { return fmt.Sprintf("%.0f", d.CommercialPrice) }},
+	{"matched_owner", func(d EvaluationDTO) string { return d.MatchedOwner }},
+	{"matched_serial", func(d EvaluationDTO) string { return d.MatchedSerial }},
+	{"matched_registration", func(d EvaluationDTO) string { return d.MatchedRegistration }},
+	{"commercial_sld", func(d EvaluationDTO) string {
+		if d.CommercialMatch == nil {
+			return ""
+		}
+		return d.CommercialMatch.SLD
+	}},
+}
+
+// csvExportSelection resolves the fields query parameter into CSV columns, in the order given.
+// An empty value selects every column.
+func csvExportSelection(raw string) ([]csvExportColumn, error) {
+	names := make([]string, len(csvExportColumns))
+	byName := make(map[string]csvExportColumn, len(csvExportColumns))
+	for i, col := range csvExportColumns {
+		names[i] = col.name
+		byName[col.name] = col
+	}
+	selected, err := selectExportFields(raw, names)
+	if err != nil || selected == nil {
+		return csvExportColumns, err
+	}
+	columns := make([]csvExportColumn, len(selected))
+	for i, name := range selected {
+		columns[i] = byName[name]
+	}
+	return columns, nil
+}
+
+// evaluationDTOFields maps each EvaluationDTO JSON name to its struct field index, in
+// declaration order.
+var evaluationDTOFields = func() []dtoField {
+	t := reflect.TypeOf(EvaluationDTO{})
+	fields := make([]dtoField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, dtoField{name: name, index: i})
+		}
+	}
+	return fields
+}()
+
+type dtoField struct {
+	name  string
+	index int
+}
+
+// jsonExportSelection resolves the fields query parameter into EvaluationDTO fields for the
+// JSON and NDJSON exports. An empty value returns nil, meaning the full DTO.
+func jsonExportSelection(raw string) ([]dtoField, error) {
+	names := make([]string, len(evaluationDTOFields))
+	byName := make(map[string]dtoField, len(evaluationDTOFields))
+	for i, field := range evaluationDTOFields {
+		names[i] = field.name
+		byName[field.name] = field
+	}
+	selected, err := selectExportFields(raw, names)
+	if err != nil || selected == nil {
+		return nil, err
+	}
+	fields := make([]dtoField, len(selected))
+	for i, name := range selected {
+		fields[i] = byName[name]
+	}
+	return fields, nil
+}
+
+// selectExportFields splits a comma-separated field list and checks it against known. It
+// returns nil for an empty list and rejects unknown or repeated names.
+func selectExportFields(raw string, known []string) ([]string, error) {
+	var selected []string
+	seen := make(map[string]struct{})
+	for _, part := range strings.Split(raw, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name == "" {
+			continue
+		}
+		if !containsString(known, name) {
+			return nil, fmt.Errorf("unknown export field %q; expected any of %s", name, strings.Join(known, ", "))
+		}
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("export field %q listed more than once", name)
+		}
+		seen[name] = struct{}{}
+		selected = append(selected, name)
+	}
+	return selected, nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+// exportProjection is an EvaluationDTO reduced to the selected fields. It marshals them in the
+// selected order and, unlike the DTO, includes empty omitempty fields that were asked for.
+type exportProjection struct {
+	dto    EvaluationDTO
+	fields []dtoField
+}
+
+// projectDTO returns dto limited to fields, or dto itself when fields is nil.
+func projectDTO(dto EvaluationDTO, fields []dtoField) any {
+	if fields == nil {
+		return dto
+	}
+	return exportProjection{dto: dto, fields: fields}
+}
+
+// MarshalJSON writes the selected fields as a JSON object.
+func (p exportProjection) MarshalJSON() ([]byte, error) {
+	value := reflect.ValueOf(p.dto)
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range p.fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(field.name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		encoded, err := json.Marshal(value.Field(field.index).Interface())
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(encoded)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"domain-risk-eval/backend/internal/store"
+)
+
+func TestExportFieldSelection(t *testing.T) {
+	columns, err := csvExportSelection("")
+	if err != nil || len(columns) != len(csvExportColumns) {
+		t.Fatalf("expected default columns, got %d (%v)", len(columns), err)
+	}
+	for _, raw := range []string{"domain,bogus", "domain,Domain", "explanation"} {
+		if _, err := csvExportSelection(raw); err == nil {
+			t.Fatalf("%q: expected an error", raw)
+		}
+	}
+	if _, err := jsonExportSelection("ai_explanation"); err == nil {
+		t.Fatal("expected CSV-only column to be rejected for JSON")
+	}
+
+	fields, err := jsonExportSelection(" overall_recommendation, domain ,matched_serial,")
+	if err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	encoded, err := json.Marshal(projectDTO(EvaluationDTO{Domain: "alpha.com", OverallRecommendation: "BLOCK"}, fields))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if want := `{"overall_recommendation":"BLOCK","domain":"alpha.com","matched_serial":""}`; string(encoded) != want {
+		t.Fatalf("expected %s got %s", want, encoded)
+	}
+}
+
+func TestHandleExportCSVFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := newOfflineServer(t)
+	eval := &store.Evaluation{Domain: "alpha.com", DomainNormalized: "alpha.com", TrademarkScore: 4, OverallRecommendation: "REVIEW"}
+	eval.SetCommercialMatch(&store.CommercialMatch{Source: "sale", SLD: "alfa", Price: 1200, Similarity: 0.9})
+	if err := s.db.SaveEvaluation(eval); err != nil {
+		t.Fatalf("save evaluation: %v", err)
+	}
+
+	export := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/export.csv?"+query, nil)
+		s.handleExportCSV(c)
+		return w
+	}
+
+	w := export("fields=overall_recommendation,domain,trademark_score")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d: %s", w.Code, w.Body.String())
+	}
+	if want := "overall_recommendation,domain,trademark_score\nREVIEW,alpha.com,4\n"; w.Body.String() != want {
+		t.Fatalf("expected %q got %q", want, w.Body.String())
+	}
+	if got, want := export("fields=commercial_source,commercial_sld").Body.String(), "commercial_source,commercial_sld\nsale $1200,alfa\n"; got != want {
+		t.Fatalf("expected %q got %q", want, got)
+	}
+	if w := export("fields=domain,nope"); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown field, got %d", w.Code)
+	}
+	// The original columns keep their positions; later additions are appended.
+	const wantHeader = "domain,trademark_score,trademark_type,matched_trademark,vice_score,vice_categories," +
+		"overall_recommendation,confidence,ai_explanation,commercial_override,commercial_source,commercial_similarity," +
+		"trademark_source,commercial_price,matched_owner,matched_serial,matched_registration,commercial_sld"
+	if header, _, _ := strings.Cut(export("").Body.String(), "\n"); header != wantHeader {
+		t.Fatalf("expected default header %q, got %q", wantHeader, header)
+	}
+}
+
+func TestExportBatchID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		query   string
+		want    uint
+		wantErr bool
+	}{
+		{query: "", want: 0},
+		{query: "?batch_id=7", want: 7},
+		{query: "?batchId=%207%20", want: 7},
+		{query: "?batch_id=0", wantErr: true},
+		{query: "?batch_id=seven", wantErr: true},
+		{query: "?batch_id=99999999999", wantErr: true},
+	}
+	for _, tc := range cases {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/export/csv"+tc.query, nil)
+		got, err := exportBatchID(c)
+		if tc.wantErr {
+			if err == nil {
+				t.Fatalf("%q: expected an error, got %d", tc.query, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Fatalf("%q: expected %d, got %d (%v)", tc.query, tc.want, got, err)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"domain-risk-eval/backend/internal/scoring"
+	"domain-risk-eval/backend/internal/store"
+)
+
+// maxGroundTruthMismatches caps the mismatches listed in a comparison report.
+const maxGroundTruthMismatches = 1000
+
+// labeledDomain is one row of a ground-truth CSV.
+type labeledDomain struct {
+	domain string
+	key    string
+	// row is the domain's line in the file.
+	row      int
+	expected scoring.Recommendation
+}
+
+// parseLabeledCSV reads domain and expected-recommendation pairs from the CSV at path. The domain
+// column is detected like parseDomainCSV; the label column is the first header named like
+// "expected" or "recommendation". Without a header the first two columns are used. Rows with a
+// blank domain or label are ignored; an unknown label is an error naming its line in the file.
+func parseLabeledCSV(path string, maxRows int, comment rune) ([]labeledDomain, error) {
+	var labels []labeledDomain
+	labelCol := 1
+	err := eachCSVRecord(path, comment, func(record []string, _ int) error {
+		if labelCol = detectLabelColumn(record); labelCol < 0 {
+			return errors.New("csv header has no expected recommendation column")
+		}
+		return nil
+	}, func(record []string, domainCol, line int) error {
+		if domainCol >= len(record) || labelCol >= len(record) {
+			return nil
+		}
+		value := strings.TrimSpace(record[domainCol])
+		rawLabel := strings.TrimSpace(record[labelCol])
+		if value == "" || rawLabel == "" {
+			return nil
+		}
+		if maxRows > 0 && len(labels) >= maxRows {
+			return errTooManyRows
+		}
+		expected, err := scoring.ParseRecommendation(rawLabel)
+		if err != nil {
+			return fmt.Errorf("line %d: %w; expected one of %s", line, err, strings.Join(scoring.RecommendationNames(), ", "))
+		}
+		labels = append(labels, labeledDomain{domain: value, key: domainKey(value), row: line, expected: expected})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+func detectLabelColumn(record []string) int {
+	for idx, value := range record {
+		normalized := strings.ToLower(strings.TrimSpace(value))
+		switch normalized {
+		case "expected", "expected_recommendation", "recommendation", "label", "ground_truth":
+			return idx
+		}
+	}
+	return -1
+}
+
+// compareGroundTruth scores evals, keyed by normalized domain, against labels.
+func compareGroundTruth(batchID uint, labels []labeledDomain, evals map[string]store.Evaluation) GroundTruthReport {
+	report := GroundTruthReport{
+		BatchID:    batchID,
+		Confusion:  make(map[string]map[string]int, len(scoring.Recommendations)),
+		Mismatches: []GroundTruthMismatch{},
+	}
+	for _, rec := range scoring.Recommendations {
+		report.Confusion[string(rec)] = make(map[string]int, len(scoring.Recommendations))
+	}
+
+	support := make(map[string]int)
+	predicted := make(map[string]int)
+	seen := make(map[string]struct{}, len(labels))
+	for _, label := range labels {
+		if _, ok := seen[label.key]; ok {
+			report.DuplicateLabels++
+			continue
+		}
+		seen[label.key] = struct{}{}
+		report.Labeled++
+
+		eval, ok := evals[label.key]
+		if !ok {
+			report.Unevaluated++
+			continue
+		}
+		expected := string(label.expected)
+		actual := eval.OverallRecommendation
+		report.Compared++
+		report.Confusion[expected][actual]++
+		support[expected]++
+		predicted[actual]++
+		if expected == actual {
+			report.Correct++
+			continue
+		}
+		if len(report.Mismatches) >= maxGroundTruthMismatches {
+			report.MismatchesTruncated = true
+			continue
+		}
+		report.Mismatches = append(report.Mismatches, GroundTruthMismatch{
+			Domain:         label.domain,
+			Row:            label.row,
+			Expected:       expected,
+			Actual:         actual,
+			TrademarkScore: eval.TrademarkScore,
+			ViceScore:      eval.ViceScore,
+		})
+	}
+
+	report.Accuracy = ratio(report.Correct, report.Compared)
+	for _, rec := range scoring.Recommendations {
+		name := string(rec)
+		tp := report.Confusion[name][name]
+		report.Classes = append(report.Classes, ClassMetrics{
+			Recommendation: name,
+			Support:        support[name],
+			Predicted:      predicted[name],
+			TruePositives:  tp,
+			Precision:      ratio(tp, predicted[name]),
+			Recall:         ratio(tp, support[name]),
+		})
+	}
+	return report
+}
+
+// ratio returns num/den rounded to four places, or nil when den is zero.
+func ratio(num, den int) *float64 {
+	if den == 0 {
+		return nil
+	}
+	value := math.Round(float64(num)/float64(den)*10000) / 10000
+	return &value
+}
+
+// handleCompareGroundTruth compares a batch's stored recommendations with a labeled CSV uploaded
+// as the labels form file, for calibrating seeds, vice terms, and thresholds.
+func (s *Server) handleCompareGroundTruth(c *gin.Context) {
+	batchID, err := parseUintParam(c.Param("id"))
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+	if _, err := s.db.GetCSVBatch(batchID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.renderError(c, http.StatusNotFound, fmt.Errorf("batch %d not found", batchID))
+		} else {
+			s.renderError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	path, _, cleanup, ok := s.receiveCSV(c, "labels")
+	if !ok {
+		return
+	}
+	defer cleanup()
+
+	labels, err := parseLabeledCSV(path, s.uploadRows, s.csvComment)
+	if err != nil {
+		if errors.Is(err, errTooManyRows) {
+			err = fmt.Errorf("%w (limit %d)", err, s.uploadRows)
+		}
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+	if len(labels) == 0 {
+		s.renderError(c, http.StatusBadRequest, errors.New("no labeled domains detected in csv"))
+		return
+	}
+
+	rows, _, err := s.db.ListEvaluations(store.EvaluationQuery{Limit: -1, BatchID: batchID})
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	evals := make(map[string]store.Evaluation, len(rows))
+	for _, row := range rows {
+		evals[row.DomainNormalized] = row
+	}
+	c.JSON(http.StatusOK, compareGroundTruth(batchID, labels, evals))
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"domain-risk-eval/backend/internal/store"
+)
+
+func TestCompareGroundTruth(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "labels.csv")
+	body := "\ufeffDomain,Expected\nalpha.com,block\nbeta.com,ALLOW\ngamma.com,review\nAlpha.com,ALLOW\ndelta.com,allow\n"
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	labels, err := parseLabeledCSV(path, 0, defaultCSVComment)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(labels) != 5 {
+		t.Fatalf("expected 5 labels, got %d", len(labels))
+	}
+
+	evals := map[string]store.Evaluation{
+		"alpha.com": {Domain: "alpha.com", OverallRecommendation: "BLOCK"},
+		"beta.com":  {Domain: "beta.com", OverallRecommendation: "REVIEW", TrademarkScore: 3},
+		"gamma.com": {Domain: "gamma.com", OverallRecommendation: "REVIEW"},
+	}
+	report := compareGroundTruth(7, labels, evals)
+	if report.Labeled != 4 || report.DuplicateLabels != 1 || report.Unevaluated != 1 || report.Compared != 3 || report.Correct != 2 {
+		t.Fatalf("unexpected totals %+v", report)
+	}
+	if report.Confusion["ALLOW"]["REVIEW"] != 1 || report.Confusion["BLOCK"]["BLOCK"] != 1 {
+		t.Fatalf("unexpected confusion matrix %v", report.Confusion)
+	}
+	if len(report.Mismatches) != 1 || report.Mismatches[0].Domain != "beta.com" || report.Mismatches[0].Row != 3 {
+		t.Fatalf("unexpected mismatches %+v", report.Mismatches)
+	}
+	for _, class := range report.Classes {
+		switch class.Recommendation {
+		case "REVIEW":
+			if class.Precision == nil || *class.Precision != 0.5 || class.Recall == nil || *class.Recall != 1 {
+				t.Fatalf("unexpected REVIEW metrics %+v", class)
+			}
+		case "ALLOW":
+			if class.Precision != nil || class.Recall == nil || *class.Recall != 0 {
+				t.Fatalf("unexpected ALLOW metrics %+v", class)
+			}
+		}
+	}
+}
+
+func TestParseLabeledCSVRejectsUnknownLabel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "labels.csv")
+	if err := os.WriteFile(path, []byte("alpha.com,BLOCK\nbeta.com,maybe\n"), 0o600); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	if _, err := parseLabeledCSV(path, 0, defaultCSVComment); err == nil {
+		t.Fatal("expected an error for an unknown label")
+	}
+}
+
+func TestParseLabeledCSVRowsAreFileLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "labels.csv")
+	body := "# exported labels\ndomain,expected\n\nalpha.com,BLOCK\n,ALLOW\n# note\nbeta.com,maybe\n"
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	_, err := parseLabeledCSV(path, 0, defaultCSVComment)
+	if err == nil || !strings.HasPrefix(err.Error(), "line 7:") {
+		t.Fatalf("expected the unknown label reported on line 7, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Replace(body, "maybe", "allow", 1)), 0o600); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	labels, err := parseLabeledCSV(path, 0, defaultCSVComment)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(labels) != 2 || labels[0].row != 4 || labels[1].row != 7 {
+		t.Fatalf("expected labels on lines 4 and 7, got %+v", labels)
+	}
+}
+
+func TestHandleCompareGroundTruthRejectsOversizedLabels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := newOfflineServer(t)
+	s.uploadMax = 16
+	batch, err := s.db.CreateCSVBatch("labels", "", "labels.csv")
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("labels", "labels.csv")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	part.Write([]byte("alpha.com,BLOCK\nbeta.com,ALLOW\n"))
+	form.Close()
+
+	id := strconv.FormatUint(uint64(batch.ID), 10)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/batches/"+id+"/compare", &body)
+	c.Request.Header.Set("Content-Type", form.FormDataContentType())
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	s.handleCompareGroundTruth(c)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	idempotencyHeader = "Idempotency-Key"
+	// uploadKeyWindow is how long a retried upload replays the original batch.
+	uploadKeyWindow         = 24 * time.Hour
+	maxIdempotencyKeyLength = 100
+)
+
+// uploadKey identifies an upload for replay. name is empty when the upload is not deduplicated;
+// hash fingerprints the file contents together with the batch fields.
+type uploadKey struct {
+	name string
+	hash string
+}
+
+// uploadIdempotencyKey returns the key identifying an upload: the caller's Idempotency-Key header
+// when present, otherwise, with UploadDedupe on, the hash of the file and batch fields, so a plain
+// retry of the same request is recognised too.
+func (s *Server) uploadIdempotencyKey(c *gin.Context, path, batchName, owner string, mergeID uint) (uploadKey, error) {
+	key := strings.TrimSpace(c.GetHeader(idempotencyHeader))
+	if len(key) > maxIdempotencyKeyLength {
+		return uploadKey{}, fmt.Errorf("%s exceeds %d characters", idempotencyHeader, maxIdempotencyKeyLength)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return uploadKey{}, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return uploadKey{}, err
+	}
+	fmt.Fprintf(h, "\x00%s\x00%s\x00%d", batchName, owner, mergeID)
+	hash := hex.EncodeToString(h.Sum(nil))
+	switch {
+	case key != "":
+		return uploadKey{name: "key:" + key, hash: hash}, nil
+	case s.uploadDedupe:
+		return uploadKey{name: "sha256:" + hash, hash: hash}, nil
+	}
+	return uploadKey{hash: hash}, nil
+}
+
+// replayUpload answers with the batch recorded for key and reports whether it did. A key reused
+// with a different file or batch fields is rejected with 422. Lookup errors are logged and
+// treated as a miss so the upload proceeds normally.
+func (s *Server) replayUpload(c *gin.Context, key uploadKey) bool {
+	if key.name == "" {
+		return false
+	}
+	recorded, err := s.db.FindUploadKey(key.name, time.Now().Add(-uploadKeyWindow))
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			requestLogger(c).WithError(err).Warn("lookup upload idempotency key")
+		}
+		return false
+	}
+	batch, err := s.db.GetCSVBatch(recorded.BatchID)
+	if err != nil {
+		// The batch was deleted since; treat the key as stale.
+		return false
+	}
+	if recorded.BodyHash != "" && recorded.BodyHash != key.hash {
+		s.renderError(c, http.StatusUnprocessableEntity, fmt.Errorf("%s was already used for a different upload", idempotencyHeader))
+		return true
+	}
+	marksCount, err := s.db.CountMarks()
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return true
+	}
+	processed, err := s.db.CountBatchResults(batch.ID)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return true
+	}
+
+	requestLogger(c).WithField("batch_id", batch.ID).Info("replaying idempotent upload")
+	c.JSON(http.StatusOK, UploadResponse{
+		BatchID:         batch.ID,
+		BatchName:       batch.Name,
+		Owner:           batch.Owner,
+		RowCount:        batch.RowCount,
+		UniqueDomains:   batch.UniqueDomains,
+		ExistingDomains: batch.ExistingDomains,
+		DuplicateRows:   batch.DuplicateRows,
+		Processed:       processed,
+		MarksCount:      int(marksCount),
+		Merged:          recorded.Merged,
+		Replayed:        true,
+	})
+	return true
+}
+
+// rememberUpload records the batch an upload produced under key. Failures only cost the
+// protection against duplicate retries, so they are logged rather than returned.
+func (s *Server) rememberUpload(c *gin.Context, key uploadKey, batchID uint, merged bool) {
+	if key.name == "" {
+		return
+	}
+	now := time.Now()
+	if err := s.db.SaveUploadKey(key.name, key.hash, batchID, merged, now.Add(-uploadKeyWindow)); err != nil {
+		requestLogger(c).WithError(err).Warn("save upload idempotency key")
+	}
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandleUploadIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := newOfflineServer(t)
+
+	upload := func(key, csv string) (int, UploadResponse) {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		form.WriteField("batch_name", "retry")
+		form.WriteField("owner_name", "ops")
+		part, err := form.CreateFormFile("domains", "domains.csv")
+		if err != nil {
+			t.Fatalf("create form file: %v", err)
+		}
+		part.Write([]byte(csv))
+		form.Close()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/upload", &body)
+		c.Request.Header.Set("Content-Type", form.FormDataContentType())
+		if key != "" {
+			c.Request.Header.Set(idempotencyHeader, key)
+		}
+		s.handleUpload(c)
+		var resp UploadResponse
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+		}
+		return w.Code, resp
+	}
+
+	status, first := upload("", "domain\nalpha.com\n")
+	if status != http.StatusOK {
+		t.Fatalf("expected 200 got %d", status)
+	}
+	if _, again := upload("", "domain\nalpha.com\n"); again.Replayed || again.BatchID == first.BatchID {
+		t.Fatalf("expected a deliberate re-upload to create a new batch, got %+v", again)
+	}
+
+	_, keyed := upload("retry-1", "domain\nbeta.com\n")
+	if _, replay := upload("retry-1", "domain\nbeta.com\n"); !replay.Replayed || replay.BatchID != keyed.BatchID {
+		t.Fatalf("expected the retry to replay batch %d, got %+v", keyed.BatchID, replay)
+	}
+	if status, _ := upload("retry-1", "domain\ngamma.com\n"); status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for a reused key with another file, got %d", status)
+	}
+
+	s.uploadDedupe = true
+	_, hashed := upload("", "domain\ndelta.com\n")
+	if _, replay := upload("", "domain\ndelta.com\n"); !replay.Replayed || replay.BatchID != hashed.BatchID {
+		t.Fatalf("expected UploadDedupe to replay batch %d, got %+v", hashed.BatchID, replay)
+	}
+}
+
+func TestHandleUploadValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := newOfflineServer(t)
+
+	tests := []struct {
+		name   string
+		fields map[string]string
+		file   bool
+		want   string
+	}{
+		{"missing file", map[string]string{"batch_name": "b", "owner_name": "o"}, false, "domains"},
+		{"missing names", map[string]string{}, true, "batch_name,owner_name"},
+		{"bad batch id", map[string]string{"batch_id": "-4"}, true, "batch_id"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			for name, value := range tc.fields {
+				form.WriteField(name, value)
+			}
+			if tc.file {
+				part, err := form.CreateFormFile("domains", "domains.csv")
+				if err != nil {
+					t.Fatalf("create form file: %v", err)
+				}
+				part.Write([]byte("domain\nalpha.com\n"))
+			}
+			form.Close()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/upload", &body)
+			c.Request.Header.Set("Content-Type", form.FormDataContentType())
+			s.handleUpload(c)
+
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("expected 422 got %d: %s", w.Code, w.Body.String())
+			}
+			var resp ValidationErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			names := make([]string, 0, len(resp.Fields))
+			for _, field := range resp.Fields {
+				names = append(names, field.Field)
+			}
+			if got := strings.Join(names, ","); got != tc.want {
+				t.Fatalf("expected invalid fields %q got %q", tc.want, got)
+			}
+		})
+	}
+}
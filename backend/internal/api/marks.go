@@ -0,0 +1,80 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"domain-risk-eval/backend/internal/store"
+)
+
+func (s *Server) handleListMarks(c *gin.Context) {
+	_, pageSize, offset := pageParams(c, s.marksPage)
+
+	rows, total, err := s.db.ListMarks(store.MarkQuery{
+		Query:    strings.TrimSpace(c.Query("q")),
+		Contains: strings.EqualFold(strings.TrimSpace(c.Query("match")), "contains"),
+		Offset:   offset,
+		Limit:    pageSize,
+	})
+	if errors.Is(err, store.ErrEmptyMarkQuery) {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	dtos := make([]MarkDTO, 0, len(rows))
+	for _, row := range rows {
+		dtos = append(dtos, MarkFromModel(row))
+	}
+	c.JSON(http.StatusOK, MarksResponse{Items: dtos, Total: total})
+}
+
+// handleListPopularMarks returns the popular_marks aggregation that both feeds the scoring corpus
+// and drives popularity, so operators can sanity-check it after an ingest. limit is clamped like
+// the marks page size.
+func (s *Server) handleListPopularMarks(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	limit = s.marksPage.clamp(limit)
+
+	rows, err := s.db.ListPopularMarks(limit)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	items := make([]PopularMarkDTO, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, PopularMarkDTO{
+			Normalized: row.Normalized,
+			Mark:       row.Mark,
+			Total:      row.Total,
+			UpdatedAt:  row.UpdatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, PopularMarksResponse{Items: items, Limit: limit})
+}
+
+func (s *Server) handleGetMark(c *gin.Context) {
+	serial := strings.TrimSpace(c.Param("serial"))
+	if serial == "" {
+		s.renderError(c, http.StatusBadRequest, errors.New("serial is required"))
+		return
+	}
+	mark, err := s.db.GetMark(serial)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.renderError(c, http.StatusNotFound, fmt.Errorf("mark %s not found", serial))
+		} else {
+			s.renderError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, MarkFromModel(*mark))
+}
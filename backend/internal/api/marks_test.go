@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"domain-risk-eval/backend/internal/store"
+)
+
+func TestHandleListMarksContains(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := newOfflineServer(t)
+	for _, mark := range []store.Mark{
+		{Serial: "1", Mark: "A_B", MarkNormalized: "a_b", MarkNoSpaces: "ab"},
+		{Serial: "2", Mark: "AXB", MarkNormalized: "axb", MarkNoSpaces: "axb"},
+		{Serial: "3", Mark: "100% PURE", MarkNormalized: "100% pure", MarkNoSpaces: "100pure"},
+	} {
+		if err := s.db.UpsertMark(&mark); err != nil {
+			t.Fatalf("upsert mark: %v", err)
+		}
+	}
+
+	list := func(q string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/marks?match=contains&q="+url.QueryEscape(q), nil)
+		s.handleListMarks(c)
+		return w
+	}
+
+	for _, q := range []string{"%", "_", "- -"} {
+		if w := list(q); w.Code != http.StatusBadRequest {
+			t.Fatalf("%q: expected 400 got %d", q, w.Code)
+		}
+	}
+	w := list("a_b")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d: %s", w.Code, w.Body.String())
+	}
+	var resp MarksResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Total != 1 || resp.Items[0].Serial != "1" {
+		t.Fatalf("expected only the literal a_b mark, got %+v", resp.Items)
+	}
+}
+
+func TestHandleListPopularMarks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := newOfflineServer(t)
+	s.marksPage = PageLimits{Default: 2, Max: 3}
+	if err := s.db.ReplacePopularMarks([]store.PopularMark{
+		{Normalized: "acme", Mark: "ACME", Total: 12},
+		{Normalized: "zorblax", Mark: "ZORBLAX", Total: 40},
+		{Normalized: "quibbly", Mark: "QUIBBLY", Total: 7},
+		{Normalized: "vexo", Mark: "VEXO", Total: 3},
+	}); err != nil {
+		t.Fatalf("replace popular marks: %v", err)
+	}
+
+	tests := []struct {
+		query string
+		limit int
+		first []string
+	}{
+		{"", 2, []string{"zorblax", "acme"}},
+		{"?limit=1", 1, []string{"zorblax"}},
+		{"?limit=99", 3, []string{"zorblax", "acme", "quibbly"}},
+		{"?limit=abc", 2, []string{"zorblax", "acme"}},
+	}
+	for _, tc := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/popular"+tc.query, nil)
+		s.handleListPopularMarks(c)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%q: expected 200 got %d: %s", tc.query, w.Code, w.Body.String())
+		}
+		var resp PopularMarksResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		got := make([]string, 0, len(resp.Items))
+		for _, item := range resp.Items {
+			got = append(got, item.Normalized)
+		}
+		if resp.Limit != tc.limit || !reflect.DeepEqual(got, tc.first) {
+			t.Fatalf("%q: expected limit %d with %v, got limit %d with %v", tc.query, tc.limit, tc.first, resp.Limit, got)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	narrativeWindowSize      = 50
+	narrativeRepeatThreshold = 0.5
+	narrativeShingleSize     = 3
+)
+
+// narrativeWindow keeps word n-gram shingles of recently produced narratives so near-duplicates
+// can be detected within a run.
+type narrativeWindow struct {
+	mu     sync.Mutex
+	size   int
+	recent []map[string]struct{}
+	next   int
+}
+
+func newNarrativeWindow(size int) *narrativeWindow {
+	return &narrativeWindow{size: size}
+}
+
+// similarity returns the highest Jaccard overlap between the narrative and the window contents.
+func (w *narrativeWindow) similarity(narrative string) float64 {
+	shingles := narrativeShingles(narrative)
+	if len(shingles) == 0 {
+		return 0
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	best := 0.0
+	for _, other := range w.recent {
+		if score := jaccard(shingles, other); score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+// add records the narrative, evicting the oldest entry once the window is full.
+func (w *narrativeWindow) add(narrative string) {
+	shingles := narrativeShingles(narrative)
+	if len(shingles) == 0 {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.recent) < w.size {
+		w.recent = append(w.recent, shingles)
+		return
+	}
+	w.recent[w.next] = shingles
+	w.next = (w.next + 1) % w.size
+}
+
+func narrativeShingles(narrative string) map[string]struct{} {
+	words := strings.FieldsFunc(strings.ToLower(narrative), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) < narrativeShingleSize {
+		return nil
+	}
+	out := make(map[string]struct{}, len(words))
+	for i := 0; i+narrativeShingleSize <= len(words); i++ {
+		out[strings.Join(words[i:i+narrativeShingleSize], " ")] = struct{}{}
+	}
+	return out
+}
+
+func jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for key := range a {
+		if _, ok := b[key]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PageLimits bounds a client-supplied page size or limit: Default applies when the client sends
+// none, and larger requests are clamped to Max.
+type PageLimits struct {
+	Default int
+	Max     int
+}
+
+// Limits applied when Config leaves them unset.
+var (
+	defaultResultsPage   = PageLimits{Default: 100, Max: 1000}
+	defaultBatchesPage   = PageLimits{Default: 25, Max: 200}
+	defaultMarksPage     = PageLimits{Default: 50, Max: 500}
+	defaultEvaluateLimit = PageLimits{Default: 5000, Max: 5000}
+)
+
+// defaultMarksLimit caps how many marks are loaded into the trademark index when
+// Config.MarksLimit is unset.
+const defaultMarksLimit = 500000
+
+// withDefaults fills unset fields from fallback and keeps Default within Max.
+func (l PageLimits) withDefaults(fallback PageLimits) PageLimits {
+	if l.Max <= 0 {
+		l.Max = max(fallback.Max, l.Default)
+	}
+	if l.Default <= 0 {
+		l.Default = fallback.Default
+	}
+	l.Default = min(l.Default, l.Max)
+	return l
+}
+
+// clamp returns requested bounded by l, or l.Default when requested is not positive.
+func (l PageLimits) clamp(requested int) int {
+	if requested <= 0 {
+		return l.Default
+	}
+	return min(requested, l.Max)
+}
+
+// pageParams reads the page and pageSize query parameters, clamping pageSize to limits, and
+// returns them with the row offset of the page.
+func pageParams(c *gin.Context, limits PageLimits) (page, pageSize, offset int) {
+	page, _ = strconv.Atoi(c.Query("page"))
+	if page < 0 {
+		page = 0
+	}
+	pageSize, _ = strconv.Atoi(c.Query("pageSize"))
+	pageSize = limits.clamp(pageSize)
+	// Keep the offset from overflowing; such a page is past the end of any table anyway.
+	page = min(page, math.MaxInt32/pageSize)
+	return page, pageSize, page * pageSize
+}
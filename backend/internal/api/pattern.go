@@ -0,0 +1,215 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"domain-risk-eval/backend/internal/match"
+	"domain-risk-eval/backend/internal/store"
+	"domain-risk-eval/backend/internal/usp"
+)
+
+const (
+	// minPatternLiterals keeps patterns such as "*" or "*.*" from listing the whole domains table.
+	minPatternLiterals = 3
+	// maxCandidateEvaluations caps the candidates one request scores in-line, since each may call
+	// USPTO and the AI on the request goroutine.
+	maxCandidateEvaluations = 10
+)
+
+// PatternRequest asks for every domain matching Pattern, a glob over normalized domains where *
+// matches any run of characters and ? a single one (e.g. "*-paypal.com" or "*apple*"). Without
+// Candidates the pattern is matched against uploaded domains; with Candidates, those matching the
+// pattern (all of them when Pattern is empty) are looked up and, when never evaluated, scored
+// on the fly without being stored; at most 100 candidates are accepted per request, of which
+// maxCandidateEvaluations are scored.
+type PatternRequest struct {
+	Pattern        string   `json:"pattern" binding:"required_without=Candidates"`
+	Candidates     []string `json:"candidates" binding:"max=100"`
+	Limit          int      `json:"limit" binding:"gte=0"`
+	SkipVice       bool     `json:"skip_vice"`
+	SkipUSPTO      bool     `json:"skip_uspto"`
+	SkipCommercial bool     `json:"skip_commercial"`
+	SkipAI         bool     `json:"skip_ai"`
+}
+
+// PatternMatchDTO is one domain matching a pattern request. Source is "stored" for an existing
+// evaluation, "evaluated" for a candidate scored by this request, and empty for an uploaded
+// domain that has not been evaluated yet; Reason explains a skipped candidate.
+type PatternMatchDTO struct {
+	Domain     string         `json:"domain"`
+	Source     string         `json:"source"`
+	Evaluation *EvaluationDTO `json:"evaluation"`
+	Reason     string         `json:"reason,omitempty"`
+}
+
+// PatternResponse lists the domains matching a pattern request. Total counts every match, of
+// which at most limit are returned.
+type PatternResponse struct {
+	Pattern string            `json:"pattern"`
+	Items   []PatternMatchDTO `json:"items"`
+	Total   int64             `json:"total"`
+	HasNext bool              `json:"has_next"`
+}
+
+// validatePattern normalizes a domain pattern and rejects ones that are too broad or use glob
+// syntax beyond * and ?.
+func validatePattern(pattern string) (string, []FieldError) {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "" {
+		return "", nil
+	}
+	if strings.ContainsAny(pattern, "[]\\") {
+		return "", []FieldError{{Field: "pattern", Message: "may only use * and ? as wildcards"}}
+	}
+	literals := 0
+	for _, r := range pattern {
+		if r != '*' && r != '?' && r != '.' {
+			literals++
+		}
+	}
+	if literals < minPatternLiterals {
+		return "", []FieldError{{Field: "pattern", Message: "must contain at least 3 literal characters"}}
+	}
+	return pattern, nil
+}
+
+// handleDomainPattern answers "show me everything that looks like brand X" across all uploads
+// rather than one batch at a time.
+func (s *Server) handleDomainPattern(c *gin.Context) {
+	var req PatternRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		s.renderBindingError(c, &req, err)
+		return
+	}
+	pattern, invalid := validatePattern(req.Pattern)
+	if len(invalid) > 0 {
+		s.renderValidation(c, invalid)
+		return
+	}
+	limit := s.resultsPage.clamp(req.Limit)
+
+	if len(req.Candidates) > 0 {
+		s.evaluateCandidates(c, req, pattern, limit)
+		return
+	}
+
+	keys, total, err := s.db.FindDomainsByPattern(pattern, limit)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	items, err := s.storedPatternMatches(keys)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, PatternResponse{
+		Pattern: pattern,
+		Items:   items,
+		Total:   total,
+		HasNext: int64(len(items)) < total,
+	})
+}
+
+// storedPatternMatches pairs normalized domains with their stored evaluations, keeping order.
+func (s *Server) storedPatternMatches(keys []string) ([]PatternMatchDTO, error) {
+	evaluations, err := s.db.EvaluationsByDomain(keys)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]store.Evaluation, len(evaluations))
+	for _, eval := range evaluations {
+		byKey[eval.DomainNormalized] = eval
+	}
+	items := make([]PatternMatchDTO, 0, len(keys))
+	for _, key := range keys {
+		item := PatternMatchDTO{Domain: key}
+		if eval, ok := byKey[key]; ok {
+			dto := FromModel(eval)
+			item.Domain, item.Source, item.Evaluation = eval.Domain, "stored", &dto
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// evaluateCandidates reports the candidates matching pattern, reusing stored evaluations and
+// scoring up to maxCandidateEvaluations of the rest in-line; AI calls share the evaluation jobs'
+// concurrency limit. Fresh results are not persisted: candidates belong to no batch.
+func (s *Server) evaluateCandidates(c *gin.Context, req PatternRequest, pattern string, limit int) {
+	var keys []string
+	seen := make(map[string]struct{}, len(req.Candidates))
+	for _, candidate := range req.Candidates {
+		key := domainKey(candidate)
+		if key == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		if pattern != "" {
+			if ok, _ := path.Match(pattern, key); !ok {
+				continue
+			}
+		}
+		keys = append(keys, key)
+	}
+	total := int64(len(keys))
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	items, err := s.storedPatternMatches(keys)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	scorer, marks, err := s.loadTrademarkScorer()
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	opts := newEvaluationOptions(EvaluateRequest{
+		SkipVice:       req.SkipVice,
+		SkipUSPTO:      req.SkipUSPTO,
+		SkipCommercial: req.SkipCommercial,
+		SkipAI:         req.SkipAI,
+	})
+	cache := make(map[string]usp.LookupResult)
+	evaluated := 0
+	for i := range items {
+		if items[i].Evaluation != nil {
+			continue
+		}
+		if evaluated >= maxCandidateEvaluations {
+			items[i].Reason = fmt.Sprintf("not evaluated: at most %d candidates are scored per request", maxCandidateEvaluations)
+			continue
+		}
+		evaluated++
+		task := store.BatchDomain{Domain: items[i].Domain, DomainNormalized: items[i].Domain}
+		res := s.evaluateDomain(c.Request.Context(), task, scorer, marks, total, cache, nil, opts)
+		if errors.Is(res.Err, match.ErrSkippable) {
+			items[i].Reason = match.SkipReason(res.Err)
+			continue
+		}
+		if res.Err != nil {
+			s.renderError(c, http.StatusInternalServerError, res.Err)
+			return
+		}
+		dto := FromModel(res.Evaluation)
+		items[i].Source, items[i].Evaluation = "evaluated", &dto
+	}
+	c.JSON(http.StatusOK, PatternResponse{
+		Pattern: pattern,
+		Items:   items,
+		Total:   total,
+		HasNext: int64(len(items)) < total,
+	})
+}
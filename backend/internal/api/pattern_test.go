@@ -0,0 +1,62 @@
+package api
+
+import (
+	"path"
+	"reflect"
+	"testing"
+
+	"domain-risk-eval/backend/internal/store"
+)
+
+func TestValidatePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+		ok      bool
+	}{
+		{" *-PayPal.com ", "*-paypal.com", true},
+		{"ab?c", "ab?c", true},
+		{"*", "", false},
+		{"*.*", "", false},
+		{"a?b*", "", false},
+		{"[ab]cde*", "", false},
+		{`abc\*`, "", false},
+	}
+	for _, tc := range tests {
+		got, invalid := validatePattern(tc.pattern)
+		if (len(invalid) == 0) != tc.ok || got != tc.want {
+			t.Fatalf("%q: expected %q (ok %v) got %q (%v)", tc.pattern, tc.want, tc.ok, got, invalid)
+		}
+	}
+}
+
+// TestPatternGlobMatchesPathMatch checks that the SQLite GLOB used for uploaded domains and the
+// path.Match used for candidates agree on validated patterns.
+func TestPatternGlobMatchesPathMatch(t *testing.T) {
+	s := newOfflineServer(t)
+	keys := []string{"apple.com", "mypaypal.net", "pay-pal.com", "pay.pal.com", "paypal.co.uk", "paypal.com", "paypal1.com"}
+	for _, key := range keys {
+		if err := s.db.SaveDomain(&store.Domain{Domain: key}); err != nil {
+			t.Fatalf("save domain: %v", err)
+		}
+	}
+	for _, raw := range []string{"*paypal*", "pay?pal.com", "*.com", "paypal.*", "*-pal.com", "paypal?.com", "*pal.co*"} {
+		pattern, invalid := validatePattern(raw)
+		if len(invalid) > 0 {
+			t.Fatalf("%q: unexpected validation error %v", raw, invalid)
+		}
+		stored, _, err := s.db.FindDomainsByPattern(pattern, len(keys))
+		if err != nil {
+			t.Fatalf("%q: find: %v", raw, err)
+		}
+		var matched []string
+		for _, key := range keys {
+			if ok, _ := path.Match(pattern, key); ok {
+				matched = append(matched, key)
+			}
+		}
+		if !reflect.DeepEqual(stored, matched) {
+			t.Fatalf("%q: GLOB matched %q but path.Match %q", pattern, stored, matched)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"domain-risk-eval/backend/internal/usp"
+)
+
+const (
+	// prewarmPageSize is how many batch rows each bulk USPTO prefetch covers.
+	prewarmPageSize = 200
+	// prewarmProgressInterval throttles prewarm progress broadcasts to every N domains or SLDs.
+	prewarmProgressInterval = 250
+)
+
+// prewarmCaches resolves the batch's distinct brand tokens against USPTO and its distinct SLDs
+// against the commercial index before the worker loop starts, so early domains do not pay for
+// cold caches. The two lookups run concurrently; failures only cost cache hits.
+func (s *Server) prewarmCaches(ctx context.Context, job *evaluationJob, opts evaluationOptions, skip map[string]struct{}, cache map[string]usp.LookupResult, cacheMu *sync.Mutex) {
+	warmUSPTO := s.usptoClient != nil && !opts.skipUSPTO
+	warmCommercial := s.commercial != nil && !opts.skipCommercial
+	if !warmUSPTO && !warmCommercial {
+		return
+	}
+
+	rows, err := job.listDomains(0, int(job.total))
+	if err != nil {
+		job.logger().WithError(err).Warn("prewarm: list batch domains")
+		return
+	}
+	pending := rows[:0]
+	slds := make([]string, 0, len(rows))
+	seenSLD := make(map[string]struct{}, len(rows))
+	for _, row := range rows {
+		if _, ok := skip[strings.TrimSpace(row.DomainNormalized)]; ok {
+			continue
+		}
+		pending = append(pending, row)
+		sld, _ := splitDomainParts(row.Domain)
+		if _, ok := seenSLD[sld]; ok || sld == "" {
+			continue
+		}
+		seenSLD[sld] = struct{}{}
+		slds = append(slds, sld)
+	}
+
+	var total int64
+	if warmUSPTO {
+		total += int64(len(pending))
+	}
+	if warmCommercial {
+		total += int64(len(slds))
+	}
+	if total == 0 {
+		return
+	}
+
+	start := time.Now()
+	s.evalNotifier.Broadcast(EvaluationEvent{
+		Type:    "prewarm_started",
+		JobID:   job.id,
+		BatchID: job.batchID,
+		Total:   total,
+		Message: "warming lookup caches",
+	})
+
+	var processed atomic.Int64
+	report := func(n int) {
+		done := processed.Add(int64(n))
+		if done/prewarmProgressInterval != (done-int64(n))/prewarmProgressInterval {
+			s.evalNotifier.Broadcast(EvaluationEvent{
+				Type:      "prewarm_progress",
+				JobID:     job.id,
+				BatchID:   job.batchID,
+				Total:     total,
+				Processed: int(done),
+			})
+		}
+	}
+
+	var wg sync.WaitGroup
+	if warmUSPTO {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := 0; start < len(pending) && ctx.Err() == nil; start += prewarmPageSize {
+				end := start + prewarmPageSize
+				if end > len(pending) {
+					end = len(pending)
+				}
+				page := pending[start:end]
+				s.prefetchUSPTO(ctx, page, nil, cache, cacheMu)
+				report(len(page))
+			}
+		}()
+	}
+	if warmCommercial {
+		sldCh := make(chan string)
+		for i := 0; i < determineWorkerCount(); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for sld := range sldCh {
+					s.commercial.BestMatch(sld)
+					report(1)
+				}
+			}()
+		}
+		go func() {
+			defer close(sldCh)
+			for _, sld := range slds {
+				select {
+				case sldCh <- sld:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	duration := time.Since(start).Round(time.Millisecond)
+	job.logger().WithFields(logrus.Fields{
+		"domains":  len(pending),
+		"slds":     len(slds),
+		"lookups":  processed.Load(),
+		"duration": duration,
+	}).Info("lookup caches prewarmed")
+	s.evalNotifier.Broadcast(EvaluationEvent{
+		Type:      "prewarm_complete",
+		JobID:     job.id,
+		BatchID:   job.batchID,
+		Total:     total,
+		Processed: int(processed.Load()),
+		Message:   fmt.Sprintf("warmed %d lookups in %s", processed.Load(), duration),
+	})
+}
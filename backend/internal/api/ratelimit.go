@@ -0,0 +1,132 @@
+package api
+
+import (
+	"container/list"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultRateLimitBurst is the bucket size used when Config.RateLimitBurst is unset.
+	defaultRateLimitBurst = 5
+	// maxTrackedClients bounds the bucket map; beyond it, buckets that have refilled are dropped
+	// since they carry no state a fresh bucket would not, and then the least recently used ones.
+	maxTrackedClients = 10000
+)
+
+// rateLimiter is a token-bucket limiter keyed by client: each key holds up to burst tokens,
+// refilled at perSecond, and every request spends one.
+type rateLimiter struct {
+	mu        sync.Mutex
+	perSecond float64
+	burst     float64
+	buckets   map[string]*list.Element
+	// recent orders the buckets by last use, most recent first.
+	recent     *list.List
+	maxClients int
+	now        func() time.Time
+}
+
+type tokenBucket struct {
+	key    string
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter returns a limiter allowing perMinute requests per key with bursts of up to burst,
+// or nil when perMinute is not positive.
+func newRateLimiter(perMinute float64, burst int) *rateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = defaultRateLimitBurst
+	}
+	return &rateLimiter{
+		perSecond:  perMinute / 60,
+		burst:      float64(burst),
+		buckets:    make(map[string]*list.Element),
+		recent:     list.New(),
+		maxClients: maxTrackedClients,
+		now:        time.Now,
+	}
+}
+
+// allow spends a token for key. When none is available it reports false and how long until one is.
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var bucket *tokenBucket
+	if elem, ok := l.buckets[key]; ok {
+		bucket = elem.Value.(*tokenBucket)
+		bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.perSecond)
+		bucket.last = now
+		l.recent.MoveToFront(elem)
+	} else {
+		if len(l.buckets) >= l.maxClients {
+			l.prune(now)
+		}
+		bucket = &tokenBucket{key: key, tokens: l.burst, last: now}
+		l.buckets[key] = l.recent.PushFront(bucket)
+	}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - bucket.tokens) / l.perSecond * float64(time.Second))
+	return false, wait
+}
+
+// perMinute reports the configured refill rate, or 0 for a nil (disabled) limiter.
+func (l *rateLimiter) perMinute() float64 {
+	if l == nil {
+		return 0
+	}
+	return l.perSecond * 60
+}
+
+// prune makes room for a new bucket. Starting from the least recently used, it drops buckets
+// that would be full by now, then keeps dropping until the map is below maxClients, so clients
+// rotating through addresses cannot grow it without bound. The caller holds l.mu.
+func (l *rateLimiter) prune(now time.Time) {
+	for elem := l.recent.Back(); elem != nil; elem = l.recent.Back() {
+		bucket := elem.Value.(*tokenBucket)
+		full := bucket.tokens+now.Sub(bucket.last).Seconds()*l.perSecond >= l.burst
+		if !full && len(l.buckets) < l.maxClients {
+			return
+		}
+		l.recent.Remove(elem)
+		delete(l.buckets, bucket.key)
+	}
+}
+
+// rateLimit returns middleware that answers 429 with Retry-After once a client IP exhausts
+// limiter. The IP is read from X-Forwarded-For only for requests from Config.TrustedProxies. A
+// nil limiter lets every request through.
+func (s *Server) rateLimit(limiter *rateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limiter == nil {
+			c.Next()
+			return
+		}
+		ok, wait := limiter.allow(c.ClientIP())
+		if ok {
+			c.Next()
+			return
+		}
+		retryAfter := int(math.Ceil(wait.Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		requestLogger(c).WithField("client_ip", c.ClientIP()).WithField("path", c.FullPath()).Warn("rate limit exceeded")
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded; retry in " + strconv.Itoa(retryAfter) + "s"})
+	}
+}
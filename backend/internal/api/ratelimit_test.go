@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newRateLimiter(60, 2)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.allow("a"); !ok {
+			t.Fatalf("request %d: expected the burst to allow it", i+1)
+		}
+	}
+	if ok, wait := l.allow("a"); ok || wait != time.Second {
+		t.Fatalf("expected a denial with a 1s wait, got %v/%v", ok, wait)
+	}
+	if ok, _ := l.allow("b"); !ok {
+		t.Fatal("expected another client to have its own bucket")
+	}
+	now = now.Add(time.Second)
+	if ok, _ := l.allow("a"); !ok {
+		t.Fatal("expected a token after a second")
+	}
+}
+
+func TestRateLimiterPrune(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newRateLimiter(60, 2)
+	l.now = func() time.Time { return now }
+	l.maxClients = 2
+
+	l.allow("a")
+	l.allow("b")
+	l.allow("a")
+	l.allow("c")
+	if _, ok := l.buckets["b"]; ok || len(l.buckets) != 2 {
+		t.Fatalf("expected the least recently used bucket b to be evicted, got %d buckets", len(l.buckets))
+	}
+	if ok, _ := l.allow("a"); ok {
+		t.Fatal("expected a's drained bucket to survive eviction")
+	}
+
+	now = now.Add(time.Minute)
+	l.allow("d")
+	if _, ok := l.buckets["d"]; !ok || len(l.buckets) != 1 {
+		t.Fatalf("expected refilled buckets to be dropped, got %d buckets", len(l.buckets))
+	}
+}
+
+func TestRateLimitExposesRetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s, err := NewServer(Config{DBPath: filepath.Join(t.TempDir(), "test.db"), SilentDB: true, FakeAI: true, UploadRateLimit: 1, RateLimitBurst: 1})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	t.Cleanup(func() { s.db.Close() })
+	router, err := s.Router()
+	if err != nil {
+		t.Fatalf("router: %v", err)
+	}
+
+	post := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("Origin", "http://client.example")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	if w := post("/api/upload"); w.Code == http.StatusTooManyRequests {
+		t.Fatal("expected the burst to allow the first upload")
+	}
+	// The dry run shares the upload bucket, so it cannot be used to parse CSVs without limit.
+	w := post("/api/upload/validate")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected 429 with Retry-After, got %d %v", w.Code, w.Header())
+	}
+	exposed := strings.ToLower(w.Header().Get("Access-Control-Expose-Headers"))
+	if !strings.Contains(exposed, "retry-after") {
+		t.Fatalf("expected CORS to expose Retry-After, got %q", exposed)
+	}
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// preloadTrademarkIndex loads the marks and builds the trademark index in the background so the
+// first evaluation does not pay for it; /api/readyz reports not ready until it finishes.
+func (s *Server) preloadTrademarkIndex() {
+	start := time.Now()
+	if _, _, err := s.loadTrademarkScorer(); err != nil {
+		s.preloadErr.Store(err.Error())
+		logrus.WithError(err).Error("preload trademark index")
+		return
+	}
+	logrus.WithField("duration", time.Since(start)).Info("trademark index preloaded")
+}
+
+// handleReady reports readiness, distinct from the liveness check in handleHealth. With
+// preloading enabled the server is ready once the trademark index is built; otherwise marks load
+// lazily on the first evaluation and the server is always ready.
+func (s *Server) handleReady(c *gin.Context) {
+	marksLoaded := s.marksReady.Load()
+	ready := marksLoaded || !s.preload
+	body := gin.H{
+		"ready":        ready,
+		"marks_loaded": marksLoaded,
+		"preload":      s.preload,
+	}
+	if msg, ok := s.preloadErr.Load().(string); ok && msg != "" {
+		ready = false
+		body["ready"] = false
+		body["error"] = msg
+	}
+	status := http.StatusOK
+	if !ready {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, body)
+}
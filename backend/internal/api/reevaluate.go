@@ -0,0 +1,163 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"domain-risk-eval/backend/internal/scoring"
+	"domain-risk-eval/backend/internal/store"
+)
+
+// errEmptyReevaluateFilter rejects a re-evaluation that would select every stored evaluation;
+// re-running the batches is the way to re-score everything.
+var errEmptyReevaluateFilter = errors.New("at least one filter is required to select evaluations to re-score")
+
+// query converts the request's filters into an EvaluationQuery, rejecting unknown
+// recommendations and a filter that selects nothing in particular.
+func (r ReevaluateRequest) query() (store.EvaluationQuery, error) {
+	filter := store.EvaluationQuery{
+		Query:              strings.TrimSpace(r.Query),
+		MinTrademark:       r.MinScore,
+		MinVice:            r.MinViceScore,
+		TLD:                strings.TrimSpace(r.TLD),
+		ViceCategory:       strings.TrimSpace(r.ViceCategory),
+		BatchID:            r.BatchID,
+		MinCommercialPrice: r.MinCommercialPrice,
+		CommercialOverride: r.CommercialOverride,
+		Sort:               "domain_asc",
+	}
+	if r.UpdatedSince != nil {
+		// Timestamps are stored in server-local time and compared as text.
+		filter.UpdatedSince = r.UpdatedSince.In(time.Local)
+	}
+	if raw := strings.TrimSpace(r.Recommendation); raw != "" {
+		rec, err := scoring.ParseRecommendation(raw)
+		if err != nil {
+			return filter, fmt.Errorf("%w; expected one of %s", err, strings.Join(scoring.RecommendationNames(), ", "))
+		}
+		filter.Recommendation = string(rec)
+	}
+	for _, raw := range r.Recommendations {
+		rec, err := scoring.ParseRecommendation(raw)
+		if err != nil {
+			return filter, fmt.Errorf("%w; expected one of %s", err, strings.Join(scoring.RecommendationNames(), ", "))
+		}
+		filter.Recommendations = append(filter.Recommendations, string(rec))
+	}
+
+	if filter.Query == "" && filter.MinTrademark == 0 && filter.MinVice == 0 && filter.TLD == "" &&
+		filter.ViceCategory == "" && filter.BatchID == 0 && filter.MinCommercialPrice == 0 &&
+		filter.CommercialOverride == nil && filter.UpdatedSince.IsZero() &&
+		filter.Recommendation == "" && len(filter.Recommendations) == 0 {
+		return filter, errEmptyReevaluateFilter
+	}
+	return filter, nil
+}
+
+// evaluateRequest returns the evaluation options the job runs with. Stored evaluations are
+// exactly what is being replaced, so the run is always forced.
+func (r ReevaluateRequest) evaluateRequest() EvaluateRequest {
+	return EvaluateRequest{
+		BatchID:          r.BatchID,
+		Limit:            r.Limit,
+		Force:            true,
+		SkipVice:         r.SkipVice,
+		SkipUSPTO:        r.SkipUSPTO,
+		SkipCommercial:   r.SkipCommercial,
+		SkipAI:           r.SkipAI,
+		DedupeNarratives: r.DedupeNarratives,
+		Prewarm:          r.Prewarm,
+		CustomMarks:      r.CustomMarks,
+		MarkOwner:        r.MarkOwner,
+	}
+}
+
+// handleReevaluate re-scores the stored evaluations matching a filter, for example every BLOCK
+// after the recommendation matrix changed, without re-running whole batches.
+func (s *Server) handleReevaluate(c *gin.Context) {
+	var req ReevaluateRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		s.renderBindingError(c, &req, err)
+		return
+	}
+	filter, err := req.query()
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+	if filter.BatchID != 0 {
+		if _, err := s.db.GetCSVBatch(filter.BatchID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				s.renderError(c, http.StatusNotFound, fmt.Errorf("batch %d not found", filter.BatchID))
+			} else {
+				s.renderError(c, http.StatusInternalServerError, err)
+			}
+			return
+		}
+	}
+
+	// A re-evaluation is held to the row cap of an uploaded batch.
+	matched, err := s.db.CountEvaluations(filter)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if s.uploadRows > 0 && matched > int64(s.uploadRows) {
+		s.renderError(c, http.StatusUnprocessableEntity, fmt.Errorf("filter matches %d evaluations; at most %d can be re-scored at once", matched, s.uploadRows))
+		return
+	}
+
+	var rows []store.BatchDomain
+	err = s.db.EachEvaluation(filter, func(eval store.Evaluation) error {
+		rows = append(rows, store.BatchDomain{
+			Domain:           eval.Domain,
+			DomainNormalized: eval.DomainNormalized,
+			RowIndex:         len(rows) + 1,
+		})
+		return nil
+	})
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(rows) == 0 {
+		s.renderError(c, http.StatusBadRequest, errors.New("no evaluations match the filter"))
+		return
+	}
+
+	evalReq := req.evaluateRequest()
+	evalReq.Limit = s.evalLimit.clamp(evalReq.Limit)
+
+	s.jobMu.Lock()
+	defer s.jobMu.Unlock()
+	if s.activeJob != nil {
+		s.renderError(c, http.StatusConflict, errors.New("evaluation already running"))
+		return
+	}
+
+	job, err := s.startReevaluation(evalReq, rows, requestIDFrom(c))
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	requestLogger(c).WithFields(logrus.Fields{
+		"job":   job.id,
+		"total": job.total,
+	}).Info("re-evaluation requested")
+
+	c.JSON(http.StatusAccepted, StartEvaluationResponse{
+		JobID:     job.id,
+		RequestID: job.requestID,
+		Total:     job.total,
+		StartedAt: job.startedAt,
+	})
+}
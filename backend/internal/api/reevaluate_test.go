@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"domain-risk-eval/backend/internal/store"
+)
+
+func TestHandleReevaluate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := newOfflineServer(t)
+
+	seed := []struct {
+		domain, rec string
+		categories  []string
+	}{
+		{"quietharbor.com", "BLOCK", nil},
+		{"stillwater.net", "BLOCK", []string{"casino"}},
+		{"greenmeadow.org", "ALLOW_WITH_CAUTION", []string{"casino"}},
+	}
+	for _, row := range seed {
+		eval := &store.Evaluation{Domain: row.domain, DomainNormalized: row.domain, OverallRecommendation: row.rec}
+		eval.SetViceCategories(row.categories)
+		if err := s.db.SaveEvaluation(eval); err != nil {
+			t.Fatalf("save %s: %v", row.domain, err)
+		}
+	}
+
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/reevaluate", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		s.handleReevaluate(c)
+		return w
+	}
+
+	if w := post(`{}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("empty filter: expected 400 got %d", w.Code)
+	}
+	if w := post(`{"recommendations":["maybe"]}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("unknown recommendation: expected 400 got %d", w.Code)
+	}
+	if w := post(`{"vice_category":"tobacco"}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("no matches: expected 400 got %d", w.Code)
+	}
+	if w := post(`{"vice_category":"cas_no"}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("wildcard category: expected 400 got %d", w.Code)
+	}
+	s.uploadRows = 1
+	if w := post(`{"recommendation":"block"}`); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("over the row cap: expected 422 got %d", w.Code)
+	}
+	s.uploadRows = defaultMaxUploadRows
+
+	w := post(`{"recommendations":["block"],"vice_category":"casino","skip_uspto":true,"skip_commercial":true}`)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 got %d: %s", w.Code, w.Body.String())
+	}
+	var resp StartEvaluationResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Total != 1 {
+		t.Fatalf("expected 1 domain selected, got %d", resp.Total)
+	}
+	s.jobMu.Lock()
+	job := s.activeJob
+	s.jobMu.Unlock()
+	if job != nil {
+		<-job.done
+	}
+
+	request, err := s.db.GetBatchRequest(resp.RequestID)
+	if err != nil {
+		t.Fatalf("load request: %v", err)
+	}
+	if request.Type != "reevaluate" || request.Status != "completed" {
+		t.Fatalf("unexpected request %s/%s", request.Type, request.Status)
+	}
+	if summary := request.Summary(); summary == nil || summary.Evaluated != 1 {
+		t.Fatalf("expected one domain re-scored, got %+v", summary)
+	}
+	rows, err := s.db.EvaluationsByDomain([]string{"quietharbor.com", "stillwater.net"})
+	if err != nil {
+		t.Fatalf("load evaluations: %v", err)
+	}
+	for _, row := range rows {
+		rescored := row.DomainNormalized == "stillwater.net"
+		if rescored == (row.OverallRecommendation == "BLOCK") {
+			t.Fatalf("%s: unexpected recommendation %s after re-evaluation", row.Domain, row.OverallRecommendation)
+		}
+	}
+}
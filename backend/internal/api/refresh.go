@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"domain-risk-eval/backend/internal/scoring"
+)
+
+// startDatasetRefresh runs refreshDatasets every refreshEvery until Shutdown. The mark tables'
+// current version is the baseline, so the first tick only reloads if they change.
+func (s *Server) startDatasetRefresh() {
+	if version, err := s.db.MarksVersion(); err != nil {
+		logrus.WithError(err).Warn("dataset refresh: read marks version")
+	} else {
+		s.datasetVersion = version
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopRefresh = cancel
+	logrus.WithField("interval", s.refreshEvery).Info("periodic dataset refresh enabled")
+	go func() {
+		ticker := time.NewTicker(s.refreshEvery)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.refreshDatasets()
+			}
+		}
+	}()
+}
+
+// refreshDatasets reloads the popular tokens from the store and invalidates the cached marks and
+// trademark index so the next job rebuilds them, unless the mark tables' version is unchanged
+// since the last refresh. The popular token set is shared by every scorer,
+// so a tick is skipped while an evaluation job runs rather than changing it mid-batch, and jobMu
+// is held until the reload finishes so no job can start in between; it is also skipped while an
+// admin popular refresh holds popularMu.
+func (s *Server) refreshDatasets() {
+	s.jobMu.Lock()
+	defer s.jobMu.Unlock()
+	if s.activeJob != nil {
+		logrus.Info("evaluation job running; skipping dataset refresh")
+		return
+	}
+	if !s.popularMu.TryLock() {
+		logrus.Info("popular refresh in progress; skipping dataset refresh")
+		return
+	}
+	defer s.popularMu.Unlock()
+
+	start := time.Now()
+	version, err := s.db.MarksVersion()
+	if err != nil {
+		logrus.WithError(err).Warn("dataset refresh: read marks version")
+	} else if version == s.datasetVersion {
+		logrus.Debug("marks unchanged; skipping dataset refresh")
+		return
+	}
+	fields := logrus.Fields{}
+	if s.popularLimit > 0 {
+		before := scoring.PopularTokenCount()
+		if _, err := scoring.LoadPopularTokensFromStore(s.db, s.popularLimit); err != nil {
+			logrus.WithError(err).Warn("dataset refresh: reload popular tokens")
+		} else {
+			fields["popular_tokens_before"], fields["popular_tokens"] = before, scoring.PopularTokenCount()
+		}
+	}
+
+	s.marksMu.Lock()
+	cached, loaded := len(s.marksCache), s.marksLoaded
+	s.marksMu.Unlock()
+	if err == nil {
+		fields["marks_stored"] = version.Marks
+		s.datasetVersion = version
+	}
+	if loaded {
+		fields["marks_cached"] = cached
+	}
+	s.invalidateTrademarkMarks()
+
+	fields["duration"] = time.Since(start)
+	logrus.WithFields(fields).Info("dataset refresh complete")
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"domain-risk-eval/backend/internal/store"
+)
+
+func TestRefreshDatasets(t *testing.T) {
+	s := newOfflineServer(t)
+	if err := s.db.UpsertMark(&store.Mark{Serial: "1", Mark: "ZORBLAX", MarkNormalized: "zorblax", MarkNoSpaces: "zorblax"}); err != nil {
+		t.Fatalf("upsert mark: %v", err)
+	}
+	s.refreshEvery = time.Hour
+	s.startDatasetRefresh()
+	t.Cleanup(s.stopRefresh)
+
+	cached := func() bool {
+		if _, err := s.loadTrademarkMarks(); err != nil {
+			t.Fatalf("load marks: %v", err)
+		}
+		s.refreshDatasets()
+		s.marksMu.Lock()
+		defer s.marksMu.Unlock()
+		return s.marksLoaded
+	}
+
+	if !cached() {
+		t.Fatal("expected unchanged marks to keep the cache")
+	}
+
+	s.activeJob = &evaluationJob{}
+	if err := s.db.UpsertMark(&store.Mark{Serial: "2", Mark: "QUIBBLY", MarkNormalized: "quibbly", MarkNoSpaces: "quibbly"}); err != nil {
+		t.Fatalf("upsert mark: %v", err)
+	}
+	if !cached() {
+		t.Fatal("expected a running job to skip the refresh")
+	}
+	s.activeJob = nil
+
+	s.popularMu.Lock()
+	stale := cached()
+	s.popularMu.Unlock()
+	if !stale {
+		t.Fatal("expected a popular refresh in progress to skip the refresh")
+	}
+
+	if cached() {
+		t.Fatal("expected a new mark to invalidate the cache")
+	}
+	if !s.jobMu.TryLock() {
+		t.Fatal("expected the refresh to release jobMu")
+	}
+	s.jobMu.Unlock()
+	if !cached() {
+		t.Fatal("expected the refreshed version to keep the reloaded cache")
+	}
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	requestIDHeader = "X-Request-ID"
+	requestIDKey    = "request_id"
+	// maxRequestIDLength caps caller-supplied IDs so they cannot bloat every log line.
+	maxRequestIDLength = 128
+)
+
+// requestIDMiddleware tags every request with an ID, reusing a sane X-Request-ID from the caller
+// and echoing it back so clients can quote it when reporting problems.
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := strings.TrimSpace(c.GetHeader(requestIDHeader))
+		if id == "" || len(id) > maxRequestIDLength || strings.ContainsAny(id, "\r\n") {
+			id = uuid.NewString()
+		}
+		c.Set(requestIDKey, id)
+		c.Header(requestIDHeader, id)
+		c.Next()
+	}
+}
+
+// requestIDFrom returns the ID assigned by requestIDMiddleware, or "" outside of it.
+func requestIDFrom(c *gin.Context) string {
+	if c == nil {
+		return ""
+	}
+	return c.GetString(requestIDKey)
+}
+
+// requestLogger returns a log entry carrying the request ID so handler logs can be correlated
+// with the jobs they start.
+func requestLogger(c *gin.Context) *logrus.Entry {
+	return logrus.WithField(requestIDKey, requestIDFrom(c))
+}
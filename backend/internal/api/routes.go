@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -14,10 +15,14 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/gorilla/websocket"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -35,6 +40,7 @@ type Config struct {
 	DBPath             string
 	SeedsPath          string
 	ViceTermsPath      string
+	ViceAllowlistPath  string
 	DefaultXMLPath     string
 	DefaultDomainsPath string
 	CommercialSales    string
@@ -43,21 +49,195 @@ type Config struct {
 	AIConfig           ai.Config
 	USPTOConfig        usp.Config
 	DisableAI          bool
-	PopularLimit       int
-	PopularMinCount    int
-	MarksLimit         int
+	// FakeAI replaces the OpenAI client with ai.FakeExplainer for offline end-to-end runs.
+	// DisableAI takes precedence.
+	FakeAI          bool
+	PopularLimit    int
+	PopularMinCount int
+	MarksLimit      int
+	// TrademarkScoresPath optionally points to a JSON TrademarkScoreConfig; empty uses defaults.
+	TrademarkScoresPath string
+	// CommonWordsPath optionally replaces the embedded common-word dictionary; ExtraCommonWords
+	// adds domain-specific stopwords on top of whichever dictionary is active.
+	CommonWordsPath  string
+	ExtraCommonWords []string
+	// AdminToken is the bearer token required by /api/admin routes; they are disabled when empty.
+	AdminToken string
+	// ReviewTrademarkConflicts raises ALLOW and ALLOW_WITH_CAUTION to REVIEW for domains whose
+	// heuristic and USPTO trademark signals disagree; BLOCK is left as is.
+	ReviewTrademarkConflicts bool
+	// AIBatchSize groups up to this many domains per AI request; values below 2 keep the
+	// single-domain path.
+	AIBatchSize int
+	// TLDRiskPath optionally points to a JSON TLDRiskTable; empty uses DefaultTLDRiskTable.
+	TLDRiskPath string
+	// ReviewRandomDomains routes ALLOW_WITH_CAUTION domains with a random-looking label to REVIEW.
+	ReviewRandomDomains bool
+	// SpamThresholds tunes the hyphen/digit spam-pattern signal; nil uses
+	// match.DefaultSpamThresholds. ReviewSpamDomains routes ALLOW_WITH_CAUTION domains that
+	// reach it to REVIEW.
+	SpamThresholds    *match.SpamThresholds
+	ReviewSpamDomains bool
+	// PrewarmMaxDomains skips requested cache pre-warming for batches larger than this; 0 means
+	// no limit.
+	PrewarmMaxDomains int
+	// CommercialPolicyPath optionally points to a JSON CommercialOverridePolicy; empty uses
+	// DefaultCommercialOverridePolicy.
+	CommercialPolicyPath string
+	// CommercialMinPrice, when set, replaces the policy's min_price: a matched sale below it does
+	// not override.
+	CommercialMinPrice *float64
+	// MaxUploadBytes and MaxUploadRows bound domain CSV uploads; zero uses
+	// defaultMaxUploadBytes and defaultMaxUploadRows.
+	MaxUploadBytes int64
+	MaxUploadRows  int
+	// UploadDedupe replays an upload without an Idempotency-Key when the same file and batch
+	// fields were uploaded within the last 24 hours. Off by default, so re-uploading a file on
+	// purpose creates a new batch.
+	UploadDedupe bool
+	// CSVComment is the character starting comment lines in domain CSVs: empty uses '#', and
+	// "none" treats every line as data.
+	CSVComment string
+	// HighValueOwners lists rights holders whose matched marks always score at least REVIEW.
+	HighValueOwners []string
+	// PreloadMarks loads the marks and builds the trademark index in the background at startup
+	// instead of on the first evaluation; /api/readyz reports ready once it finishes.
+	PreloadMarks bool
+	// FancifulThresholds tunes the fanciful heuristic applied at ingest; nil uses
+	// DefaultFancifulThresholds.
+	FancifulThresholds *scoring.FancifulThresholds
+	// CommercialSimilarity names the algorithm used to match SLDs against the sales inventory
+	// (see commercial.Algorithms); empty uses Levenshtein.
+	CommercialSimilarity string
+	// CommercialCandidateLimit and CommercialLengthWindow bound the sales inventory search: rows
+	// fetched per prefix pass and the allowed length difference from the SLD. A zero limit uses
+	// commercial.DefaultCandidateLimit and a nil window commercial.DefaultLengthWindow; a zero
+	// window only matches sales of the SLD's exact length.
+	CommercialCandidateLimit int
+	CommercialLengthWindow   *int
+	// CommercialEarlyExit is the similarity at which the sales search stops widening its prefix
+	// tiers, separate from the policy's MinSimilarity; zero uses
+	// commercial.DefaultEarlyExitSimilarity. It must not exceed 1.
+	CommercialEarlyExit float64
+	// UnicodeFolding turns on match.Fold's NFKC and diacritic folding for the whole process. It
+	// must match the setting the stored marks were ingested with.
+	UnicodeFolding bool
+	// SubdomainSignals checks subdomain labels against the trademark index and routes a brand
+	// found on an unrelated registrable domain (e.g. login-paypal.attacker.com) to REVIEW.
+	SubdomainSignals bool
+	// AllowedHeaders and AllowedMethods configure CORS; empty uses defaultCORSHeaders and
+	// defaultCORSMethods. CORSMaxAge is how long browsers may cache a preflight response; zero
+	// uses defaultCORSMaxAge.
+	AllowedHeaders []string
+	AllowedMethods []string
+	CORSMaxAge     time.Duration
+	// EmbeddedTrademarks also matches marks found as a token or alternate split of the SLD
+	// (e.g. apple in applestore.com) when the SLD has no exact match.
+	EmbeddedTrademarks bool
+	// SimilarMarkReview escalates a domain to a score 3 (REVIEW) "similar" trademark result when a
+	// live USPTO mark scores at least SimilarMarkThreshold against the SLD without an exact
+	// match; zero uses defaultSimilarMarkThreshold.
+	SimilarMarkReview    bool
+	SimilarMarkThreshold float64
+	// SimilarOnlyScore gives a domain whose live USPTO search found only similar marks a
+	// "similar_only" trademark result at this score instead of "none". It must be 1 or 2; zero
+	// keeps "none".
+	SimilarOnlyScore int
+	// SoftenLowConfidence lowers a BLOCK to REVIEW when the overall confidence is below
+	// LowConfidenceThreshold; zero uses scoring.DefaultLowConfidenceThreshold.
+	SoftenLowConfidence    bool
+	LowConfidenceThreshold float64
+	// RefreshInterval, when positive, periodically reloads popular tokens from the store and
+	// invalidates the cached marks and trademark index so data written by cmd/popular or another
+	// process is picked up without a restart.
+	RefreshInterval time.Duration
+	// MaxNarrativeLength caps AI narratives, in characters, before they are stored; longer ones
+	// are cut at a sentence boundary and noted in the reasons. Zero uses
+	// ai.DefaultMaxNarrativeLength; negative disables the cap.
+	MaxNarrativeLength int
+	// AIGuardrailScore is the heuristic trademark or vice score from which AI overrides may not
+	// lower that score or relax the recommendation below REVIEW. Zero uses
+	// scoring.DefaultAIGuardrailScore; negative disables the guardrail.
+	AIGuardrailScore int
+	// LiveMarksOnly keeps marks whose USPTO status is abandoned, cancelled, or expired out of the
+	// trademark index. Marks ingested without a status are always kept.
+	LiveMarksOnly bool
+	// MarksDBPath optionally moves the marks and popular_marks tables to a separate SQLite file,
+	// opened read-only unless MarksDBWritable is set. Empty keeps marks in DBPath.
+	MarksDBPath     string
+	MarksDBWritable bool
+	// UploadRateLimit and EvaluateRateLimit cap requests per minute per client IP on
+	// /api/upload and on /api/evaluate (which also covers retries); zero disables the limit.
+	// RateLimitBurst is how many requests a client may make back to back; zero uses
+	// defaultRateLimitBurst.
+	UploadRateLimit   float64
+	EvaluateRateLimit float64
+	RateLimitBurst    int
+	// TrustedProxies lists the proxy IPs or CIDRs whose X-Forwarded-For header names the client
+	// IP; empty trusts none, so the client IP is the connection's remote address.
+	TrustedProxies []string
+	// AIMaxConcurrency caps in-flight AI explainer calls across all evaluation workers, so the
+	// model's concurrency limit can be lower than the worker count; zero leaves it unlimited.
+	AIMaxConcurrency int
+	// StoreConfig sets the SQLite busy timeout and connection pool limits for both databases.
+	StoreConfig store.Config
+	// ResultsPage bounds pageSize on /api/results, /api/batches/:id/results, and
+	// /api/batches/:id/skipped; BatchesPage on /api/batches; MarksPage on /api/marks.
+	// EvaluateLimit bounds the evaluate request's limit (domains read per chunk). Zero fields use
+	// the defaults in paging.go.
+	ResultsPage   PageLimits
+	BatchesPage   PageLimits
+	MarksPage     PageLimits
+	EvaluateLimit PageLimits
+	// StoreTimings persists each evaluation's USPTO lookup and AI durations alongside its total
+	// processing time.
+	StoreTimings bool
 }
 
+// Upload limits applied when Config leaves them unset.
+const (
+	defaultMaxUploadBytes int64 = 50 << 20
+	defaultMaxUploadRows        = 1000000
+	// multipartOverhead allows for the form fields and part headers around the uploaded file.
+	multipartOverhead int64 = 1 << 20
+)
+
+// CORS settings applied when Config leaves them unset. Chromium caps preflight caching at two
+// hours, so a longer max age buys nothing there.
+var (
+	defaultCORSHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", requestIDHeader, idempotencyHeader}
+	defaultCORSMethods = []string{"GET", "POST", "DELETE", "OPTIONS"}
+)
+
+const defaultCORSMaxAge = 2 * time.Hour
+
+// defaultSimilarMarkThreshold is the USPTO mark similarity that escalates to REVIEW when
+// Config.SimilarMarkReview is set: one edit in a five-letter mark scores 0.8.
+const defaultSimilarMarkThreshold = 0.8
+
+// uploadSampleSize is how many parsed domains /api/upload/validate returns as a preview.
+const uploadSampleSize = 20
+
+// errTooManyRows reports a CSV upload with more domain rows than the configured cap.
+var errTooManyRows = errors.New("csv exceeds the maximum number of rows")
+
 // Server wires HTTP handlers with persistence and scoring.
 type Server struct {
-	db              *store.Database
+	db              Store
 	seedPath        string
 	vicePath        string
+	allowlistPath   string
+	scoresPath      string
+	trademarkScores *scoring.TrademarkScoreConfig
 	defaultXMLPath  string
 	defaultDomains  string
 	viceScorer      *scoring.ViceScorer
 	fancifulDecider *scoring.FancifulDecider
 	allowedOrigins  []string
+	trustedProxies  []string
+	corsHeaders     []string
+	corsMethods     []string
+	corsMaxAge      time.Duration
 	explainer       ai.Explainer
 	usptoClient     *usp.Client
 	evalNotifier    *EvaluationNotifier
@@ -68,22 +248,75 @@ type Server struct {
 	popularLimit    int
 	popularMinCount int
 	marksLimit      int
-	marksOnce       sync.Once
+	aiBatchSize     int
+	reviewConflicts bool
+	reviewRandom    bool
+	reviewSpam      bool
+	spamThresholds  match.SpamThresholds
+	prewarmMax      int
+	uploadMax       int64
+	uploadRows      int
+	uploadDedupe    bool
+	csvComment      rune
+	salesPolicy     scoring.CommercialOverridePolicy
+	adminToken      string
+	popularMu       sync.Mutex
+	ingestMu        sync.Mutex
+	marksMu         sync.Mutex
 	marksCache      []store.Mark
-	marksErr        error
+	marksLoaded     bool
+	scorerMu        sync.Mutex
+	scorerCache     *scoring.TrademarkScorer
+	tldRisk         *scoring.TLDRiskTable
+	owners          *scoring.OwnerWatchlist
+	preload         bool
+	subdomains      bool
+	embeddedMarks   bool
+	similarReview   bool
+	similarMin      float64
+	similarOnly     int
+	softenLow       bool
+	liveMarksOnly   bool
+	refreshEvery    time.Duration
+	datasetVersion  store.MarksVersion
+	narrativeMax    int
+	aiGuardrail     int
+	stopRefresh     context.CancelFunc
+	lowConfidence   float64
+	marksReady      atomic.Bool
+	preloadErr      atomic.Value
+	uploadLimiter   *rateLimiter
+	evalLimiter     *rateLimiter
+	aiSlots         chan struct{}
+	resultsPage     PageLimits
+	batchesPage     PageLimits
+	marksPage       PageLimits
+	evalLimit       PageLimits
+	storeTimings    bool
 }
 
-const commercialMinPrice = 10000.0
-
 // NewServer constructs the API server.
 func NewServer(cfg Config) (*Server, error) {
 	if cfg.DBPath == "" {
 		return nil, errors.New("db path required")
 	}
-	db, err := store.Open(cfg.DBPath, cfg.SilentDB)
+	match.SetUnicodeFolding(cfg.UnicodeFolding)
+	storeConfig := cfg.StoreConfig
+	storeConfig.DecodeDomain = match.DecodePunycode
+	db, err := store.Open(cfg.DBPath, cfg.SilentDB, storeConfig)
 	if err != nil {
 		return nil, err
 	}
+	if marksPath := strings.TrimSpace(cfg.MarksDBPath); marksPath != "" {
+		if err := db.OpenMarks(marksPath, !cfg.MarksDBWritable, cfg.SilentDB); err != nil {
+			db.Close()
+			return nil, err
+		}
+		logrus.WithFields(logrus.Fields{
+			"path":      marksPath,
+			"read_only": !cfg.MarksDBWritable,
+		}).Info("marks database attached")
+	}
 
 	seedPath := cfg.SeedsPath
 	if seedPath == "" {
@@ -94,7 +327,7 @@ func NewServer(cfg Config) (*Server, error) {
 		vicePath = filepath.Join("internal", "scoring", "vice_terms.json")
 	}
 
-	decider, err := scoring.NewFancifulDecider(seedPath)
+	decider, err := scoring.NewFancifulDecider(seedPath, cfg.FancifulThresholds)
 	if err != nil {
 		return nil, fmt.Errorf("fanciful decider: %w", err)
 	}
@@ -102,10 +335,89 @@ func NewServer(cfg Config) (*Server, error) {
 	if err != nil {
 		return nil, fmt.Errorf("vice scorer: %w", err)
 	}
+	allowlistPath := strings.TrimSpace(cfg.ViceAllowlistPath)
+	if allowlistPath != "" {
+		allowlist, err := scoring.LoadViceAllowlist(allowlistPath)
+		if err != nil {
+			return nil, fmt.Errorf("vice allowlist: %w", err)
+		}
+		viceScorer.SetAllowlist(allowlist)
+		logrus.WithFields(logrus.Fields{
+			"path":    allowlistPath,
+			"entries": allowlist.Len(),
+		}).Info("vice allowlist loaded")
+	}
+
+	if path := strings.TrimSpace(cfg.CommonWordsPath); path != "" {
+		count, err := scoring.LoadCommonWords(path)
+		if err != nil {
+			return nil, fmt.Errorf("common words: %w", err)
+		}
+		logrus.WithFields(logrus.Fields{"path": path, "words": count}).Info("common word dictionary loaded")
+	}
+	if len(cfg.ExtraCommonWords) > 0 {
+		scoring.AddCommonWords(cfg.ExtraCommonWords...)
+	}
+
+	scoresPath := strings.TrimSpace(cfg.TrademarkScoresPath)
+	var trademarkScores *scoring.TrademarkScoreConfig
+	if scoresPath != "" {
+		loaded, err := scoring.LoadTrademarkScoreConfig(scoresPath)
+		if err != nil {
+			return nil, fmt.Errorf("trademark scores: %w", err)
+		}
+		trademarkScores = &loaded
+		logrus.WithField("path", scoresPath).Info("trademark score config loaded")
+	}
+
+	commercialPolicy := scoring.DefaultCommercialOverridePolicy()
+	if path := strings.TrimSpace(cfg.CommercialPolicyPath); path != "" {
+		loaded, err := scoring.LoadCommercialOverridePolicy(path)
+		if err != nil {
+			return nil, fmt.Errorf("commercial policy: %w", err)
+		}
+		commercialPolicy = loaded
+		logrus.WithField("path", path).Info("commercial override policy loaded")
+	}
+	if cfg.CommercialMinPrice != nil {
+		commercialPolicy, err = commercialPolicy.WithMinPrice(*cfg.CommercialMinPrice)
+		if err != nil {
+			return nil, fmt.Errorf("commercial min price: %w", err)
+		}
+	}
+
+	similarity, err := commercial.ParseAlgorithm(cfg.CommercialSimilarity)
+	if err != nil {
+		return nil, fmt.Errorf("commercial similarity: %w", err)
+	}
+	sales := commercial.NewService(db)
+	sales.SetAlgorithm(similarity)
+	lengthWindow := -1
+	if cfg.CommercialLengthWindow != nil {
+		lengthWindow = *cfg.CommercialLengthWindow
+	}
+	sales.SetSearchLimits(cfg.CommercialCandidateLimit, lengthWindow)
+	if cfg.CommercialEarlyExit > 1 {
+		return nil, fmt.Errorf("commercial early exit similarity must be at most 1, got %g", cfg.CommercialEarlyExit)
+	}
+	sales.SetEarlyExitSimilarity(cfg.CommercialEarlyExit)
+
+	tldRisk := scoring.DefaultTLDRiskTable()
+	if path := strings.TrimSpace(cfg.TLDRiskPath); path != "" {
+		loaded, err := scoring.LoadTLDRiskTable(path)
+		if err != nil {
+			return nil, fmt.Errorf("tld risk: %w", err)
+		}
+		tldRisk = loaded
+		logrus.WithFields(logrus.Fields{"path": path, "tlds": tldRisk.Len()}).Info("tld risk table loaded")
+	}
 
 	var explainer ai.Explainer
 	if cfg.DisableAI {
 		logrus.Info("AI explainer disabled via configuration")
+	} else if cfg.FakeAI {
+		logrus.Warn("AI explainer replaced by a deterministic fake; narratives are template output")
+		explainer = ai.NewFakeExplainer()
 	} else {
 		if client, err := ai.NewClient(cfg.AIConfig); err == nil {
 			explainer = client
@@ -136,22 +448,109 @@ func NewServer(cfg Config) (*Server, error) {
 		db:              db,
 		seedPath:        seedPath,
 		vicePath:        vicePath,
+		allowlistPath:   allowlistPath,
+		scoresPath:      scoresPath,
+		trademarkScores: trademarkScores,
+		tldRisk:         tldRisk,
 		defaultXMLPath:  cfg.DefaultXMLPath,
 		defaultDomains:  cfg.DefaultDomainsPath,
 		viceScorer:      viceScorer,
 		fancifulDecider: decider,
 		allowedOrigins:  cfg.AllowedOrigins,
+		trustedProxies:  cfg.TrustedProxies,
+		corsHeaders:     cfg.AllowedHeaders,
+		corsMethods:     cfg.AllowedMethods,
+		corsMaxAge:      cfg.CORSMaxAge,
 		explainer:       explainer,
 		usptoClient:     usptoClient,
 		evalNotifier:    NewEvaluationNotifier(),
-		commercial:      commercial.NewService(db),
+		commercial:      sales,
 		popularLimit:    cfg.PopularLimit,
 		popularMinCount: cfg.PopularMinCount,
 		marksLimit:      cfg.MarksLimit,
+		aiBatchSize:     cfg.AIBatchSize,
+		reviewConflicts: cfg.ReviewTrademarkConflicts,
+		reviewRandom:    cfg.ReviewRandomDomains,
+		reviewSpam:      cfg.ReviewSpamDomains,
+		spamThresholds:  match.DefaultSpamThresholds(),
+		prewarmMax:      cfg.PrewarmMaxDomains,
+		uploadMax:       cfg.MaxUploadBytes,
+		uploadRows:      cfg.MaxUploadRows,
+		uploadDedupe:    cfg.UploadDedupe,
+		owners:          scoring.NewOwnerWatchlist(cfg.HighValueOwners),
+		preload:         cfg.PreloadMarks,
+		subdomains:      cfg.SubdomainSignals,
+		embeddedMarks:   cfg.EmbeddedTrademarks,
+		similarReview:   cfg.SimilarMarkReview,
+		similarMin:      cfg.SimilarMarkThreshold,
+		similarOnly:     cfg.SimilarOnlyScore,
+		softenLow:       cfg.SoftenLowConfidence,
+		liveMarksOnly:   cfg.LiveMarksOnly,
+		refreshEvery:    cfg.RefreshInterval,
+		narrativeMax:    cfg.MaxNarrativeLength,
+		aiGuardrail:     cfg.AIGuardrailScore,
+		lowConfidence:   cfg.LowConfidenceThreshold,
+		salesPolicy:     commercialPolicy,
+		adminToken:      strings.TrimSpace(cfg.AdminToken),
+		uploadLimiter:   newRateLimiter(cfg.UploadRateLimit, cfg.RateLimitBurst),
+		evalLimiter:     newRateLimiter(cfg.EvaluateRateLimit, cfg.RateLimitBurst),
+		resultsPage:     cfg.ResultsPage.withDefaults(defaultResultsPage),
+		batchesPage:     cfg.BatchesPage.withDefaults(defaultBatchesPage),
+		marksPage:       cfg.MarksPage.withDefaults(defaultMarksPage),
+		evalLimit:       cfg.EvaluateLimit.withDefaults(defaultEvaluateLimit),
+		storeTimings:    cfg.StoreTimings,
 	}
 
 	if server.marksLimit <= 0 {
-		server.marksLimit = 500000
+		server.marksLimit = defaultMarksLimit
+	}
+	if server.uploadMax <= 0 {
+		server.uploadMax = defaultMaxUploadBytes
+	}
+	if server.uploadRows <= 0 {
+		server.uploadRows = defaultMaxUploadRows
+	}
+	comment, err := parseCSVComment(cfg.CSVComment)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	server.csvComment = comment
+	if cfg.SpamThresholds != nil {
+		if err := cfg.SpamThresholds.Validate(); err != nil {
+			db.Close()
+			return nil, err
+		}
+		server.spamThresholds = *cfg.SpamThresholds
+	}
+	if cfg.SimilarOnlyScore < 0 || cfg.SimilarOnlyScore > 2 {
+		db.Close()
+		return nil, fmt.Errorf("similar-only trademark score must be 0, 1, or 2, got %d", cfg.SimilarOnlyScore)
+	}
+	if len(server.corsHeaders) == 0 {
+		server.corsHeaders = defaultCORSHeaders
+	}
+	if len(server.corsMethods) == 0 {
+		server.corsMethods = defaultCORSMethods
+	}
+	if server.corsMaxAge <= 0 {
+		server.corsMaxAge = defaultCORSMaxAge
+	}
+	if cfg.AIMaxConcurrency > 0 {
+		server.aiSlots = make(chan struct{}, cfg.AIMaxConcurrency)
+		logrus.WithField("ai_max_concurrency", cfg.AIMaxConcurrency).Info("limiting concurrent ai calls")
+	}
+	if server.similarMin <= 0 || server.similarMin > 1 {
+		server.similarMin = defaultSimilarMarkThreshold
+	}
+	if server.narrativeMax == 0 {
+		server.narrativeMax = ai.DefaultMaxNarrativeLength
+	}
+	if server.aiGuardrail == 0 {
+		server.aiGuardrail = scoring.DefaultAIGuardrailScore
+	}
+	if server.lowConfidence <= 0 || server.lowConfidence > 1 {
+		server.lowConfidence = scoring.DefaultLowConfidenceThreshold
 	}
 
 	if trimmed := strings.TrimSpace(cfg.CommercialSales); trimmed != "" {
@@ -174,12 +573,38 @@ func NewServer(cfg Config) (*Server, error) {
 		}
 	}
 
+	if server.preload {
+		go server.preloadTrademarkIndex()
+	}
+	if server.refreshEvery > 0 {
+		server.startDatasetRefresh()
+	}
+
 	return server, nil
 }
 
+// Shutdown stops the periodic dataset refresh, drains the active evaluation job, and closes the
+// database once the job has stopped. It is called after the HTTP server has stopped accepting
+// requests, with a deadline of its own.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.stopRefresh != nil {
+		s.stopRefresh()
+	}
+	if !s.shutdownEvaluation(ctx) {
+		// Closing the database under a job that is still saving would fail its writes; the
+		// process is exiting anyway.
+		return errors.New("evaluation job still running; database left open")
+	}
+	return s.db.Close()
+}
+
 // Router configures gin routes.
 func (s *Server) Router() (*gin.Engine, error) {
 	r := gin.Default()
+	if err := r.SetTrustedProxies(s.trustedProxies); err != nil {
+		return nil, fmt.Errorf("trusted proxies: %w", err)
+	}
+	r.Use(requestIDMiddleware())
 
 	corsCfg := cors.DefaultConfig()
 	corsCfg.AllowCredentials = true
@@ -188,11 +613,15 @@ func (s *Server) Router() (*gin.Engine, error) {
 	} else {
 		corsCfg.AllowOrigins = s.allowedOrigins
 	}
-	corsCfg.AllowHeaders = []string{"Origin", "Content-Type", "Accept"}
-	corsCfg.AllowMethods = []string{"GET", "POST", "DELETE", "OPTIONS"}
+	corsCfg.AllowHeaders = s.corsHeaders
+	// Browsers hide Retry-After from scripts unless it is exposed, so clients could not back off from a 429.
+	corsCfg.ExposeHeaders = []string{requestIDHeader, "Retry-After"}
+	corsCfg.AllowMethods = s.corsMethods
+	corsCfg.MaxAge = s.corsMaxAge
 	r.Use(cors.New(corsCfg))
 
 	r.GET("/api/healthz", s.handleHealth)
+	r.GET("/api/readyz", s.handleReady)
 	r.GET("/api/config", s.handleConfig)
 
 	api := r.Group("/api")
@@ -200,15 +629,38 @@ func (s *Server) Router() (*gin.Engine, error) {
 		api.GET("/batches", s.handleListBatches)
 		api.GET("/batches/:id", s.handleGetBatch)
 		api.GET("/batches/:id/results", s.handleBatchResults)
+		api.GET("/batches/:id/skipped", s.handleSkippedDomains)
+		api.POST("/batches/:id/reset", s.handleResetBatch)
+		api.POST("/batches/:id/recompute-stats", s.handleRecomputeBatchStats)
+		api.POST("/batches/:id/compare", s.handleCompareGroundTruth)
+		api.GET("/batches/:id/requests", s.handleListBatchRequests)
 		api.GET("/requests/:id/status", s.handleRequestStatus)
-		api.POST("/upload", s.handleUpload)
-		api.POST("/evaluate", s.handleEvaluate)
+		api.POST("/requests/:id/retry", s.rateLimit(s.evalLimiter), s.handleRetryRequest)
+		api.POST("/upload", s.rateLimit(s.uploadLimiter), s.handleUpload)
+		api.POST("/upload/validate", s.rateLimit(s.uploadLimiter), s.handleValidateUpload)
+		api.POST("/evaluate", s.rateLimit(s.evalLimiter), s.handleEvaluate)
 		api.GET("/evaluate/status", s.handleEvaluateStatus)
 		api.DELETE("/evaluate/:jobID", s.handleCancelEvaluate)
 		api.GET("/evaluate/stream", s.handleEvaluateStream)
+		api.POST("/reevaluate", s.rateLimit(s.evalLimiter), s.handleReevaluate)
 		api.GET("/results", s.handleResults)
+		api.GET("/results/count", s.handleResultsCount)
 		api.GET("/export.csv", s.handleExportCSV)
+		api.GET("/export/narratives", s.handleExportNarratives)
 		api.GET("/export.json", s.handleExportJSON)
+		api.GET("/export.ndjson", s.handleExportNDJSON)
+		api.POST("/domains/pattern", s.rateLimit(s.evalLimiter), s.handleDomainPattern)
+		api.GET("/marks", s.handleListMarks)
+		api.GET("/marks/:serial", s.handleGetMark)
+		api.GET("/popular", s.handleListPopularMarks)
+		api.GET("/debug/normalize", s.handleDebugNormalize)
+		api.POST("/debug/evaluate", s.handleDebugEvaluate)
+	}
+
+	admin := r.Group("/api/admin", s.requireAdmin())
+	{
+		admin.POST("/popular/refresh", s.handleRefreshPopular)
+		admin.POST("/ingest", s.handleAdminIngest)
 	}
 
 	return r, nil
@@ -229,62 +681,158 @@ func (s *Server) handleConfig(c *gin.Context) {
 	if s.commercial != nil {
 		commercialRecords = s.commercial.Count()
 	}
+	candidateLimit, lengthWindow := s.commercial.SearchLimits()
+	aiEnabled := s.explainer != nil && s.explainer.Enabled()
+	aiModel := ""
+	if named, ok := s.explainer.(interface{ Model() string }); ok && aiEnabled {
+		aiModel = named.Model()
+	}
+	aiLanguage := ""
+	if localized, ok := s.explainer.(interface{ Language() string }); ok && aiEnabled {
+		aiLanguage = localized.Language()
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"seed_path":                s.seedPath,
-		"vice_terms_path":          s.vicePath,
-		"tlds":                     tlds,
-		"commercial_sales_records": commercialRecords,
+		"ai_enabled":                 aiEnabled,
+		"ai_model":                   aiModel,
+		"ai_language":                aiLanguage,
+		"uspto_enabled":              s.usptoClient != nil,
+		"commercial_enabled":         commercialRecords > 0,
+		"workers":                    determineWorkerCount(),
+		"ai_max_concurrency":         s.aiConcurrency(determineWorkerCount()),
+		"seed_path":                  s.seedPath,
+		"vice_terms_path":            s.vicePath,
+		"vice_allowlist_path":        s.allowlistPath,
+		"trademark_scores_path":      s.scoresPath,
+		"common_words":               scoring.CommonWordCount(),
+		"tld_risk_entries":           s.tldRisk.Len(),
+		"high_value_owners":          s.owners.Len(),
+		"subdomain_signals":          s.subdomains,
+		"unicode_folding":            match.UnicodeFolding(),
+		"store_timings":              s.storeTimings,
+		"embedded_trademarks":        s.embeddedMarks,
+		"similar_mark_review":        s.similarReview,
+		"similar_mark_threshold":     s.similarMin,
+		"similar_only_score":         s.similarOnly,
+		"soften_low_confidence":      s.softenLow,
+		"low_confidence_threshold":   s.lowConfidence,
+		"marks_read_only":            s.db.MarksReadOnly(),
+		"live_marks_only":            s.liveMarksOnly,
+		"refresh_interval_seconds":   int(s.refreshEvery / time.Second),
+		"max_narrative_length":       s.narrativeMax,
+		"ai_guardrail_score":         s.aiGuardrail,
+		"spam_thresholds":            s.spamThresholds,
+		"spam_pattern_review":        s.reviewSpam,
+		"cors_allowed_headers":       s.corsHeaders,
+		"cors_allowed_methods":       s.corsMethods,
+		"cors_max_age_seconds":       int(s.corsMaxAge / time.Second),
+		"upload_rate_limit":          s.uploadLimiter.perMinute(),
+		"upload_dedupe":              s.uploadDedupe,
+		"evaluate_rate_limit":        s.evalLimiter.perMinute(),
+		"trusted_proxies":            s.trustedProxies,
+		"tlds":                       tlds,
+		"commercial_sales_records":   commercialRecords,
+		"commercial_similarity":      s.commercial.Algorithm(),
+		"commercial_min_price":       s.salesPolicy.MinPrice,
+		"commercial_tiers":           s.salesPolicy.Tiers,
+		"commercial_candidate_limit": candidateLimit,
+		"commercial_length_window":   lengthWindow,
+		"commercial_early_exit":      s.commercial.EarlyExitSimilarity(),
 	})
 }
-func (s *Server) loadTrademarkMarks() ([]store.Mark, error) {
-	s.marksOnce.Do(func() {
-		limit := s.marksLimit
-		if limit <= 0 {
-			limit = 500000
-		}
 
-		start := time.Now()
-		logrus.WithFields(logrus.Fields{
-			"marks_limit": limit,
-		}).Info("loading trademark marks from store")
-		marks, err := scoring.LoadMarks(s.db, limit)
-		duration := time.Since(start)
-		if err != nil {
-			s.marksErr = err
-			logrus.WithError(err).WithFields(logrus.Fields{
-				"marks_limit": limit,
-				"duration":    duration,
-			}).Error("load trademark marks failed")
-			return
-		}
-		s.marksCache = marks
-		logrus.WithFields(logrus.Fields{
-			"marks_loaded": len(marks),
-			"marks_limit":  limit,
-			"duration":     duration,
-		}).Info("trademark marks cached")
-	})
-
-	if s.marksErr == nil {
+// loadTrademarkMarks returns the cached scoring marks, loading them from the store on first use
+// or after invalidateTrademarkMarks. Load failures are not cached, so the next call retries.
+func (s *Server) loadTrademarkMarks() ([]store.Mark, error) {
+	s.marksMu.Lock()
+	defer s.marksMu.Unlock()
+	if s.marksLoaded {
 		logrus.WithFields(logrus.Fields{
 			"marks_cached": len(s.marksCache),
 			"marks_limit":  s.marksLimit,
 		}).Debug("trademark marks ready")
+		return s.marksCache, nil
+	}
+
+	limit := s.marksLimit
+	if limit <= 0 {
+		limit = defaultMarksLimit
+	}
+
+	start := time.Now()
+	logrus.WithFields(logrus.Fields{
+		"marks_limit": limit,
+	}).Info("loading trademark marks from store")
+	marks, err := scoring.LoadMarks(s.db, limit, s.liveMarksOnly)
+	duration := time.Since(start)
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"marks_limit": limit,
+			"duration":    duration,
+		}).Error("load trademark marks failed")
+		return nil, err
 	}
-	return s.marksCache, s.marksErr
+	s.marksCache = marks
+	s.marksLoaded = true
+	s.marksReady.Store(true)
+	logrus.WithFields(logrus.Fields{
+		"marks_loaded": len(marks),
+		"marks_limit":  limit,
+		"duration":     duration,
+	}).Info("trademark marks cached")
+	return marks, nil
 }
 
-func (s *Server) handleListBatches(c *gin.Context) {
-	page, _ := strconv.Atoi(c.Query("page"))
-	if page < 0 {
-		page = 0
+// loadTrademarkScorer returns the cached trademark scorer, building its index from the cached
+// marks on first use or after invalidateTrademarkMarks. The scorer is read-only once built, so
+// concurrent jobs can share it.
+func (s *Server) loadTrademarkScorer() (*scoring.TrademarkScorer, []store.Mark, error) {
+	s.scorerMu.Lock()
+	defer s.scorerMu.Unlock()
+
+	marks, err := s.loadTrademarkMarks()
+	if err != nil {
+		return nil, nil, err
 	}
-	pageSize, _ := strconv.Atoi(c.Query("pageSize"))
-	if pageSize <= 0 {
-		pageSize = 25
+	if s.scorerCache != nil {
+		logrus.WithField("marks_cached", len(marks)).Info("reusing cached trademark index")
+		return s.scorerCache, marks, nil
 	}
-	offset := page * pageSize
+
+	start := time.Now()
+	scorer, err := scoring.NewTrademarkScorer(marks, s.seedPath, s.trademarkScores)
+	if err != nil {
+		return nil, marks, err
+	}
+	scorer = scorer.WithEmbeddedMatches(s.embeddedMarks)
+	s.scorerCache = scorer
+	logrus.WithFields(logrus.Fields{
+		"marks_indexed": len(marks),
+		"duration":      time.Since(start),
+	}).Info("trademark index built")
+	return scorer, marks, nil
+}
+
+// invalidateTrademarkMarks drops the cached marks and the index built from them so the next job
+// reloads both, e.g. after an ingest or a popular-token refresh changed the store. Running jobs
+// keep the slice and scorer they captured, which are never mutated. With preloading enabled the
+// reload starts right away in the background.
+func (s *Server) invalidateTrademarkMarks() {
+	s.scorerMu.Lock()
+	s.marksMu.Lock()
+	s.marksCache = nil
+	s.marksLoaded = false
+	s.scorerCache = nil
+	s.marksMu.Unlock()
+	s.scorerMu.Unlock()
+	logrus.Info("trademark marks cache invalidated")
+	if s.preload {
+		go s.preloadTrademarkIndex()
+	}
+}
+
+func (s *Server) handleListBatches(c *gin.Context) {
+	page, pageSize, offset := pageParams(c, s.batchesPage)
 
 	rows, total, err := s.db.ListCSVBatches(offset, pageSize)
 	if err != nil {
@@ -295,7 +843,13 @@ func (s *Server) handleListBatches(c *gin.Context) {
 	for _, row := range rows {
 		dtos = append(dtos, BatchFromModel(row))
 	}
-	c.JSON(http.StatusOK, BatchesResponse{Items: dtos, Total: total})
+	c.JSON(http.StatusOK, BatchesResponse{
+		Items:    dtos,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		HasNext:  hasNextPage(offset, len(dtos), total),
+	})
 }
 
 func (s *Server) handleGetBatch(c *gin.Context) {
@@ -322,9 +876,153 @@ func (s *Server) handleGetBatch(c *gin.Context) {
 	}
 	dto := BatchFromModel(*batch)
 	dto.ProcessedDomains = processed
+	lastRun, err := s.db.LatestBatchRequest(batch.ID)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if lastRun != nil {
+		runDTO := BatchRequestFromModel(*lastRun)
+		dto.LastRun = &runDTO
+	}
 	c.JSON(http.StatusOK, dto)
 }
 
+// handleResetBatch deletes the batch's evaluations so it can be re-run from scratch.
+func (s *Server) handleResetBatch(c *gin.Context) {
+	batchID, err := parseUintParam(c.Param("id"))
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+	force := false
+	if raw := strings.TrimSpace(c.Query("force")); raw != "" {
+		force, err = strconv.ParseBool(raw)
+		if err != nil {
+			s.renderError(c, http.StatusBadRequest, fmt.Errorf("invalid force: %s", raw))
+			return
+		}
+	}
+
+	batch, err := s.db.GetCSVBatch(batchID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.renderError(c, http.StatusNotFound, fmt.Errorf("batch %d not found", batchID))
+		} else {
+			s.renderError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	s.jobMu.Lock()
+	defer s.jobMu.Unlock()
+	if s.activeJob != nil {
+		s.renderError(c, http.StatusConflict, errors.New("evaluation running; cancel it before resetting a batch"))
+		return
+	}
+
+	// Evaluations are shared by domain, so resetting would also clear other batches' results.
+	if !force {
+		shared, err := s.db.BatchesSharingDomains(batch.ID)
+		if err != nil {
+			s.renderError(c, http.StatusInternalServerError, err)
+			return
+		}
+		if len(shared) > 0 {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":            fmt.Sprintf("batch %d shares evaluations with other batches; pass force=true to reset them too", batch.ID),
+				"affected_batches": shared,
+			})
+			return
+		}
+	}
+
+	deleted, err := s.db.ClearBatchEvaluations(batch.ID)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	requestLogger(c).WithFields(logrus.Fields{
+		"batch_id": batch.ID,
+		"deleted":  deleted,
+	}).Info("batch evaluations reset")
+
+	batch, err = s.db.GetCSVBatch(batch.ID)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"batch":               BatchFromModel(*batch),
+		"deleted_evaluations": deleted,
+	})
+}
+
+// handleRecomputeBatchStats rebuilds a batch's counts from its stored rows and the evaluations
+// table, correcting drift after resets, re-evaluations, or evaluations removed out of band.
+// Existing domains are those evaluated before the batch was created. Merged uploads only store
+// rows for domains new to the batch, so their repeat rows drop out of the row and duplicate counts.
+func (s *Server) handleRecomputeBatchStats(c *gin.Context) {
+	batchID, err := parseUintParam(c.Param("id"))
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	batch, err := s.db.GetCSVBatch(batchID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.renderError(c, http.StatusNotFound, fmt.Errorf("batch %d not found", batchID))
+		} else {
+			s.renderError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	rowCount, err := s.db.CountBatchRows(batch.ID)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	uniqueCount, err := s.db.CountBatchDomains(batch.ID)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	existingCount, err := s.db.CountBatchPriorResults(batch.ID, batch.CreatedAt)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	processedCount, err := s.db.CountBatchResults(batch.ID)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if err := s.db.UpdateCSVBatchStats(batch.ID, rowCount, uniqueCount, existingCount, rowCount-uniqueCount, processedCount); err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	updated, err := s.db.GetCSVBatch(batch.ID)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	requestLogger(c).WithFields(logrus.Fields{
+		"batch_id":  batch.ID,
+		"rows":      fmt.Sprintf("%d -> %d", batch.RowCount, rowCount),
+		"unique":    fmt.Sprintf("%d -> %d", batch.UniqueDomains, uniqueCount),
+		"existing":  fmt.Sprintf("%d -> %d", batch.ExistingDomains, existingCount),
+		"duplicate": fmt.Sprintf("%d -> %d", batch.DuplicateRows, rowCount-uniqueCount),
+		"processed": fmt.Sprintf("%d -> %d", batch.ProcessedDomains, processedCount),
+	}).Info("batch stats recomputed")
+	c.JSON(http.StatusOK, gin.H{
+		"batch":    BatchFromModel(*updated),
+		"previous": BatchFromModel(*batch),
+	})
+}
+
 func (s *Server) handleBatchResults(c *gin.Context) {
 	batchID, err := parseUintParam(c.Param("id"))
 	if err != nil {
@@ -342,6 +1040,94 @@ func (s *Server) handleBatchResults(c *gin.Context) {
 	s.renderResults(c, batchID)
 }
 
+// handleSkippedDomains pages through the rows of a batch that evaluation skipped, with reasons.
+func (s *Server) handleSkippedDomains(c *gin.Context) {
+	batchID, err := parseUintParam(c.Param("id"))
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+	if _, err := s.db.GetCSVBatch(batchID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.renderError(c, http.StatusNotFound, fmt.Errorf("batch %d not found", batchID))
+		} else {
+			s.renderError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	page, pageSize, offset := pageParams(c, s.resultsPage)
+
+	rows, total, err := s.db.ListSkippedDomains(batchID, offset, pageSize)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	items := make([]SkippedDomainDTO, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, SkippedDomainDTO{
+			Domain:    row.Domain,
+			RowIndex:  row.RowIndex,
+			Reason:    row.Reason,
+			SkippedAt: row.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, SkippedDomainsResponse{
+		BatchID:  batchID,
+		Items:    items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		HasNext:  hasNextPage(offset, len(items), total),
+	})
+}
+
+// handleValidateUpload parses a domains CSV the way /api/upload does and reports what it found,
+// without storing anything. The temporary copy of the file is removed before returning.
+func (s *Server) handleValidateUpload(c *gin.Context) {
+	path, fileHeader, cleanup, ok := s.receiveCSV(c, "domains")
+	if !ok {
+		return
+	}
+	defer cleanup()
+
+	parsed, err := parseDomainCSV(path, s.uploadRows, s.csvComment)
+	if err != nil {
+		if errors.Is(err, errTooManyRows) {
+			err = fmt.Errorf("%w (limit %d)", err, s.uploadRows)
+		}
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+	if parsed.rowCount == 0 {
+		s.renderError(c, http.StatusBadRequest, errors.New("no domains detected in csv"))
+		return
+	}
+
+	sample := parsed.uniqueDomains
+	if len(sample) > uploadSampleSize {
+		sample = sample[:uploadSampleSize]
+	}
+	unscoreable := 0
+	for _, domain := range parsed.uniqueDomains {
+		if match.CheckProfile(match.NormalizeDomain(domain)) != nil {
+			unscoreable++
+		}
+	}
+	c.JSON(http.StatusOK, UploadValidationResponse{
+		Filename:            fileHeader.Filename,
+		RowCount:            parsed.rowCount,
+		UniqueDomains:       len(parsed.uniqueDomains),
+		DuplicateRows:       parsed.duplicateRows,
+		DomainColumn:        parsed.domainColumn,
+		DomainHeader:        parsed.domainHeader,
+		Sample:              sample,
+		Unscoreable:         unscoreable,
+		Duplicates:          parsed.duplicateGroups,
+		DuplicatesTruncated: parsed.duplicateTruncated,
+		SkippedRows:         parsed.skippedRows,
+	})
+}
+
 func (s *Server) handleRequestStatus(c *gin.Context) {
 	requestID, err := parseUintParam(c.Param("id"))
 	if err != nil {
@@ -362,40 +1148,157 @@ func (s *Server) handleRequestStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, BatchRequestFromModel(*request))
 }
 
-func (s *Server) handleUpload(c *gin.Context) {
-	batchName := strings.TrimSpace(c.PostForm("batch_name"))
-	if batchName == "" {
-		s.renderError(c, http.StatusBadRequest, errors.New("batch_name is required"))
+// handleListBatchRequests returns a page of a batch's evaluation history, including the
+// re-evaluations that may have touched it, optionally filtered by the status and type query
+// parameters.
+func (s *Server) handleListBatchRequests(c *gin.Context) {
+	batchID, err := parseUintParam(c.Param("id"))
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+	if _, err := s.db.GetCSVBatch(batchID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.renderError(c, http.StatusNotFound, fmt.Errorf("batch %d not found", batchID))
+		} else {
+			s.renderError(c, http.StatusInternalServerError, err)
+		}
 		return
 	}
-	ownerName := strings.TrimSpace(c.PostForm("owner_name"))
-	if ownerName == "" {
-		s.renderError(c, http.StatusBadRequest, errors.New("owner_name is required"))
+
+	page, pageSize, offset := pageParams(c, s.batchesPage)
+	rows, total, err := s.db.ListBatchRequests(batchID, store.BatchRequestQuery{
+		Status: c.Query("status"),
+		Type:   c.Query("type"),
+		Offset: offset,
+		Limit:  pageSize,
+	})
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
 		return
 	}
+	items := make([]BatchRequestDTO, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, BatchRequestFromModel(row))
+	}
+	c.JSON(http.StatusOK, BatchRequestsResponse{
+		BatchID:  batchID,
+		Items:    items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		HasNext:  hasNextPage(offset, len(items), total),
+	})
+}
+
+// receiveCSV saves the CSV uploaded as field to a temporary file, enforcing the upload size
+// limit on both the request body and the file itself. When ok is false the error response has
+// already been written; otherwise the caller must call cleanup once it is done with path.
+func (s *Server) receiveCSV(c *gin.Context, field string) (path string, header *multipart.FileHeader, cleanup func(), ok bool) {
+	if c.Request.ContentLength > s.uploadMax+multipartOverhead {
+		s.renderError(c, http.StatusRequestEntityTooLarge, fmt.Errorf("upload exceeds %d bytes", s.uploadMax))
+		return "", nil, nil, false
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, s.uploadMax+multipartOverhead)
 
-	fileHeader, err := c.FormFile("domains")
+	header, err := c.FormFile(field)
 	if err != nil {
-		status := http.StatusBadRequest
-		if errors.Is(err, http.ErrMissingFile) {
-			s.renderError(c, status, errors.New("domains csv file is required"))
-		} else {
-			s.renderError(c, status, err)
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.Is(err, http.ErrMissingFile):
+			s.renderValidation(c, []FieldError{{Field: field, Message: "is required"}})
+		case errors.As(err, &tooLarge):
+			s.renderError(c, http.StatusRequestEntityTooLarge, fmt.Errorf("upload exceeds %d bytes", s.uploadMax))
+		default:
+			s.renderError(c, http.StatusBadRequest, err)
 		}
-		return
+		return "", nil, nil, false
+	}
+	if header.Size > s.uploadMax {
+		s.renderError(c, http.StatusRequestEntityTooLarge, fmt.Errorf("upload exceeds %d bytes", s.uploadMax))
+		return "", nil, nil, false
 	}
 
-	path, cleanup, err := saveFormFile(fileHeader)
+	path, cleanup, err = saveFormFile(header)
 	if err != nil {
 		s.renderError(c, http.StatusInternalServerError, err)
+		return "", nil, nil, false
+	}
+	return path, header, cleanup, true
+}
+
+func (s *Server) handleUpload(c *gin.Context) {
+	// The file is received first so the size limit is in place before the form is parsed.
+	path, fileHeader, cleanup, ok := s.receiveCSV(c, "domains")
+	if !ok {
+		return
+	}
+	defer cleanup()
+
+	form := UploadForm{
+		BatchName: strings.TrimSpace(c.PostForm("batch_name")),
+		OwnerName: strings.TrimSpace(c.PostForm("owner_name")),
+	}
+	var invalid []FieldError
+	if raw := strings.TrimSpace(c.PostForm("batch_id")); raw != "" {
+		batchID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil || batchID == 0 {
+			invalid = append(invalid, FieldError{Field: "batch_id", Message: "must be a positive integer"})
+		}
+		form.BatchID = uint(batchID)
+	}
+	// A malformed batch_id already explains itself; skip the name checks that depend on it.
+	if len(invalid) == 0 {
+		if err := binding.Validator.ValidateStruct(&form); err != nil {
+			fields := fieldErrors(&form, err)
+			if len(fields) == 0 {
+				s.renderError(c, http.StatusBadRequest, err)
+				return
+			}
+			invalid = append(invalid, fields...)
+		}
+	}
+
+	if len(invalid) > 0 {
+		s.renderValidation(c, invalid)
+		return
+	}
+
+	var (
+		mergeInto *store.CSVBatch
+		err       error
+	)
+	if form.BatchID != 0 {
+		mergeInto, err = s.db.GetCSVBatch(form.BatchID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				s.renderError(c, http.StatusNotFound, fmt.Errorf("batch %d not found", form.BatchID))
+			} else {
+				s.renderError(c, http.StatusInternalServerError, err)
+			}
+			return
+		}
+	}
+	batchName, ownerName := form.BatchName, form.OwnerName
+
+	var mergeID uint
+	if mergeInto != nil {
+		mergeID = mergeInto.ID
+	}
+	uploadKey, err := s.uploadIdempotencyKey(c, path, batchName, ownerName, mergeID)
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
 		return
 	}
-	if cleanup != nil {
-		defer cleanup()
+	if s.replayUpload(c, uploadKey) {
+		return
 	}
 
-	parsed, err := parseDomainCSV(path)
+	parsed, err := parseDomainCSV(path, s.uploadRows, s.csvComment)
 	if err != nil {
+		if errors.Is(err, errTooManyRows) {
+			err = fmt.Errorf("%w (limit %d)", err, s.uploadRows)
+		}
 		s.renderError(c, http.StatusBadRequest, err)
 		return
 	}
@@ -416,6 +1319,18 @@ func (s *Server) handleUpload(c *gin.Context) {
 		return
 	}
 	existingCount := len(existing)
+	// Checked before the domains below are saved, so it reflects earlier uploads only.
+	known, err := s.db.ExistingDomainKeys(parsed.uniqueNormalized)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	knownCount := len(known)
+
+	if mergeInto != nil {
+		s.mergeUpload(c, mergeInto, parsed, existing, knownCount, int(marksCount), uploadKey)
+		return
+	}
 
 	batch, err := s.db.CreateCSVBatch(batchName, ownerName, fileHeader.Filename)
 	if err != nil {
@@ -457,36 +1372,128 @@ func (s *Server) handleUpload(c *gin.Context) {
 		return
 	}
 
+	s.rememberUpload(c, uploadKey, batch.ID, false)
+	c.JSON(http.StatusOK, UploadResponse{
+		BatchID:             batch.ID,
+		BatchName:           batch.Name,
+		Owner:               batch.Owner,
+		RowCount:            parsed.rowCount,
+		UniqueDomains:       len(parsed.domainModels),
+		ExistingDomains:     existingCount,
+		DuplicateRows:       parsed.duplicateRows,
+		Processed:           processedCount,
+		MarksCount:          int(marksCount),
+		Duplicates:          parsed.duplicateGroups,
+		DuplicatesTruncated: parsed.duplicateTruncated,
+		SkippedRows:         parsed.skippedRows,
+		NewDomains:          len(parsed.uniqueNormalized) - knownCount,
+		KnownDomains:        knownCount,
+	})
+}
+
+// mergeUpload appends the domains of an upload that are not yet in the batch, so a following
+// evaluate with resume only processes the additions. The result is recorded under uploadKey.
+func (s *Server) mergeUpload(c *gin.Context, batch *store.CSVBatch, parsed *csvParseResult, existing map[string]struct{}, knownCount, marksCount int, uploadKey uploadKey) {
+	added, err := s.db.NewBatchDomainKeys(batch.ID, parsed.uniqueNormalized)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	rows := make([]store.DomainBatch, 0, len(added))
+	for _, row := range parsed.domainBatches {
+		if _, ok := added[row.DomainNormalized]; ok {
+			rows = append(rows, row)
+		}
+	}
+	addedExisting := 0
+	for _, domain := range parsed.domainModels {
+		if _, ok := added[domain.DomainNormalized]; !ok {
+			continue
+		}
+		if _, ok := existing[domain.DomainNormalized]; ok {
+			addedExisting++
+		}
+		if err := s.db.SaveDomain(domain); err != nil {
+			s.renderError(c, http.StatusInternalServerError, fmt.Errorf("save domain %s: %w", domain.Domain, err))
+			return
+		}
+	}
+	if err := s.db.AppendDomainBatch(batch.ID, rows); err != nil {
+		s.renderError(c, http.StatusInternalServerError, fmt.Errorf("store batch domains: %w", err))
+		return
+	}
+
+	uniqueCount, err := s.db.CountBatchDomains(batch.ID)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	processedCount, err := s.db.CountBatchResults(batch.ID)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	rowCount := batch.RowCount + parsed.rowCount
+	existingCount := batch.ExistingDomains + addedExisting
+	// Every row beyond the first for each added domain is a duplicate, including rows for domains
+	// the batch already had.
+	duplicateRows := batch.DuplicateRows + parsed.rowCount - len(added)
+	if err := s.db.UpdateCSVBatchStats(batch.ID, rowCount, uniqueCount, existingCount, duplicateRows, processedCount); err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	requestLogger(c).WithFields(logrus.Fields{
+		"batch_id": batch.ID,
+		"added":    len(added),
+		"rows":     parsed.rowCount,
+	}).Info("merged upload into existing batch")
+	s.rememberUpload(c, uploadKey, batch.ID, true)
 	c.JSON(http.StatusOK, UploadResponse{
-		BatchID:         batch.ID,
-		BatchName:       batch.Name,
-		Owner:           batch.Owner,
-		RowCount:        parsed.rowCount,
-		UniqueDomains:   len(parsed.domainModels),
-		ExistingDomains: existingCount,
-		DuplicateRows:   parsed.duplicateRows,
-		Processed:       processedCount,
-		MarksCount:      int(marksCount),
+		BatchID:             batch.ID,
+		BatchName:           batch.Name,
+		Owner:               batch.Owner,
+		RowCount:            rowCount,
+		UniqueDomains:       uniqueCount,
+		ExistingDomains:     existingCount,
+		DuplicateRows:       duplicateRows,
+		Processed:           processedCount,
+		MarksCount:          marksCount,
+		Merged:              true,
+		AddedDomains:        len(added),
+		Duplicates:          parsed.duplicateGroups,
+		DuplicatesTruncated: parsed.duplicateTruncated,
+		SkippedRows:         parsed.skippedRows,
+		NewDomains:          len(parsed.uniqueNormalized) - knownCount,
+		KnownDomains:        knownCount,
 	})
 }
 
 func (s *Server) handleEvaluate(c *gin.Context) {
 	var req EvaluateRequest
-	if c.Request.Body != nil {
-		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
-			s.renderError(c, http.StatusBadRequest, err)
-			return
-		}
+	err := c.ShouldBindJSON(&req)
+	if errors.Is(err, io.EOF) {
+		// An empty body skips binding validation but must still fail on the required fields.
+		err = binding.Validator.ValidateStruct(&req)
 	}
-
-	if req.BatchID == 0 {
-		s.renderError(c, http.StatusBadRequest, errors.New("batch_id is required"))
+	if err != nil {
+		s.renderBindingError(c, &req, err)
 		return
 	}
+	s.launchEvaluation(c, req, 0)
+}
 
+// launchEvaluation starts an evaluation of req.BatchID and responds 202 with the job, or with
+// the error that prevented it. retryOf is the batch request being retried, if any.
+func (s *Server) launchEvaluation(c *gin.Context, req EvaluateRequest, retryOf uint) {
 	batch, err := s.db.GetCSVBatch(req.BatchID)
 	if err != nil {
-		s.renderError(c, http.StatusNotFound, fmt.Errorf("batch %d not found", req.BatchID))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.renderError(c, http.StatusNotFound, fmt.Errorf("batch %d not found", req.BatchID))
+		} else {
+			s.renderError(c, http.StatusInternalServerError, err)
+		}
 		return
 	}
 
@@ -500,6 +1507,8 @@ func (s *Server) handleEvaluate(c *gin.Context) {
 		return
 	}
 
+	req.Limit = s.evalLimit.clamp(req.Limit)
+
 	s.jobMu.Lock()
 	defer s.jobMu.Unlock()
 	if s.activeJob != nil {
@@ -507,22 +1516,71 @@ func (s *Server) handleEvaluate(c *gin.Context) {
 		return
 	}
 
-	job, err := s.startEvaluation(req, batch, int64(totalDomains))
+	job, err := s.startEvaluation(req, batch, int64(totalDomains), requestIDFrom(c), retryOf)
 	if err != nil {
 		s.renderError(c, http.StatusInternalServerError, err)
 		return
 	}
 
+	requestLogger(c).WithFields(logrus.Fields{
+		"job":      job.id,
+		"batch_id": batch.ID,
+		"retry_of": retryOf,
+	}).Info("evaluation requested")
+
 	response := StartEvaluationResponse{
 		JobID:     job.id,
 		BatchID:   batch.ID,
 		RequestID: job.requestID,
+		RetryOf:   retryOf,
 		Total:     job.total,
 		StartedAt: job.startedAt,
 	}
 	c.JSON(http.StatusAccepted, response)
 }
 
+// retryableStatuses are the terminal batch request statuses that POST /requests/:id/retry accepts.
+var retryableStatuses = map[string]bool{"failed": true, "cancelled": true, "interrupted": true}
+
+func (s *Server) handleRetryRequest(c *gin.Context) {
+	requestID, err := parseUintParam(c.Param("id"))
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+	request, err := s.db.GetBatchRequest(requestID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.renderError(c, http.StatusNotFound, fmt.Errorf("request %d not found", requestID))
+		} else {
+			s.renderError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	if !retryableStatuses[request.Status] {
+		s.renderError(c, http.StatusConflict, fmt.Errorf("request %d is %s; only failed, cancelled, or interrupted requests can be retried", requestID, request.Status))
+		return
+	}
+	if request.Type == "reevaluate" {
+		// The selected domains were not stored with the request; post the filter again instead.
+		s.renderError(c, http.StatusConflict, fmt.Errorf("request %d is a re-evaluation and cannot be retried", requestID))
+		return
+	}
+
+	// Requests recorded before parameters were stored retry with the defaults.
+	req := EvaluateRequest{BatchID: request.BatchID}
+	if strings.TrimSpace(request.ParamsJSON) != "" {
+		if err := json.Unmarshal([]byte(request.ParamsJSON), &req); err != nil {
+			s.renderError(c, http.StatusInternalServerError, fmt.Errorf("decode request %d parameters: %w", requestID, err))
+			return
+		}
+	}
+	req.BatchID = request.BatchID
+	req.Resume = true
+	req.Force = false
+	s.launchEvaluation(c, req, request.ID)
+}
+
 func (s *Server) handleCancelEvaluate(c *gin.Context) {
 	jobID := strings.TrimSpace(c.Param("jobID"))
 	if jobID == "" {
@@ -542,7 +1600,7 @@ func (s *Server) handleCancelEvaluate(c *gin.Context) {
 	}
 
 	s.activeJob.cancel()
-	logrus.WithField("job", jobID).Info("evaluation cancellation requested")
+	requestLogger(c).WithField("job", jobID).Info("evaluation cancellation requested")
 	s.evalNotifier.Broadcast(EvaluationEvent{
 		Type:      "progress",
 		JobID:     s.activeJob.id,
@@ -614,20 +1672,20 @@ func (s *Server) handleEvaluateStream(c *gin.Context) {
 
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
-		logrus.WithError(err).Warn("upgrade websocket")
+		requestLogger(c).WithError(err).Warn("upgrade websocket")
 		return
 	}
 
 	client := s.evalNotifier.Register(conn)
-	logrus.WithField("remote", conn.RemoteAddr().String()).Info("evaluation websocket connected")
+	requestLogger(c).WithField("remote", conn.RemoteAddr().String()).Info("evaluation websocket connected")
 	defer s.evalNotifier.Unregister(client)
 
 	for {
 		if _, _, err := conn.ReadMessage(); err != nil {
 			if !websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				logrus.WithField("remote", conn.RemoteAddr().String()).Info("evaluation websocket closed")
+				requestLogger(c).WithField("remote", conn.RemoteAddr().String()).Info("evaluation websocket closed")
 			} else {
-				logrus.WithError(err).Warn("evaluation websocket unexpected close")
+				requestLogger(c).WithError(err).Warn("evaluation websocket unexpected close")
 			}
 			break
 		}
@@ -635,47 +1693,100 @@ func (s *Server) handleEvaluateStream(c *gin.Context) {
 }
 
 func (s *Server) handleResults(c *gin.Context) {
-	batchID := uint(0)
-	if value := strings.TrimSpace(firstNonEmpty(c.Query("batch_id"), c.Query("batchId"))); value != "" {
-		parsed, err := strconv.ParseUint(value, 10, 64)
-		if err != nil || parsed == 0 {
-			s.renderError(c, http.StatusBadRequest, fmt.Errorf("invalid batch_id: %s", value))
-			return
-		}
-		batchID = uint(parsed)
+	batchID, err := exportBatchID(c)
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
 	}
 	s.renderResults(c, batchID)
 }
 
-func (s *Server) renderResults(c *gin.Context, batchID uint) {
-	query := strings.TrimSpace(c.Query("q"))
-	minScore, _ := strconv.Atoi(c.Query("minScore"))
-	page, _ := strconv.Atoi(c.Query("page"))
-	if page < 0 {
-		page = 0
+// handleResultsCount returns only the number of evaluations matching the /api/results filters.
+func (s *Server) handleResultsCount(c *gin.Context) {
+	batchID, err := exportBatchID(c)
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+	filter, err := resultsFilter(c, batchID)
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
 	}
-	pageSize, _ := strconv.Atoi(c.Query("pageSize"))
-	if pageSize <= 0 {
-		pageSize = 100
+	total, err := s.db.CountEvaluations(filter)
+	if err != nil {
+		s.renderError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"total": total})
+}
+
+// exportBatchID reads the optional batch_id (or batchId) query parameter used by the results and
+// export endpoints. It returns 0 when the parameter is absent.
+func exportBatchID(c *gin.Context) (uint, error) {
+	value := strings.TrimSpace(firstNonEmpty(c.Query("batch_id"), c.Query("batchId")))
+	if value == "" {
+		return 0, nil
 	}
-	offset := page * pageSize
+	parsed, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || parsed == 0 {
+		return 0, fmt.Errorf("invalid batch_id: %s", value)
+	}
+	return uint(parsed), nil
+}
 
+// resultsFilter reads the result filter query parameters shared by listing and counting. An
+// unknown recommendation is an error rather than a filter that silently matches nothing.
+func resultsFilter(c *gin.Context, batchID uint) (store.EvaluationQuery, error) {
+	minScore, _ := strconv.Atoi(c.Query("minScore"))
 	minViceScore, _ := strconv.Atoi(c.Query("minViceScore"))
-	tld := strings.TrimSpace(c.Query("tld"))
-	recommendation := strings.TrimSpace(c.Query("recommendation"))
-	sort := strings.TrimSpace(c.Query("sort"))
-
-	rows, total, err := s.db.ListEvaluations(store.EvaluationQuery{
-		Query:          query,
-		MinTrademark:   minScore,
-		MinVice:        minViceScore,
-		TLD:            tld,
-		Recommendation: recommendation,
-		Sort:           sort,
-		Offset:         offset,
-		Limit:          pageSize,
-		BatchID:        batchID,
-	})
+	filter := store.EvaluationQuery{
+		Query:        strings.TrimSpace(c.Query("q")),
+		MinTrademark: minScore,
+		MinVice:      minViceScore,
+		TLD:          strings.TrimSpace(c.Query("tld")),
+		BatchID:      batchID,
+		ViceCategory: strings.TrimSpace(c.Query("viceCategory")),
+	}
+	if raw := strings.TrimSpace(c.Query("recommendation")); raw != "" {
+		rec, err := scoring.ParseRecommendation(raw)
+		if err != nil {
+			return filter, fmt.Errorf("%w; expected one of %s", err, strings.Join(scoring.RecommendationNames(), ", "))
+		}
+		filter.Recommendation = string(rec)
+	}
+	filter.MinCommercialPrice, _ = strconv.ParseFloat(c.Query("minCommercialPrice"), 64)
+	if raw := strings.TrimSpace(c.Query("commercialOverride")); raw != "" {
+		override, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, fmt.Errorf("invalid commercialOverride: %s", raw)
+		}
+		filter.CommercialOverride = &override
+	}
+	if raw := strings.TrimSpace(c.Query("updatedSince")); raw != "" {
+		since, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, fmt.Errorf("invalid updatedSince (want RFC3339): %s", raw)
+		}
+		// Timestamps are stored in server-local time and compared as text.
+		filter.UpdatedSince = since.In(time.Local)
+	}
+	return filter, nil
+}
+
+func (s *Server) renderResults(c *gin.Context, batchID uint) {
+	page, pageSize, offset := pageParams(c, s.resultsPage)
+
+	filter, err := resultsFilter(c, batchID)
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+	filter.Sort = strings.TrimSpace(c.Query("sort"))
+	filter.Offset = offset
+	filter.Limit = pageSize
+
+	rows, total, err := s.db.ListEvaluations(filter)
 	if err != nil {
 		s.renderError(c, http.StatusInternalServerError, err)
 		return
@@ -684,18 +1795,30 @@ func (s *Server) renderResults(c *gin.Context, batchID uint) {
 	for _, row := range rows {
 		dtos = append(dtos, FromModel(row))
 	}
-	c.JSON(http.StatusOK, EvaluateResponse{Items: dtos, Total: total})
+	c.JSON(http.StatusOK, EvaluateResponse{
+		Items:    dtos,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		HasNext:  hasNextPage(offset, len(dtos), total),
+	})
+}
+
+// hasNextPage reports whether rows remain after a page of count items starting at offset.
+func hasNextPage(offset, count int, total int64) bool {
+	return int64(offset+count) < total
 }
 
 func (s *Server) handleExportCSV(c *gin.Context) {
-	batchID := uint(0)
-	if value := strings.TrimSpace(firstNonEmpty(c.Query("batch_id"), c.Query("batchId"))); value != "" {
-		parsed, err := strconv.ParseUint(value, 10, 64)
-		if err != nil || parsed == 0 {
-			s.renderError(c, http.StatusBadRequest, fmt.Errorf("invalid batch_id: %s", value))
-			return
-		}
-		batchID = uint(parsed)
+	batchID, err := exportBatchID(c)
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+	columns, err := csvExportSelection(c.Query("fields"))
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
 	}
 
 	rows, _, err := s.db.ListEvaluations(store.EvaluationQuery{Limit: -1, BatchID: batchID})
@@ -708,25 +1831,18 @@ func (s *Server) handleExportCSV(c *gin.Context) {
 	c.Header("Content-Type", "text/csv")
 
 	writer := csv.NewWriter(c.Writer)
-	headers := []string{"domain", "trademark_score", "trademark_type", "matched_trademark", "vice_score", "vice_categories", "overall_recommendation", "confidence", "ai_explanation", "commercial_override", "commercial_source", "commercial_similarity"}
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.name
+	}
 	if err := writer.Write(headers); err != nil {
 		return
 	}
+	line := make([]string, len(columns))
 	for _, row := range rows {
 		dto := FromModel(row)
-		line := []string{
-			dto.Domain,
-			strconv.Itoa(dto.TrademarkScore),
-			dto.TrademarkType,
-			dto.MatchedTrademark,
-			strconv.Itoa(dto.ViceScore),
-			strings.Join(dto.ViceCategories, "|"),
-			dto.OverallRecommendation,
-			fmt.Sprintf("%.2f", dto.Confidence),
-			dto.Explanation,
-			strconv.FormatBool(dto.CommercialOverride),
-			dto.CommercialSource,
-			fmt.Sprintf("%.2f", dto.CommercialSimilarity),
+		for i, col := range columns {
+			line[i] = col.value(dto)
 		}
 		if err := writer.Write(line); err != nil {
 			return
@@ -736,14 +1852,15 @@ func (s *Server) handleExportCSV(c *gin.Context) {
 }
 
 func (s *Server) handleExportJSON(c *gin.Context) {
-	batchID := uint(0)
-	if value := strings.TrimSpace(firstNonEmpty(c.Query("batch_id"), c.Query("batchId"))); value != "" {
-		parsed, err := strconv.ParseUint(value, 10, 64)
-		if err != nil || parsed == 0 {
-			s.renderError(c, http.StatusBadRequest, fmt.Errorf("invalid batch_id: %s", value))
-			return
-		}
-		batchID = uint(parsed)
+	batchID, err := exportBatchID(c)
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+	fields, err := jsonExportSelection(c.Query("fields"))
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
 	}
 
 	rows, _, err := s.db.ListEvaluations(store.EvaluationQuery{Limit: -1, BatchID: batchID})
@@ -751,14 +1868,163 @@ func (s *Server) handleExportJSON(c *gin.Context) {
 		s.renderError(c, http.StatusInternalServerError, err)
 		return
 	}
-	dtos := make([]EvaluationDTO, 0, len(rows))
+	dtos := make([]any, 0, len(rows))
 	for _, row := range rows {
-		dtos = append(dtos, FromModel(row))
+		dtos = append(dtos, projectDTO(FromModel(row), fields))
 	}
 	c.Header("Content-Disposition", "attachment; filename=domain-risk-export.json")
 	c.JSON(http.StatusOK, dtos)
 }
 
+// handleExportNDJSON streams matching evaluations as newline-delimited JSON, one EvaluationDTO
+// per line, writing each row as it is read so neither side has to buffer the full export. It
+// accepts the /api/results filters and the fields projection.
+func (s *Server) handleExportNDJSON(c *gin.Context) {
+	batchID, err := exportBatchID(c)
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+	filter, err := resultsFilter(c, batchID)
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+	filter.Sort = strings.TrimSpace(c.Query("sort"))
+	fields, err := jsonExportSelection(c.Query("fields"))
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=domain-risk-export.ndjson")
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(c.Writer)
+	written := 0
+	err = s.db.EachEvaluation(filter, func(row store.Evaluation) error {
+		if err := encoder.Encode(projectDTO(FromModel(row), fields)); err != nil {
+			return err
+		}
+		written++
+		if written%500 == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// Headers are already sent, so the client sees a truncated stream.
+		requestLogger(c).WithError(err).WithField("rows", written).Error("ndjson export failed")
+		return
+	}
+	c.Writer.Flush()
+}
+
+// narrativeFormats maps the format query parameter of /api/export/narratives to its content type.
+var narrativeFormats = map[string]string{
+	"csv":      "text/csv",
+	"markdown": "text/markdown; charset=utf-8",
+}
+
+// handleExportNarratives streams a compact reviewer document of domain, recommendation, and AI
+// narrative as CSV (default) or a markdown table. actionable=true keeps only REVIEW and BLOCK rows.
+func (s *Server) handleExportNarratives(c *gin.Context) {
+	batchID, err := exportBatchID(c)
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+	filter, err := resultsFilter(c, batchID)
+	if err != nil {
+		s.renderError(c, http.StatusBadRequest, err)
+		return
+	}
+	filter.Sort = strings.TrimSpace(c.Query("sort"))
+	if raw := strings.TrimSpace(c.Query("actionable")); raw != "" {
+		actionable, err := strconv.ParseBool(raw)
+		if err != nil {
+			s.renderError(c, http.StatusBadRequest, fmt.Errorf("invalid actionable: %s", raw))
+			return
+		}
+		if actionable {
+			filter.Recommendations = []string{string(scoring.RecommendationReview), string(scoring.RecommendationBlock)}
+		}
+	}
+	format := strings.ToLower(strings.TrimSpace(c.DefaultQuery("format", "csv")))
+	if format == "md" {
+		format = "markdown"
+	}
+	contentType, ok := narrativeFormats[format]
+	if !ok {
+		s.renderError(c, http.StatusBadRequest, fmt.Errorf("invalid format %q; expected csv or markdown", format))
+		return
+	}
+
+	extension := format
+	if format == "markdown" {
+		extension = "md"
+	}
+	c.Header("Content-Disposition", "attachment; filename=domain-risk-narratives."+extension)
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+
+	var write func(store.Evaluation) error
+	var flush func() error
+	if format == "markdown" {
+		if _, err := io.WriteString(c.Writer, "| Domain | Recommendation | Explanation |\n| --- | --- | --- |\n"); err != nil {
+			return
+		}
+		write = func(row store.Evaluation) error {
+			_, err := fmt.Fprintf(c.Writer, "| %s | %s | %s |\n", markdownCell(row.Domain), markdownCell(row.OverallRecommendation), markdownCell(row.Explanation))
+			return err
+		}
+		flush = func() error { return nil }
+	} else {
+		writer := csv.NewWriter(c.Writer)
+		if err := writer.Write([]string{"domain", "overall_recommendation", "explanation"}); err != nil {
+			return
+		}
+		write = func(row store.Evaluation) error {
+			return writer.Write([]string{row.Domain, row.OverallRecommendation, row.Explanation})
+		}
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	}
+
+	written := 0
+	err = s.db.EachEvaluation(filter, func(row store.Evaluation) error {
+		if err := write(row); err != nil {
+			return err
+		}
+		written++
+		if written%500 == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		// Headers are already sent, so the client sees a truncated document.
+		requestLogger(c).WithError(err).WithField("rows", written).Error("narrative export failed")
+		return
+	}
+	c.Writer.Flush()
+}
+
+// markdownCell flattens a value onto one line and escapes pipes so it fits a table cell.
+func markdownCell(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	return strings.ReplaceAll(value, "|", "\\|")
+}
+
 func (s *Server) lookupUSPTO(ctx context.Context, brandToken string, cache map[string]usp.LookupResult) (usp.LookupResult, bool) {
 	if s.usptoClient == nil {
 		return usp.LookupResult{}, false
@@ -772,7 +2038,7 @@ func (s *Server) lookupUSPTO(ctx context.Context, brandToken string, cache map[s
 	}
 	result, err := s.usptoClient.LookupExact(ctx, key)
 	if err != nil {
-		logrus.WithError(err).Warn("usp lookup")
+		logrus.WithError(err).WithField("term", key).Warn("usp lookup")
 		cache[key] = usp.LookupResult{Term: key, Checked: false}
 		return usp.LookupResult{}, false
 	}
@@ -780,11 +2046,64 @@ func (s *Server) lookupUSPTO(ctx context.Context, brandToken string, cache map[s
 	return result, result.Checked
 }
 
+// prefetchUSPTO resolves the brand tokens of a page of batch rows with one bulk lookup per group
+// of terms, so workers find them in the job cache instead of issuing one request per token.
+// Failures are logged and left to the per-domain lookup.
+func (s *Server) prefetchUSPTO(ctx context.Context, rows []store.BatchDomain, skip map[string]struct{}, cache map[string]usp.LookupResult, cacheMu *sync.Mutex) {
+	tokens := make([]string, 0, len(rows))
+	seen := make(map[string]struct{}, len(rows))
+	cacheMu.Lock()
+	for _, row := range rows {
+		if _, ok := skip[strings.TrimSpace(row.DomainNormalized)]; ok {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(match.NormalizeDomain(row.Domain).BrandToken))
+		if key == "" {
+			continue
+		}
+		if _, ok := cache[key]; ok {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		tokens = append(tokens, key)
+	}
+	cacheMu.Unlock()
+	if len(tokens) == 0 {
+		return
+	}
+
+	start := time.Now()
+	results, err := s.usptoClient.BulkLookup(ctx, tokens)
+	if err != nil {
+		logrus.WithError(err).WithField("tokens", len(tokens)).Warn("usp bulk lookup")
+	}
+	cacheMu.Lock()
+	for key, result := range results {
+		if result.Checked {
+			cache[key] = result
+		}
+	}
+	cacheMu.Unlock()
+	logrus.WithFields(logrus.Fields{
+		"tokens":   len(tokens),
+		"resolved": len(results),
+		"duration": time.Since(start),
+	}).Debug("prefetched usp lookups")
+}
+
 func (s *Server) resolveTrademark(profile match.DomainProfile, hasLookup bool, lookup usp.LookupResult, fallback scoring.TrademarkResult) (scoring.TrademarkResult, []string) {
 	closeMatches := make([]string, 0)
 	sldToken := secondLevelToken(profile)
+	// similarFound records whether USPTO returned similar marks, as opposed to exact matches on
+	// another token, so only those mark a "none" result as uspto_similar.
+	similarFound := false
 
-	if fallback.Score > 0 && fallback.MatchedTrademark != "" {
+	// An embedded hit only covers part of the SLD, so a live exact match on the whole SLD wins.
+	embedded := fallback.Type == scoring.TrademarkTypeEmbedded && fallback.Score > 0
+	if fallback.Score > 0 && fallback.MatchedTrademark != "" && !embedded {
 		closeMatches = append(closeMatches, fallback.MatchedTrademark)
 		return fallback, uniqueStrings(closeMatches)
 	}
@@ -807,7 +2126,11 @@ func (s *Server) resolveTrademark(profile match.DomainProfile, hasLookup bool, l
 					Score:            5,
 					Type:             "fanciful",
 					MatchedTrademark: exact.Mark,
+					Owner:            exact.Owner,
+					Serial:           exact.SerialNumber,
+					Registration:     exact.RegistrationNumber,
 					Confidence:       0.98,
+					Source:           scoring.MatchSourceUSPTOExact,
 				}, uniqueStrings(closeMatches)
 			}
 			if scoring.IsPopularToken(exact.Mark) {
@@ -815,24 +2138,108 @@ func (s *Server) resolveTrademark(profile match.DomainProfile, hasLookup bool, l
 					Score:            2,
 					Type:             "popular",
 					MatchedTrademark: exact.Mark,
+					Owner:            exact.Owner,
+					Serial:           exact.SerialNumber,
+					Registration:     exact.RegistrationNumber,
 					Confidence:       0.75,
+					Source:           scoring.MatchSourceUSPTOExact,
 				}, uniqueStrings(closeMatches)
 			}
 			return scoring.TrademarkResult{
 				Score:            0,
 				Type:             "generic",
 				MatchedTrademark: exact.Mark,
+				Owner:            exact.Owner,
+				Serial:           exact.SerialNumber,
+				Registration:     exact.RegistrationNumber,
 				Confidence:       0.4,
+				Source:           scoring.MatchSourceUSPTOExact,
 			}, uniqueStrings(closeMatches)
 		}
 		for _, sim := range lookup.Similar {
 			if sim.Mark != "" {
 				closeMatches = append(closeMatches, sim.Mark)
+				similarFound = true
 			}
 		}
+		if similar, ok := s.similarMarkResult(lookup.Similar); ok && !(embedded && fallback.Score >= similar.Score) {
+			return similar, uniqueStrings(closeMatches)
+		}
+	}
+
+	if embedded {
+		closeMatches = append(closeMatches, fallback.MatchedTrademark)
+		return fallback, uniqueStrings(closeMatches)
+	}
+
+	result := scoring.TrademarkResult{Score: 0, Type: "none", Confidence: 0.4}
+	if similarFound {
+		result.Source = scoring.MatchSourceUSPTOSimilar
+	}
+	if hasLookup && lookup.Checked && s.similarOnly > 0 && hasLiveMark(lookup.Similar) {
+		result.Score, result.Type = s.similarOnly, scoring.TrademarkTypeSimilarOnly
+	}
+	return result, uniqueStrings(closeMatches)
+}
+
+func hasLiveMark(marks []usp.Mark) bool {
+	for _, mark := range marks {
+		if mark.Mark != "" && mark.IsLive {
+			return true
+		}
 	}
+	return false
+}
+
+// similarMarkResult picks the most similar live USPTO mark at or above the configured threshold
+// as a REVIEW-level "similar" trademark result when SimilarMarkReview is enabled.
+func (s *Server) similarMarkResult(similar []usp.Mark) (scoring.TrademarkResult, bool) {
+	if !s.similarReview {
+		return scoring.TrademarkResult{}, false
+	}
+	var best *usp.Mark
+	for i := range similar {
+		candidate := &similar[i]
+		if candidate.Mark == "" || !candidate.IsLive || candidate.Similarity < s.similarMin {
+			continue
+		}
+		if best == nil || candidate.Similarity > best.Similarity {
+			best = candidate
+		}
+	}
+	if best == nil {
+		return scoring.TrademarkResult{}, false
+	}
+	return scoring.TrademarkResult{
+		Score:            3,
+		Type:             "similar",
+		MatchedTrademark: best.Mark,
+		Owner:            best.Owner,
+		Serial:           best.SerialNumber,
+		Registration:     best.RegistrationNumber,
+		Confidence:       round2(0.7 * best.Similarity),
+		Source:           scoring.MatchSourceUSPTOSimilar,
+	}, true
+}
 
-	return scoring.TrademarkResult{Score: 0, Type: "none", Confidence: 0.4}, uniqueStrings(closeMatches)
+// trademarkConflictThreshold is the score at which a trademark signal counts as high risk when
+// comparing the heuristic index with the live USPTO lookup.
+const trademarkConflictThreshold = 4
+
+// detectTrademarkConflict reports whether the heuristic index and a completed USPTO lookup disagree
+// on whether the SLD is a high-risk trademark, with a description for the evaluation reasons.
+func (s *Server) detectTrademarkConflict(profile match.DomainProfile, hasLookup bool, lookup usp.LookupResult, fallback scoring.TrademarkResult) (bool, string) {
+	if !hasLookup || !lookup.Checked {
+		return false, ""
+	}
+	live, _ := s.resolveTrademark(profile, hasLookup, lookup, scoring.TrademarkResult{})
+	heuristicHigh := fallback.Score >= trademarkConflictThreshold
+	liveHigh := live.Score >= trademarkConflictThreshold
+	if heuristicHigh == liveHigh {
+		return false, ""
+	}
+	return true, fmt.Sprintf("trademark sources disagree: index %s score %d vs USPTO %s score %d",
+		fallback.Type, fallback.Score, live.Type, live.Score)
 }
 
 func cleanToken(value string) string {
@@ -905,6 +2312,12 @@ func saveFormFile(header *multipart.FileHeader) (string, func(), error) {
 	return tmp.Name(), cleanup, nil
 }
 
+// Caps on the duplicate report returned with an upload.
+const (
+	maxDuplicateGroups   = 100
+	maxDuplicateVariants = 20
+)
+
 type csvParseResult struct {
 	domainModels     []*store.Domain
 	domainBatches    []store.DomainBatch
@@ -912,66 +2325,73 @@ type csvParseResult struct {
 	uniqueNormalized []string
 	rowCount         int
 	duplicateRows    int
+	// duplicateGroups lists normalized domains that appeared on more than one row, in order of
+	// first appearance and capped at maxDuplicateGroups.
+	duplicateGroups    []DuplicateGroup
+	duplicateTruncated bool
+	// domainColumn is the zero-based column domains were read from; domainHeader is its header
+	// cell, empty when the file has no recognised header row.
+	domainColumn int
+	domainHeader string
+	// skippedRows counts data rows without a domain: too short to reach the domain column, or
+	// with that cell blank.
+	skippedRows int
 }
 
-func parseDomainCSV(path string) (*csvParseResult, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	reader := csv.NewReader(f)
-	reader.FieldsPerRecord = -1
-	reader.TrimLeadingSpace = true
+// domainKey returns the key the store deduplicates domain under, matching the DecodeDomain
+// NewServer configures so both spellings of an IDN collapse.
+func domainKey(domain string) string {
+	return match.DecodePunycode(store.NormalizeDomainKey(domain))
+}
 
+// parseDomainCSV reads domains from the CSV at path, failing with errTooManyRows once more than
+// maxRows domain rows are seen; maxRows <= 0 disables the cap. The domain column is the first
+// header named like one, or the first column when there is no such header. Lines starting with
+// comment (0 for none) are skipped and quoted fields may contain commas. A stray quote fails the
+// parse with its line number rather than swallowing the rows after it, and rows without a domain
+// are counted in skippedRows. UTF-16 files and byte order marks are handled by decodeCSV.
+func parseDomainCSV(path string, maxRows int, comment rune) (*csvParseResult, error) {
 	var (
-		domainCol       = -1
-		headerProcessed bool
-		uniqueMap       = make(map[string]*store.Domain)
-		order           []string
-		batches         []store.DomainBatch
-		rowIndex        int
+		detectedCol  int
+		domainHeader string
+		uniqueMap    = make(map[string]*store.Domain)
+		order        []string
+		batches      []store.DomainBatch
+		rowIndex     int
+		skipped      int
+		variants     = make(map[string]*DuplicateGroup)
 	)
 
-	for {
-		record, err := reader.Read()
-		if errors.Is(err, io.EOF) {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("read csv: %w", err)
-		}
-		if len(record) == 0 {
-			continue
-		}
-
-		if !headerProcessed {
-			domainCol = detectDomainColumn(record)
-			headerProcessed = true
-			if domainCol >= 0 {
-				continue // header row, move to next record
-			}
-			domainCol = 0
-		}
-
-		if domainCol < 0 || domainCol >= len(record) {
-			domainCol = 0
+	err := eachCSVRecord(path, comment, func(record []string, domainCol int) error {
+		detectedCol, domainHeader = domainCol, strings.TrimSpace(record[domainCol])
+		return nil
+	}, func(record []string, domainCol, _ int) error {
+		if domainCol >= len(record) {
+			skipped++
+			return nil
 		}
 
 		value := strings.TrimSpace(record[domainCol])
 		if value == "" {
-			continue
-		}
-		value = strings.TrimPrefix(value, "\ufeff")
-		value = strings.TrimSpace(value)
-		if value == "" {
-			continue
+			skipped++
+			return nil
 		}
 
 		rowIndex++
-		key := strings.ToLower(strings.TrimSpace(value))
+		if maxRows > 0 && rowIndex > maxRows {
+			return errTooManyRows
+		}
+		key := domainKey(value)
 		batches = append(batches, store.DomainBatch{Domain: value, DomainNormalized: key, RowIndex: rowIndex})
+		group := variants[key]
+		if group == nil {
+			group = &DuplicateGroup{Normalized: key}
+			variants[key] = group
+		}
+		group.Count++
+		if len(group.Variants) < maxDuplicateVariants {
+			group.Variants = append(group.Variants, DuplicateVariant{Row: rowIndex, Domain: value})
+		}
 
 		if _, ok := uniqueMap[key]; !ok {
 			profile := match.NormalizeDomain(value)
@@ -986,11 +2406,17 @@ func parseDomainCSV(path string) (*csvParseResult, error) {
 			uniqueMap[key] = domainModel
 			order = append(order, key)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	uniqueModels := make([]*store.Domain, 0, len(order))
 	uniqueDomains := make([]string, 0, len(order))
 	uniqueNormalized := make([]string, 0, len(order))
+	var duplicateGroups []DuplicateGroup
+	duplicateTruncated := false
 	for _, key := range order {
 		model := uniqueMap[key]
 		if model == nil {
@@ -999,6 +2425,13 @@ func parseDomainCSV(path string) (*csvParseResult, error) {
 		uniqueModels = append(uniqueModels, model)
 		uniqueDomains = append(uniqueDomains, model.Domain)
 		uniqueNormalized = append(uniqueNormalized, key)
+		if group := variants[key]; group != nil && group.Count > 1 {
+			if len(duplicateGroups) < maxDuplicateGroups {
+				duplicateGroups = append(duplicateGroups, *group)
+			} else {
+				duplicateTruncated = true
+			}
+		}
 	}
 
 	duplicates := rowIndex - len(uniqueModels)
@@ -1007,15 +2440,39 @@ func parseDomainCSV(path string) (*csvParseResult, error) {
 	}
 
 	return &csvParseResult{
-		domainModels:     uniqueModels,
-		domainBatches:    batches,
-		uniqueDomains:    uniqueDomains,
-		uniqueNormalized: uniqueNormalized,
-		rowCount:         rowIndex,
-		duplicateRows:    duplicates,
+		domainModels:       uniqueModels,
+		domainBatches:      batches,
+		uniqueDomains:      uniqueDomains,
+		uniqueNormalized:   uniqueNormalized,
+		rowCount:           rowIndex,
+		duplicateRows:      duplicates,
+		duplicateGroups:    duplicateGroups,
+		duplicateTruncated: duplicateTruncated,
+		domainColumn:       detectedCol,
+		domainHeader:       domainHeader,
+		skippedRows:        skipped,
 	}, nil
 }
 
+// defaultCSVComment starts comment lines in domain CSVs unless Config.CSVComment says otherwise.
+const defaultCSVComment = '#'
+
+// parseCSVComment resolves Config.CSVComment to the rune handed to csv.Reader.
+func parseCSVComment(value string) (rune, error) {
+	value = strings.TrimSpace(value)
+	switch {
+	case value == "":
+		return defaultCSVComment, nil
+	case strings.EqualFold(value, "none"):
+		return 0, nil
+	}
+	r, size := utf8.DecodeRuneInString(value)
+	if size != len(value) || r == ',' || r == '"' || r == utf8.RuneError || unicode.IsSpace(r) {
+		return 0, fmt.Errorf("csv comment %q must be a single character other than a comma, quote, or space", value)
+	}
+	return r, nil
+}
+
 func detectDomainColumn(record []string) int {
 	for idx, value := range record {
 		normalized := strings.ToLower(strings.TrimSpace(value))
@@ -1031,7 +2488,9 @@ func (s *Server) loadCommercialSales(path string) error {
 	if s.commercial == nil {
 		s.commercial = commercial.NewService(s.db)
 	}
-	count, err := s.commercial.LoadFromCSV(path, commercialMinPrice)
+	// Every sale is loaded so the closest comparable is found whatever its price; the policy's
+	// min_price then decides whether that comparable may override.
+	count, err := s.commercial.LoadFromCSV(path, 0)
 	if err != nil {
 		return err
 	}
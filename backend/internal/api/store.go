@@ -0,0 +1,74 @@
+package api
+
+import (
+	"time"
+
+	"domain-risk-eval/backend/internal/commercial"
+	"domain-risk-eval/backend/internal/scoring"
+	"domain-risk-eval/backend/internal/store"
+	xmlparser "domain-risk-eval/backend/internal/xml"
+)
+
+// Store is the persistence the handlers use. *store.Database implements it; tests can substitute
+// a fake to exercise handlers without SQLite.
+type Store interface {
+	Close() error
+
+	// Batches and their domains.
+	CreateCSVBatch(name, owner, filename string) (*store.CSVBatch, error)
+	GetCSVBatch(batchID uint) (*store.CSVBatch, error)
+	ListCSVBatches(offset, limit int) ([]store.CSVBatch, int64, error)
+	UpdateCSVBatchStats(batchID uint, rowCount, uniqueDomains, existingDomains, duplicateRows, processed int) error
+	UpdateBatchProcessingInfo(batchID uint) error
+	ReplaceDomainBatch(batchID uint, rows []store.DomainBatch) error
+	AppendDomainBatch(batchID uint, rows []store.DomainBatch) error
+	NewBatchDomainKeys(batchID uint, keys []string) (map[string]struct{}, error)
+	CountBatchDomains(batchID uint) (int, error)
+	CountBatchRows(batchID uint) (int, error)
+	ListBatchDomainsForEval(batchID uint, offset, limit int) ([]store.BatchDomain, error)
+	SaveDomain(domain *store.Domain) error
+	ListDomains(offset, limit int) ([]store.Domain, int64, error)
+	ExistingDomainKeys(domains []string) (map[string]struct{}, error)
+	FindDomainsByPattern(glob string, limit int) ([]string, int64, error)
+	SaveUploadKey(key, bodyHash string, batchID uint, merged bool, expiredBefore time.Time) error
+	FindUploadKey(key string, since time.Time) (*store.UploadKey, error)
+
+	// Evaluations.
+	SaveEvaluation(e *store.Evaluation) error
+	ListEvaluations(opts store.EvaluationQuery) ([]store.Evaluation, int64, error)
+	CountEvaluations(opts store.EvaluationQuery) (int64, error)
+	EachEvaluation(opts store.EvaluationQuery, fn func(store.Evaluation) error) error
+	EvaluationsByDomain(keys []string) ([]store.Evaluation, error)
+	EvaluatedDomainsForBatch(batchID uint) ([]string, error)
+	ExistingEvaluationKeys(domains []string) (map[string]struct{}, error)
+	CountBatchResults(batchID uint) (int, error)
+	CountBatchPriorResults(batchID uint, since time.Time) (int, error)
+	BatchesSharingDomains(batchID uint) ([]uint, error)
+	ClearBatchEvaluations(batchID uint) (int64, error)
+	SaveSkippedDomain(row *store.SkippedDomain) error
+	ListSkippedDomains(batchID uint, offset, limit int) ([]store.SkippedDomain, int64, error)
+
+	// Evaluation runs.
+	CreateBatchRequest(request *store.BatchRequest) error
+	GetBatchRequest(requestID uint) (*store.BatchRequest, error)
+	LatestBatchRequest(batchID uint) (*store.BatchRequest, error)
+	ListBatchRequests(batchID uint, opts store.BatchRequestQuery) ([]store.BatchRequest, int64, error)
+	UpdateBatchRequest(requestID uint, status string) error
+	SaveBatchRequestSummary(requestID uint, summary store.RunSummary) error
+
+	// Marks.
+	ListMarks(opts store.MarkQuery) ([]store.Mark, int64, error)
+	GetMark(serial string) (*store.Mark, error)
+	MarksByOwner(owner string, limit int) ([]store.Mark, error)
+	CountMarks() (int64, error)
+	MarksVersion() (store.MarksVersion, error)
+	MarksReadOnly() bool
+
+	// Mark ingest, popular-token aggregation, index loading, and the commercial service.
+	xmlparser.MarkWriter
+	scoring.PopularStore
+	scoring.MarkSource
+	commercial.SalesStore
+}
+
+var _ Store = (*store.Database)(nil)
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"domain-risk-eval/backend/internal/store"
+)
+
+// fakeStore serves batches and skipped rows from memory. Methods it does not override panic via
+// the nil embedded Store, which flags a handler reaching for data a test did not set up.
+type fakeStore struct {
+	Store
+	batches map[uint]store.CSVBatch
+	skipped map[uint][]store.SkippedDomain
+	// batchErr, when set, fails every batch lookup as a broken database would.
+	batchErr error
+}
+
+func (f *fakeStore) GetCSVBatch(batchID uint) (*store.CSVBatch, error) {
+	if f.batchErr != nil {
+		return nil, f.batchErr
+	}
+	batch, ok := f.batches[batchID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &batch, nil
+}
+
+func (f *fakeStore) ListSkippedDomains(batchID uint, offset, limit int) ([]store.SkippedDomain, int64, error) {
+	rows := f.skipped[batchID]
+	total := int64(len(rows))
+	rows = rows[min(offset, len(rows)):]
+	return rows[:min(limit, len(rows))], total, nil
+}
+
+func TestHandleSkippedDomains(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := &fakeStore{
+		batches: map[uint]store.CSVBatch{7: {ID: 7}},
+		skipped: map[uint][]store.SkippedDomain{7: {
+			{BatchID: 7, RowIndex: 2, Domain: "", Reason: "blank domain"},
+			{BatchID: 7, RowIndex: 5, Domain: "---.com", Reason: "label has no letters or digits"},
+			{BatchID: 7, RowIndex: 9, Domain: "a b.com", Reason: "host contains whitespace"},
+		}},
+	}
+	s := &Server{db: db, resultsPage: defaultResultsPage}
+
+	tests := []struct {
+		name      string
+		id        string
+		query     string
+		status    int
+		items     int
+		hasNext   bool
+		firstHost string
+	}{
+		{"invalid id", "abc", "", http.StatusBadRequest, 0, false, ""},
+		{"unknown batch", "8", "", http.StatusNotFound, 0, false, ""},
+		{"all rows", "7", "", http.StatusOK, 3, false, ""},
+		{"second page", "7", "?page=1&pageSize=2", http.StatusOK, 1, false, "a b.com"},
+		{"first page", "7", "?pageSize=2", http.StatusOK, 2, true, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/batches/"+tc.id+"/skipped"+tc.query, nil)
+			c.Params = gin.Params{{Key: "id", Value: tc.id}}
+
+			s.handleSkippedDomains(c)
+
+			if w.Code != tc.status {
+				t.Fatalf("expected status %d got %d: %s", tc.status, w.Code, w.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+			var resp SkippedDomainsResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if len(resp.Items) != tc.items || resp.HasNext != tc.hasNext || resp.Total != 3 {
+				t.Fatalf("unexpected page %+v", resp)
+			}
+			if tc.firstHost != "" && resp.Items[0].Domain != tc.firstHost {
+				t.Fatalf("expected first domain %q got %q", tc.firstHost, resp.Items[0].Domain)
+			}
+		})
+	}
+}
+
+func TestHandleEvaluateBatchLookupErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name   string
+		db     *fakeStore
+		status int
+	}{
+		{"unknown batch", &fakeStore{}, http.StatusNotFound},
+		{"database error", &fakeStore{batchErr: errors.New("database is locked")}, http.StatusInternalServerError},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &Server{db: tc.db}
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/evaluate", strings.NewReader(`{"batch_id": 3}`))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			s.handleEvaluate(c)
+
+			if w.Code != tc.status {
+				t.Fatalf("expected status %d got %d: %s", tc.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleEvaluateValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		fields string
+	}{
+		{"empty body", "", http.StatusUnprocessableEntity, "batch_id"},
+		{"negative paging", `{"batch_id": 3, "limit": -1, "offset": -2}`, http.StatusUnprocessableEntity, "limit,offset"},
+		{"wrong type", `{"batch_id": "three"}`, http.StatusUnprocessableEntity, "batch_id"},
+		{"malformed json", `{"batch_id": `, http.StatusBadRequest, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// A request that passes validation would reach the nil embedded Store and panic.
+			s := &Server{db: &fakeStore{}}
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/evaluate", strings.NewReader(tc.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			s.handleEvaluate(c)
+
+			if w.Code != tc.status {
+				t.Fatalf("expected status %d got %d: %s", tc.status, w.Code, w.Body.String())
+			}
+			if tc.status != http.StatusUnprocessableEntity {
+				return
+			}
+			var resp ValidationErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			names := make([]string, 0, len(resp.Fields))
+			for _, field := range resp.Fields {
+				names = append(names, field.Field)
+			}
+			if got := strings.Join(names, ","); got != tc.fields {
+				t.Fatalf("expected invalid fields %q got %q: %s", tc.fields, got, resp.Error)
+			}
+		})
+	}
+}
@@ -5,20 +5,23 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+
+	"domain-risk-eval/backend/internal/store"
 )
 
 // EvaluationEvent describes websocket payloads emitted during evaluation runs.
 type EvaluationEvent struct {
-	Type       string          `json:"type"`
-	JobID      string          `json:"job_id"`
-	BatchID    uint            `json:"batch_id"`
-	Total      int64           `json:"total,omitempty"`
-	Processed  int             `json:"processed,omitempty"`
-	Evaluation *EvaluationDTO  `json:"evaluation,omitempty"`
-	Batch      []EvaluationDTO `json:"batch,omitempty"`
-	Message    string          `json:"message,omitempty"`
-	Reused     bool            `json:"reused,omitempty"`
-	Timestamp  time.Time       `json:"timestamp"`
+	Type       string            `json:"type"`
+	JobID      string            `json:"job_id"`
+	BatchID    uint              `json:"batch_id"`
+	Total      int64             `json:"total,omitempty"`
+	Processed  int               `json:"processed,omitempty"`
+	Evaluation *EvaluationDTO    `json:"evaluation,omitempty"`
+	Batch      []EvaluationDTO   `json:"batch,omitempty"`
+	Message    string            `json:"message,omitempty"`
+	Reused     bool              `json:"reused,omitempty"`
+	Summary    *store.RunSummary `json:"summary,omitempty"`
+	Timestamp  time.Time         `json:"timestamp"`
 }
 
 // wsClient wraps a websocket connection with write locking.
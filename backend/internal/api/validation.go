@@ -0,0 +1,119 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes one invalid request field, named as the client sent it.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrorResponse is the 422 body for requests that parse but fail validation.
+type ValidationErrorResponse struct {
+	Error  string       `json:"error"`
+	Fields []FieldError `json:"fields"`
+}
+
+// renderValidation writes a 422 listing every invalid field; the error string joins them so
+// clients that only read "error" still see what is wrong.
+func (s *Server) renderValidation(c *gin.Context, fields []FieldError) {
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		parts = append(parts, field.Field+" "+field.Message)
+	}
+	c.JSON(http.StatusUnprocessableEntity, ValidationErrorResponse{
+		Error:  "validation failed: " + strings.Join(parts, "; "),
+		Fields: fields,
+	})
+}
+
+// renderBindingError reports a bind or validation error on obj: field-level problems render 422
+// via renderValidation, anything else (e.g. malformed JSON) a plain 400.
+func (s *Server) renderBindingError(c *gin.Context, obj any, err error) {
+	if fields := fieldErrors(obj, err); len(fields) > 0 {
+		s.renderValidation(c, fields)
+		return
+	}
+	s.renderError(c, http.StatusBadRequest, err)
+}
+
+// fieldErrors converts validator and JSON type errors into FieldErrors, naming fields by their
+// json or form tag on obj. Other errors yield nil.
+func fieldErrors(obj any, err error) []FieldError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []FieldError{{Field: typeErr.Field, Message: "must be " + jsonKindName(typeErr.Type.Kind())}}
+	}
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		return nil
+	}
+	fields := make([]FieldError, 0, len(invalid))
+	for _, fe := range invalid {
+		fields = append(fields, FieldError{Field: requestFieldName(obj, fe.StructField()), Message: validationMessage(obj, fe)})
+	}
+	return fields
+}
+
+// requestFieldName returns the json or form tag name of a struct field, falling back to the Go name.
+func requestFieldName(obj any, structField string) string {
+	t := reflect.TypeOf(obj)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return structField
+	}
+	field, ok := t.FieldByName(structField)
+	if !ok {
+		return structField
+	}
+	for _, tag := range []string{"json", "form"} {
+		if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return structField
+}
+
+func jsonKindName(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a non-negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	default:
+		return "a " + kind.String()
+	}
+}
+
+func validationMessage(obj any, fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "required_without":
+		return "is required unless " + requestFieldName(obj, fe.Param()) + " is set"
+	case "gte", "min":
+		return "must be at least " + fe.Param()
+	case "lte", "max":
+		return "must be at most " + fe.Param()
+	default:
+		return fmt.Sprintf("failed the %s check", fe.Tag())
+	}
+}
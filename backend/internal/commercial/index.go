@@ -14,17 +14,41 @@ import (
 	"domain-risk-eval/backend/internal/store"
 )
 
+// Search bounds used by BestMatch unless SetSearchLimits overrides them.
+const (
+	// DefaultCandidateLimit caps the sales rows fetched per prefix pass.
+	DefaultCandidateLimit = 75
+	// DefaultLengthWindow is how many characters a sale may differ in length from the SLD.
+	DefaultLengthWindow = 2
+	// DefaultEarlyExitSimilarity is the similarity at which BestMatch stops widening its prefix
+	// search.
+	DefaultEarlyExitSimilarity = 0.95
+)
+
 type Match struct {
-	SLD        string
-	Price      float64
-	Similarity float64
+	SLD        string  `json:"sld"`
+	Price      float64 `json:"price"`
+	Similarity float64 `json:"similarity"`
+}
+
+// SalesStore persists commercial sales and finds candidates for a lookup; *store.Database
+// implements it.
+type SalesStore interface {
+	ReplaceCommercialSales(sales []store.CommercialSale) error
+	CountCommercialSales() (int64, error)
+	FindCommercialCandidates(prefixes []string, minLen, maxLen, targetLen, limit int) ([]store.CommercialSale, error)
 }
 
 // Service manages commercial sales persistence and lookup.
 type Service struct {
-	db      *store.Database
-	cache   map[string]cacheEntry
-	cacheMu sync.RWMutex
+	db         SalesStore
+	cache      map[string]cacheEntry
+	cacheMu    sync.RWMutex
+	algorithm  Algorithm
+	similarity SimilarityFunc
+	limit      int
+	window     int
+	earlyExit  float64
 }
 
 type cacheEntry struct {
@@ -32,13 +56,92 @@ type cacheEntry struct {
 	found bool
 }
 
-func NewService(db *store.Database) *Service {
+func NewService(db SalesStore) *Service {
 	return &Service{
-		db:    db,
-		cache: make(map[string]cacheEntry),
+		db:         db,
+		cache:      make(map[string]cacheEntry),
+		algorithm:  AlgorithmLevenshtein,
+		similarity: AlgorithmLevenshtein.Func(),
+		limit:      DefaultCandidateLimit,
+		window:     DefaultLengthWindow,
+		earlyExit:  DefaultEarlyExitSimilarity,
 	}
 }
 
+// SetAlgorithm switches the similarity used by BestMatch and drops cached matches scored with
+// the previous one.
+func (s *Service) SetAlgorithm(alg Algorithm) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	s.algorithm = alg
+	s.similarity = alg.Func()
+	s.cache = make(map[string]cacheEntry)
+}
+
+// Algorithm reports the similarity algorithm in use.
+func (s *Service) Algorithm() Algorithm {
+	if s == nil {
+		return ""
+	}
+	s.cacheMu.RLock()
+	defer s.cacheMu.RUnlock()
+	return s.algorithm
+}
+
+// SetSearchLimits tunes how widely BestMatch searches: candidateLimit caps the rows fetched per
+// prefix pass and lengthWindow is how many characters a sale may differ in length from the SLD.
+// Larger values raise recall at the cost of more similarity comparisons. A lengthWindow of zero
+// only compares sales of the SLD's exact length. A non-positive candidateLimit or negative
+// lengthWindow keeps the default, and cached matches found with the previous limits are dropped.
+func (s *Service) SetSearchLimits(candidateLimit, lengthWindow int) {
+	if candidateLimit <= 0 {
+		candidateLimit = DefaultCandidateLimit
+	}
+	if lengthWindow < 0 {
+		lengthWindow = DefaultLengthWindow
+	}
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	s.limit = candidateLimit
+	s.window = lengthWindow
+	s.cache = make(map[string]cacheEntry)
+}
+
+// SearchLimits reports the candidate limit and length window used by BestMatch.
+func (s *Service) SearchLimits() (candidateLimit, lengthWindow int) {
+	if s == nil {
+		return 0, 0
+	}
+	s.cacheMu.RLock()
+	defer s.cacheMu.RUnlock()
+	return s.limit, s.window
+}
+
+// SetEarlyExitSimilarity sets the similarity at which BestMatch stops searching wider prefix
+// tiers. Lower values answer sooner on large inventories but may miss a closer sale in a later
+// tier; 1 stops only on an exact match. It is independent of the policy's override threshold.
+// Non-positive values keep the default, and cached matches found with the previous threshold are
+// dropped.
+func (s *Service) SetEarlyExitSimilarity(threshold float64) {
+	if threshold <= 0 {
+		threshold = DefaultEarlyExitSimilarity
+	}
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	s.earlyExit = threshold
+	s.cache = make(map[string]cacheEntry)
+}
+
+// EarlyExitSimilarity reports the similarity at which BestMatch stops searching.
+func (s *Service) EarlyExitSimilarity() float64 {
+	if s == nil {
+		return 0
+	}
+	s.cacheMu.RLock()
+	defer s.cacheMu.RUnlock()
+	return s.earlyExit
+}
+
 // LoadFromCSV ingests the provided CSV and replaces the stored sales inventory.
 func (s *Service) LoadFromCSV(path string, minPrice float64) (int, error) {
 	path = strings.TrimSpace(path)
@@ -135,12 +238,24 @@ func (s *Service) BestMatch(sld string) (Match, bool) {
 		return cachedMatch.match, cachedMatch.found
 	}
 
+	best, found := s.search(normalized)
+	s.storeCache(normalized, cacheEntry{match: best, found: found})
+	return best, found
+}
+
+// search scans the sales inventory for normalized without consulting the cache, narrowing from a
+// three-character prefix down to no prefix until a match reaches the early-exit similarity.
+func (s *Service) search(normalized string) (Match, bool) {
+	s.cacheMu.RLock()
+	similarity, limit, window, earlyExit := s.similarity, s.limit, s.window, s.earlyExit
+	s.cacheMu.RUnlock()
+
 	targetLen := runeLen(normalized)
-	minLen := targetLen - 2
+	minLen := targetLen - window
 	if minLen < 1 {
 		minLen = 1
 	}
-	maxLen := targetLen + 2
+	maxLen := targetLen + window
 
 	prefix3 := prefix(normalized, 3)
 	prefix2 := prefix(normalized, 2)
@@ -157,7 +272,7 @@ func (s *Service) BestMatch(sld string) (Match, bool) {
 	var found bool
 
 	for _, prefixes := range searchPrefixes {
-		candidates, err := s.db.FindCommercialCandidates(prefixes, minLen, maxLen, targetLen, 75)
+		candidates, err := s.db.FindCommercialCandidates(prefixes, minLen, maxLen, targetLen, limit)
 		if err != nil {
 			continue
 		}
@@ -168,12 +283,11 @@ func (s *Service) BestMatch(sld string) (Match, bool) {
 				found = true
 			}
 		}
-		if found && best.Similarity >= 0.95 {
+		if found && best.Similarity >= earlyExit {
 			break
 		}
 	}
 
-	s.storeCache(normalized, cacheEntry{match: best, found: found})
 	if !found {
 		return Match{}, false
 	}
@@ -236,7 +350,9 @@ func runeLen(value string) int {
 	return len([]rune(value))
 }
 
-func similarity(a, b string) float64 {
+// LevenshteinSimilarity scores a against b between 0 and 1 as one minus their edit distance over
+// the longer length.
+func LevenshteinSimilarity(a, b string) float64 {
 	aRunes := []rune(a)
 	bRunes := []rune(b)
 	if len(aRunes) == 0 && len(bRunes) == 0 {
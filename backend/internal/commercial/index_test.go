@@ -0,0 +1,103 @@
+package commercial
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"domain-risk-eval/backend/internal/store"
+)
+
+// benchSales builds a sales inventory large enough that the candidate limit and length window
+// decide which rows BestMatch gets to compare.
+func benchSales() []store.CommercialSale {
+	var sales []store.CommercialSale
+	for i, head := range sampleInventory {
+		for j, tail := range []string{"", "hub", "ly", "go", "now", "shop", "online", "central"} {
+			name := head + tail
+			sales = append(sales, store.CommercialSale{
+				SLD:        name,
+				Normalized: name,
+				Prefix:     prefix(name, 3),
+				Length:     runeLen(name),
+				Price:      float64(10000 + (i*7+j*13)%500*100),
+			})
+		}
+	}
+	return sales
+}
+
+// BenchmarkBestMatch reports the cost and the average best similarity found (avg-sim) for a few
+// search limits, showing how much recall a wider search buys.
+func BenchmarkBestMatch(b *testing.B) {
+	db, err := store.Open(filepath.Join(b.TempDir(), "bench.sqlite"), true, store.Config{})
+	if err != nil {
+		b.Fatalf("open store: %v", err)
+	}
+	if err := db.ReplaceCommercialSales(benchSales()); err != nil {
+		b.Fatalf("load sales: %v", err)
+	}
+	targets := append([]string{"zshopify", "thecloudlabs", "mysmarthomeonline"}, sampleTargets...)
+
+	settings := []struct{ limit, window int }{
+		{25, 1},
+		{DefaultCandidateLimit, DefaultLengthWindow},
+		{200, 4},
+	}
+	for _, setting := range settings {
+		svc := NewService(db)
+		svc.SetSearchLimits(setting.limit, setting.window)
+		b.Run(fmt.Sprintf("limit=%d/window=%d", setting.limit, setting.window), func(b *testing.B) {
+			total := 0.0
+			for i := 0; i < b.N; i++ {
+				match, _ := svc.search(targets[i%len(targets)])
+				total += match.Similarity
+			}
+			benchSink = total
+			b.ReportMetric(total/float64(b.N), "avg-sim")
+		})
+	}
+}
+
+func TestSetSearchLimitsDefaults(t *testing.T) {
+	svc := NewService(nil)
+	if limit, window := svc.SearchLimits(); limit != DefaultCandidateLimit || window != DefaultLengthWindow {
+		t.Fatalf("expected defaults, got %d/%d", limit, window)
+	}
+	svc.SetSearchLimits(150, -1)
+	if limit, window := svc.SearchLimits(); limit != 150 || window != DefaultLengthWindow {
+		t.Fatalf("expected 150/%d, got %d/%d", DefaultLengthWindow, limit, window)
+	}
+	svc.SetSearchLimits(0, 0)
+	if limit, window := svc.SearchLimits(); limit != DefaultCandidateLimit || window != 0 {
+		t.Fatalf("expected %d/0 for an exact-length window, got %d/%d", DefaultCandidateLimit, limit, window)
+	}
+}
+
+func TestSearchEarlyExit(t *testing.T) {
+	db, err := store.Open(filepath.Join(t.TempDir(), "sales.sqlite"), true, store.Config{})
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	// "clouds" shares the target's prefix and is found in the first tier; the closer "kcloudly"
+	// only turns up in the final, prefix-less tier.
+	var sales []store.CommercialSale
+	for _, name := range []string{"clouds", "kcloudly"} {
+		sales = append(sales, store.CommercialSale{SLD: name, Normalized: name, Prefix: prefix(name, 3), Length: runeLen(name), Price: 20000})
+	}
+	if err := db.ReplaceCommercialSales(sales); err != nil {
+		t.Fatalf("load sales: %v", err)
+	}
+
+	svc := NewService(db)
+	if got := svc.EarlyExitSimilarity(); got != DefaultEarlyExitSimilarity {
+		t.Fatalf("expected default early exit, got %v", got)
+	}
+	if match, _ := svc.search("cloudly"); match.SLD != "kcloudly" {
+		t.Fatalf("expected the default threshold to search every tier, got %+v", match)
+	}
+	svc.SetEarlyExitSimilarity(0.7)
+	if match, _ := svc.search("cloudly"); match.SLD != "clouds" {
+		t.Fatalf("expected the first tier's match at 0.7, got %+v", match)
+	}
+}
@@ -0,0 +1,161 @@
+package commercial
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Algorithm names a string similarity used to compare an SLD against the sales inventory.
+type Algorithm string
+
+const (
+	// AlgorithmLevenshtein is normalized edit distance; the default.
+	AlgorithmLevenshtein Algorithm = "levenshtein"
+	// AlgorithmJaroWinkler rewards a shared prefix, which suits domains that extend a common stem.
+	AlgorithmJaroWinkler Algorithm = "jaro_winkler"
+	// AlgorithmBigram is token-based: the Dice coefficient over character bigrams, so names built
+	// from the same pieces in a different order still score well.
+	AlgorithmBigram Algorithm = "bigram"
+)
+
+// Algorithms lists the supported similarity algorithms.
+var Algorithms = []Algorithm{AlgorithmLevenshtein, AlgorithmJaroWinkler, AlgorithmBigram}
+
+// SimilarityFunc scores two normalized SLDs between 0 (unrelated) and 1 (identical).
+type SimilarityFunc func(a, b string) float64
+
+// ParseAlgorithm resolves a configured algorithm name; empty selects AlgorithmLevenshtein.
+func ParseAlgorithm(value string) (Algorithm, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	normalized = strings.ReplaceAll(normalized, "-", "_")
+	if normalized == "" {
+		return AlgorithmLevenshtein, nil
+	}
+	for _, alg := range Algorithms {
+		if normalized == string(alg) {
+			return alg, nil
+		}
+	}
+	return "", fmt.Errorf("unknown similarity algorithm %q", value)
+}
+
+// Func returns the similarity function for the algorithm, falling back to Levenshtein.
+func (a Algorithm) Func() SimilarityFunc {
+	switch a {
+	case AlgorithmJaroWinkler:
+		return jaroWinklerSimilarity
+	case AlgorithmBigram:
+		return bigramSimilarity
+	default:
+		return LevenshteinSimilarity
+	}
+}
+
+// jaroWinklerSimilarity is the Jaro similarity boosted by up to four shared leading runes once it
+// exceeds 0.7, the usual Winkler threshold.
+func jaroWinklerSimilarity(a, b string) float64 {
+	aRunes := []rune(a)
+	bRunes := []rune(b)
+	if len(aRunes) == 0 && len(bRunes) == 0 {
+		return 1
+	}
+	if len(aRunes) == 0 || len(bRunes) == 0 {
+		return 0
+	}
+
+	window := maxInt(len(aRunes), len(bRunes))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+	aMatched := make([]bool, len(aRunes))
+	bMatched := make([]bool, len(bRunes))
+	matches := 0
+	for i, r := range aRunes {
+		lo := maxInt(0, i-window)
+		hi := minInt(len(bRunes)-1, i+window)
+		for j := lo; j <= hi; j++ {
+			if bMatched[j] || bRunes[j] != r {
+				continue
+			}
+			aMatched[i] = true
+			bMatched[j] = true
+			matches++
+			break
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i, r := range aRunes {
+		if !aMatched[i] {
+			continue
+		}
+		for !bMatched[j] {
+			j++
+		}
+		if r != bRunes[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(aRunes)) + m/float64(len(bRunes)) + (m-float64(transpositions)/2)/m) / 3
+	if jaro <= 0.7 {
+		return jaro
+	}
+
+	prefixLen := 0
+	for prefixLen < 4 && prefixLen < len(aRunes) && prefixLen < len(bRunes) && aRunes[prefixLen] == bRunes[prefixLen] {
+		prefixLen++
+	}
+	return jaro + float64(prefixLen)*0.1*(1-jaro)
+}
+
+// bigramSimilarity is the Dice coefficient over the multisets of adjacent rune pairs. Single-rune
+// inputs have no bigrams and only match themselves.
+func bigramSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	aGrams := bigrams(a)
+	bGrams := bigrams(b)
+	if len(aGrams) == 0 || len(bGrams) == 0 {
+		return 0
+	}
+
+	counts := make(map[[2]rune]int, len(aGrams))
+	for _, gram := range aGrams {
+		counts[gram]++
+	}
+	shared := 0
+	for _, gram := range bGrams {
+		if counts[gram] > 0 {
+			counts[gram]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(aGrams)+len(bGrams))
+}
+
+func bigrams(value string) [][2]rune {
+	runes := []rune(value)
+	if len(runes) < 2 {
+		return nil
+	}
+	grams := make([][2]rune, 0, len(runes)-1)
+	for i := 0; i+1 < len(runes); i++ {
+		grams = append(grams, [2]rune{runes[i], runes[i+1]})
+	}
+	return grams
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
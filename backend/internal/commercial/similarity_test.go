@@ -0,0 +1,95 @@
+package commercial
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSimilarityAlgorithms(t *testing.T) {
+	tests := []struct {
+		a, b string
+	}{
+		{"cars", "cars"},
+		{"bestcars", "carsbest"},
+		{"shop", "shopping"},
+		{"a", "b"},
+		{"", "domain"},
+	}
+
+	for _, alg := range Algorithms {
+		sim := alg.Func()
+		for _, tc := range tests {
+			t.Run(fmt.Sprintf("%s/%s-%s", alg, tc.a, tc.b), func(t *testing.T) {
+				got := sim(tc.a, tc.b)
+				if got < 0 || got > 1 {
+					t.Fatalf("similarity %f outside 0-1", got)
+				}
+				if tc.a == tc.b && got != 1 {
+					t.Fatalf("identical inputs scored %f", got)
+				}
+				if reverse := sim(tc.b, tc.a); reverse != got {
+					t.Fatalf("asymmetric: %f vs %f", got, reverse)
+				}
+			})
+		}
+	}
+}
+
+func TestSimilarityAlgorithmCharacter(t *testing.T) {
+	// Jaro-Winkler favours a shared stem and bigrams tolerate reordered words, relative to
+	// edit distance.
+	if jw, lev := jaroWinklerSimilarity("shop", "shopping"), LevenshteinSimilarity("shop", "shopping"); jw <= lev {
+		t.Fatalf("expected jaro-winkler %f above levenshtein %f for a shared prefix", jw, lev)
+	}
+	if bi, lev := bigramSimilarity("bestcars", "carsbest"), LevenshteinSimilarity("bestcars", "carsbest"); bi <= lev {
+		t.Fatalf("expected bigram %f above levenshtein %f for reordered words", bi, lev)
+	}
+}
+
+func TestParseAlgorithm(t *testing.T) {
+	if alg, err := ParseAlgorithm(""); err != nil || alg != AlgorithmLevenshtein {
+		t.Fatalf("expected default levenshtein, got %q %v", alg, err)
+	}
+	if alg, err := ParseAlgorithm(" Jaro-Winkler "); err != nil || alg != AlgorithmJaroWinkler {
+		t.Fatalf("expected jaro_winkler, got %q %v", alg, err)
+	}
+	if _, err := ParseAlgorithm("cosine"); err == nil {
+		t.Fatal("expected error for unknown algorithm")
+	}
+}
+
+// sampleInventory mimics the sales CSV: short brandable SLDs sharing common stems.
+var sampleInventory = func() []string {
+	stems := []string{"shop", "cars", "best", "cloud", "health", "travel", "pay", "home", "smart", "crypto"}
+	suffixes := []string{"", "hub", "ly", "ify", "zone", "pro", "online", "world", "now", "labs"}
+	var names []string
+	for _, stem := range stems {
+		for _, suffix := range suffixes {
+			names = append(names, stem+suffix, suffix+stem)
+		}
+	}
+	return names
+}()
+
+var sampleTargets = []string{"shopify", "bestcar", "cloudlab", "healthzone", "paynow", "smarthomes", "cryptoworld", "travelly"}
+
+var benchSink float64
+
+func BenchmarkSimilarity(b *testing.B) {
+	for _, alg := range Algorithms {
+		sim := alg.Func()
+		b.Run(string(alg), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				target := sampleTargets[i%len(sampleTargets)]
+				best := 0.0
+				for _, candidate := range sampleInventory {
+					if score := sim(target, candidate); score > best {
+						best = score
+					}
+				}
+				benchSink = best
+			}
+		})
+	}
+}
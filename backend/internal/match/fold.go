@@ -0,0 +1,46 @@
+package match
+
+import (
+	"strings"
+	"sync/atomic"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// foldingEnabled turns on the NFKC and diacritic folding in Fold; until SetUnicodeFolding(true)
+// is called Fold only lowercases.
+var foldingEnabled atomic.Bool
+
+// SetUnicodeFolding enables or disables the NFKC and diacritic folding applied by Fold. It is
+// meant to be called once at startup, before domains or marks are normalized. Stored marks keep
+// the normalization they were ingested with and are not re-normalized when the setting changes,
+// so the ingester and the server must agree on it.
+func SetUnicodeFolding(enabled bool) {
+	foldingEnabled.Store(enabled)
+}
+
+// UnicodeFolding reports whether Fold applies NFKC and diacritic folding.
+func UnicodeFolding() bool {
+	return foldingEnabled.Load()
+}
+
+// Fold lowercases s and maps it to a canonical form so visually equivalent input compares equal:
+// compatibility characters such as full-width letters become their plain forms (NFKC) and
+// diacritics are removed, so "Café" and "ｃａｆｅ" both fold to "cafe". Domains, marks, and vice
+// terms all go through Fold so the trademark and vice scorers see the same text.
+func Fold(s string) string {
+	if !foldingEnabled.Load() {
+		return strings.ToLower(s)
+	}
+	decomposed := norm.NFKD.String(s)
+	var b strings.Builder
+	b.Grow(len(decomposed))
+	for _, r := range decomposed {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToLower(norm.NFC.String(b.String()))
+}
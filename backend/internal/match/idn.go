@@ -0,0 +1,30 @@
+package match
+
+import (
+	"strings"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+// punycodePrefix starts each ASCII-encoded (punycode) label of an internationalized domain.
+const punycodePrefix = "xn--"
+
+// DecodePunycode returns value with each punycode label decoded to Unicode and the result in NFC,
+// so the ASCII and Unicode spellings of an internationalized domain compare equal
+// ("xn--caf-dma.com" and "café.com"). Labels that fail to decode are kept as they are.
+func DecodePunycode(value string) string {
+	if !strings.Contains(strings.ToLower(value), punycodePrefix) {
+		return norm.NFC.String(value)
+	}
+	labels := strings.Split(value, ".")
+	for i, label := range labels {
+		if len(label) <= len(punycodePrefix) || !strings.EqualFold(label[:len(punycodePrefix)], punycodePrefix) {
+			continue
+		}
+		if decoded, err := idna.Punycode.ToUnicode(strings.ToLower(label)); err == nil {
+			labels[i] = decoded
+		}
+	}
+	return norm.NFC.String(strings.Join(labels, "."))
+}
@@ -3,6 +3,8 @@ package match
 import (
 	"regexp"
 	"strings"
+
+	"golang.org/x/net/publicsuffix"
 )
 
 var (
@@ -18,18 +20,26 @@ type DomainProfile struct {
 	BrandToken string
 	Tokens     []string
 	AltSplits  []string
+	// Registrable is the registrable domain the core label belongs to (e.g. "attacker.com").
+	Registrable string
+	// Subdomains holds every label left of Registrable, outermost first, e.g. ["login-paypal"]
+	// for "login-paypal.attacker.com".
+	Subdomains []string
+	// Path keeps the path, query, and fragment stripped from URL inputs.
+	Path string
 }
 
-// NormalizeDomain normalizes and tokenizes the supplied domain name.
+// NormalizeDomain normalizes and tokenizes the supplied domain name. Punycode labels are decoded
+// first, so an IDN scores the same in its ASCII and Unicode forms.
 func NormalizeDomain(input string) DomainProfile {
-	lower := strings.ToLower(strings.TrimSpace(input))
+	lower := Fold(DecodePunycode(strings.TrimSpace(input)))
 	lower = protocolStripper.ReplaceAllString(lower, "")
 
 	// Trim query, path, fragment
-	for _, sep := range []string{"/", "?", "#"} {
-		if idx := strings.Index(lower, sep); idx >= 0 {
-			lower = lower[:idx]
-		}
+	path := ""
+	if idx := strings.IndexAny(lower, "/?#"); idx >= 0 {
+		path = lower[idx:]
+		lower = lower[:idx]
 	}
 
 	// Drop credentials if present (user:pass@)
@@ -45,23 +55,25 @@ func NormalizeDomain(input string) DomainProfile {
 		host = host[:idx]
 	}
 
-	segments := strings.Split(host, ".")
-	segments = compactSegments(segments)
-	if len(segments) > 3 {
-		segments = segments[len(segments)-3:]
-	}
+	labels := compactSegments(strings.Split(host, "."))
 
+	// The core is the label left of the public suffix: "example" in both shop.example.de and
+	// shop.example.co.uk. Hosts the suffix list cannot split fall back to the last two labels.
 	core := host
-	if len(segments) == 1 {
-		core = segments[0]
-	} else if len(segments) >= 2 {
-		tld := segments[len(segments)-1]
-		second := segments[len(segments)-2]
-		if len(tld) == 2 && len(segments) >= 3 {
-			core = segments[len(segments)-3]
-		} else {
-			core = second
+	coreIdx := 0
+	if len(labels) == 1 {
+		core = labels[0]
+	} else if len(labels) >= 2 {
+		coreIdx = len(labels) - 2
+		if registrable, err := publicsuffix.EffectiveTLDPlusOne(strings.Join(labels, ".")); err == nil {
+			coreIdx = len(labels) - strings.Count(registrable, ".") - 1
 		}
+		core = labels[coreIdx]
+	}
+	registrable := strings.Join(labels[coreIdx:], ".")
+	var subdomains []string
+	if coreIdx > 0 {
+		subdomains = append(subdomains, labels[:coreIdx]...)
 	}
 
 	brandToken := nonAlphaNum.ReplaceAllString(core, "")
@@ -73,12 +85,15 @@ func NormalizeDomain(input string) DomainProfile {
 	alt := compoundSplits(brandToken)
 
 	return DomainProfile{
-		Original:   input,
-		Host:       host,
-		Core:       core,
-		BrandToken: brandToken,
-		Tokens:     tokens,
-		AltSplits:  alt,
+		Original:    input,
+		Host:        host,
+		Core:        core,
+		BrandToken:  brandToken,
+		Tokens:      tokens,
+		AltSplits:   alt,
+		Registrable: registrable,
+		Subdomains:  subdomains,
+		Path:        path,
 	}
 }
 
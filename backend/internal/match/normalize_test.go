@@ -0,0 +1,136 @@
+package match
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFold(t *testing.T) {
+	SetUnicodeFolding(true)
+	defer SetUnicodeFolding(false)
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"café", "cafe"},
+		{"CAFÉ", "cafe"},
+		{"café", "cafe"},
+		{"ｐａｙｐａｌ", "paypal"},
+		{"ＡＰＰＬＥ１２３", "apple123"},
+		{"naïve-crème", "naive-creme"},
+		{"казино", "казино"},
+	}
+	for _, tc := range tests {
+		if got := Fold(tc.in); got != tc.want {
+			t.Errorf("Fold(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestDecodePunycode(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"xn--caf-dma.com", "café.com"},
+		{"shop.XN--80ak6aa92e.com", "shop.аррӏе.com"},
+		{"cafe\u0301.com", "café.com"},
+		{"xn--.com", "xn--.com"},
+		{"xn--zz-!!.com", "xn--zz-!!.com"},
+		{"example.com", "example.com"},
+	}
+	for _, tc := range tests {
+		if got := DecodePunycode(tc.in); got != tc.want {
+			t.Errorf("DecodePunycode(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestNormalizeDomainFoldsUnicode(t *testing.T) {
+	SetUnicodeFolding(true)
+	defer SetUnicodeFolding(false)
+	tests := []struct {
+		domain string
+		host   string
+		brand  string
+		tokens []string
+	}{
+		{"café.com", "cafe.com", "cafe", []string{"cafe"}},
+		{"XN--caf-dma.com", "cafe.com", "cafe", []string{"cafe"}},
+		{"https://Crème-Brûlée.fr/menu", "creme-brulee.fr", "cremebrulee", []string{"creme", "brulee"}},
+		{"ｐａｙｐａｌ．ｃｏｍ", "paypal.com", "paypal", []string{"paypal"}},
+		{"ｌｏｇｉｎ-ｐａｙｐａｌ.example.com", "login-paypal.example.com", "example", []string{"example"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.domain, func(t *testing.T) {
+			profile := NormalizeDomain(tc.domain)
+			if profile.Host != tc.host {
+				t.Fatalf("host = %q, want %q", profile.Host, tc.host)
+			}
+			if profile.BrandToken != tc.brand {
+				t.Fatalf("brand token = %q, want %q", profile.BrandToken, tc.brand)
+			}
+			if strings.Join(profile.Tokens, ",") != strings.Join(tc.tokens, ",") {
+				t.Fatalf("tokens = %v, want %v", profile.Tokens, tc.tokens)
+			}
+		})
+	}
+}
+
+func TestNormalizeDomainWithoutFolding(t *testing.T) {
+	if got := NormalizeDomain("café.com").BrandToken; got != "caf" {
+		t.Fatalf("brand token = %q, want %q without folding", got, "caf")
+	}
+}
+
+func TestDetectSpamPattern(t *testing.T) {
+	tests := []struct {
+		in       string
+		hyphens  int
+		tokens   int
+		triggers string
+	}{
+		{"casino-bonus-free-777.com", 3, 4, "hyphens"},
+		{"win4cash2day.net", 0, 5, "tokens"},
+		{"88889999.com", 0, 1, "digits"},
+		{"best-buy-deals.com", 2, 3, ""},
+		{"3m.com", 0, 2, ""},
+		{"paypal.com", 0, 1, ""},
+	}
+	for _, tc := range tests {
+		got := DetectSpamPattern(NormalizeDomain(tc.in), DefaultSpamThresholds())
+		if got.Hyphens != tc.hyphens || got.Tokens != tc.tokens || strings.Join(got.Triggers, ",") != tc.triggers || got.Spammy != (tc.triggers != "") {
+			t.Errorf("DetectSpamPattern(%q) = %+v, want %d hyphens, %d tokens, triggers %q", tc.in, got, tc.hyphens, tc.tokens, tc.triggers)
+		}
+	}
+	if got := DetectSpamPattern(NormalizeDomain("casino-bonus-free-777.com"), SpamThresholds{}); got.Spammy {
+		t.Errorf("zero thresholds should disable every check, got %+v", got)
+	}
+	if err := (SpamThresholds{MinDigitRatio: 1.5}).Validate(); err == nil {
+		t.Error("expected a digit ratio above 1 to be rejected")
+	}
+}
+
+func TestNormalizeDomainRegistrable(t *testing.T) {
+	tests := []struct {
+		domain      string
+		core        string
+		registrable string
+		subdomains  []string
+	}{
+		{"login-paypal.attacker.io", "attacker", "attacker.io", []string{"login-paypal"}},
+		{"shop.example.de", "example", "example.de", []string{"shop"}},
+		{"example.de", "example", "example.de", nil},
+		{"login.paypal.co.uk", "paypal", "paypal.co.uk", []string{"login"}},
+		{"paypal.co.uk", "paypal", "paypal.co.uk", nil},
+		{"a.b.example.com", "example", "example.com", []string{"a", "b"}},
+		{"localhost", "localhost", "localhost", nil},
+	}
+	for _, tc := range tests {
+		got := NormalizeDomain(tc.domain)
+		if got.Core != tc.core || got.Registrable != tc.registrable || strings.Join(got.Subdomains, ",") != strings.Join(tc.subdomains, ",") {
+			t.Errorf("NormalizeDomain(%q) = core %q, registrable %q, subdomains %v; want %q, %q, %v",
+				tc.domain, got.Core, got.Registrable, got.Subdomains, tc.core, tc.registrable, tc.subdomains)
+		}
+	}
+}
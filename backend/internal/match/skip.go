@@ -0,0 +1,42 @@
+package match
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrSkippable marks a domain that cannot be scored, such as a blank row or a host that
+// normalizes to nothing. Evaluation records such domains as skipped and carries on; the wrapped
+// message is the reason.
+var ErrSkippable = errors.New("domain skipped")
+
+// CheckProfile reports, wrapping ErrSkippable, why a normalized domain cannot be scored, or nil
+// when it can.
+func CheckProfile(profile DomainProfile) error {
+	switch {
+	case strings.TrimSpace(profile.Original) == "":
+		return skip("blank domain")
+	case profile.Host == "":
+		return skip("host is empty after normalization")
+	case strings.IndexFunc(profile.Host, unicode.IsSpace) >= 0:
+		return skip("host contains whitespace")
+	case strings.IndexFunc(profile.Core, isAlphaNum) < 0:
+		return skip("label has no letters or digits")
+	}
+	return nil
+}
+
+func skip(reason string) error {
+	return fmt.Errorf("%w: %s", ErrSkippable, reason)
+}
+
+func isAlphaNum(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// SkipReason returns the reason carried by an ErrSkippable error, without the sentinel prefix.
+func SkipReason(err error) string {
+	return strings.TrimPrefix(err.Error(), ErrSkippable.Error()+": ")
+}
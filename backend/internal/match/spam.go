@@ -0,0 +1,104 @@
+package match
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// spamMinDigitLength skips the digit-ratio check on short labels such as "3m" or "7eleven",
+// where a digit or two is part of the name rather than padding.
+const spamMinDigitLength = 6
+
+// SpamThresholds configures DetectSpamPattern. A label is flagged when it reaches any one of the
+// thresholds; a zero threshold disables that check.
+type SpamThresholds struct {
+	MinHyphens    int     `json:"min_hyphens"`
+	MinDigitRatio float64 `json:"min_digit_ratio"`
+	MinTokens     int     `json:"min_tokens"`
+}
+
+// DefaultSpamThresholds flags labels with 3+ hyphens, 40%+ digits, or 5+ segments.
+func DefaultSpamThresholds() SpamThresholds {
+	return SpamThresholds{MinHyphens: 3, MinDigitRatio: 0.4, MinTokens: 5}
+}
+
+// Validate rejects negative thresholds and digit ratios above 1.
+func (t SpamThresholds) Validate() error {
+	if t.MinHyphens < 0 {
+		return fmt.Errorf("spam min hyphens must not be negative, got %d", t.MinHyphens)
+	}
+	if t.MinDigitRatio < 0 || t.MinDigitRatio > 1 {
+		return fmt.Errorf("spam min digit ratio must be between 0 and 1, got %g", t.MinDigitRatio)
+	}
+	if t.MinTokens < 0 {
+		return fmt.Errorf("spam min tokens must not be negative, got %d", t.MinTokens)
+	}
+	return nil
+}
+
+// SpamSignal describes the structure of a domain's core label. It is an advisory signal and
+// does not feed the trademark or vice scores.
+type SpamSignal struct {
+	Hyphens    int     `json:"hyphens"`
+	DigitRatio float64 `json:"digit_ratio"`
+	Tokens     int     `json:"tokens"`
+	Spammy     bool    `json:"spammy"`
+	// Triggers names the thresholds the label reached: "hyphens", "digits", or "tokens".
+	Triggers []string `json:"triggers,omitempty"`
+}
+
+// DetectSpamPattern measures the hyphen count, digit share, and segment count of the profile's
+// core label, e.g. "casino-bonus-free-777" has 3 hyphens and 4 segments. Runs of digits count
+// as their own segment, so "win4cash2day" has 5.
+func DetectSpamPattern(profile DomainProfile, t SpamThresholds) SpamSignal {
+	core := profile.Core
+	if core == "" {
+		return SpamSignal{}
+	}
+
+	var signal SpamSignal
+	digits, letters := 0, 0
+	tokens := 0
+	prev := rune(0)
+	for _, r := range core {
+		switch {
+		case r == '-' || r == '_':
+			signal.Hyphens++
+		case unicode.IsDigit(r):
+			digits++
+			if !unicode.IsDigit(prev) {
+				tokens++
+			}
+		default:
+			letters++
+			if prev == 0 || prev == '-' || prev == '_' || unicode.IsDigit(prev) {
+				tokens++
+			}
+		}
+		prev = r
+	}
+	signal.Tokens = tokens
+	if total := digits + letters; total > 0 {
+		signal.DigitRatio = math.Round(float64(digits)/float64(total)*100) / 100
+	}
+
+	if t.MinHyphens > 0 && signal.Hyphens >= t.MinHyphens {
+		signal.Triggers = append(signal.Triggers, "hyphens")
+	}
+	if t.MinDigitRatio > 0 && digits+letters >= spamMinDigitLength && signal.DigitRatio >= t.MinDigitRatio {
+		signal.Triggers = append(signal.Triggers, "digits")
+	}
+	if t.MinTokens > 0 && signal.Tokens >= t.MinTokens {
+		signal.Triggers = append(signal.Triggers, "tokens")
+	}
+	signal.Spammy = len(signal.Triggers) > 0
+	return signal
+}
+
+// Describe summarizes the signal for reasons and prompts.
+func (s SpamSignal) Describe() string {
+	return fmt.Sprintf("%d hyphens, %.0f%% digits, %d segments; reached %s",
+		s.Hyphens, s.DigitRatio*100, s.Tokens, strings.Join(s.Triggers, ", "))
+}
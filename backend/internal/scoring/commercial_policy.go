@@ -0,0 +1,198 @@
+package scoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// CommercialOverridePolicy decides when a comparable commercial sale softens a recommendation.
+// Matches below MinSimilarity are ignored, and the matched sale only overrides when it sold for at
+// least MinPrice and the vice and trademark scores stay at or below their maxima. Remap maps each
+// recommendation to its softened value; recommendations without an entry are unchanged. Tiers
+// replace the score maxima for sales at or above their own MinPrice, so very high historical
+// sales can override stronger signals.
+type CommercialOverridePolicy struct {
+	MinPrice          float64                           `json:"min_price"`
+	MinSimilarity     float64                           `json:"min_similarity"`
+	MaxViceScore      int                               `json:"max_vice_score"`
+	MaxTrademarkScore int                               `json:"max_trademark_score"`
+	Remap             map[Recommendation]Recommendation `json:"remap"`
+	Tiers             []CommercialTier                  `json:"tiers"`
+}
+
+// CommercialTier sets the score maxima under which a sale of at least MinPrice may override.
+type CommercialTier struct {
+	Name              string  `json:"name"`
+	MinPrice          float64 `json:"min_price"`
+	MaxViceScore      int     `json:"max_vice_score"`
+	MaxTrademarkScore int     `json:"max_trademark_score"`
+}
+
+// StandardCommercialTier names the tier formed by the policy's own thresholds, which applies to
+// sales below every configured tier.
+const StandardCommercialTier = "standard"
+
+// DefaultCommercialOverridePolicy returns the built-in policy: sales of $10,000 or more with at
+// least 0.8 similarity soften BLOCK to REVIEW and REVIEW to ALLOW_WITH_CAUTION when vice <= 2
+// and trademark <= 3; a "premium" sale of $500,000 or more also overrides at trademark 4.
+func DefaultCommercialOverridePolicy() CommercialOverridePolicy {
+	return CommercialOverridePolicy{
+		MinPrice:          10000,
+		MinSimilarity:     0.8,
+		MaxViceScore:      2,
+		MaxTrademarkScore: 3,
+		Remap: map[Recommendation]Recommendation{
+			RecommendationBlock:  RecommendationReview,
+			RecommendationReview: RecommendationAllowWithCaution,
+		},
+		Tiers: []CommercialTier{
+			{Name: "premium", MinPrice: 500000, MaxViceScore: 2, MaxTrademarkScore: 4},
+		},
+	}
+}
+
+// LoadCommercialOverridePolicy reads a JSON policy. Fields missing from the file keep their
+// defaults; remap entries are merged over the default mapping, and an entry mapping a
+// recommendation to itself removes the default one. Tiers, when present, replace the default list.
+func LoadCommercialOverridePolicy(path string) (CommercialOverridePolicy, error) {
+	policy := DefaultCommercialOverridePolicy()
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return policy, fmt.Errorf("read commercial policy: %w", err)
+	}
+	// Decode the file's remap on its own so its entries always override the defaults, whatever
+	// case their keys use.
+	remap := policy.Remap
+	var file struct {
+		CommercialOverridePolicy
+		Remap map[string]string `json:"remap"`
+	}
+	file.CommercialOverridePolicy = policy
+	if err := json.Unmarshal(data, &file); err != nil {
+		return policy, fmt.Errorf("unmarshal commercial policy: %w", err)
+	}
+	policy = file.CommercialOverridePolicy
+	for rawFrom, rawTo := range file.Remap {
+		from, err := ParseRecommendation(rawFrom)
+		if err != nil {
+			return policy, fmt.Errorf("commercial policy: %w", err)
+		}
+		to, err := ParseRecommendation(rawTo)
+		if err != nil {
+			return policy, fmt.Errorf("commercial policy: %w", err)
+		}
+		if from == to {
+			delete(remap, from)
+			continue
+		}
+		remap[from] = to
+	}
+	policy.Remap = remap
+	if err := policy.Validate(); err != nil {
+		return policy, err
+	}
+	return policy, nil
+}
+
+// WithMinPrice returns p with MinPrice replaced by price and validated. A built-in default tier
+// priced below the new minimum could never apply, so it is dropped; configured tiers below it
+// still fail validation.
+func (p CommercialOverridePolicy) WithMinPrice(price float64) (CommercialOverridePolicy, error) {
+	if p.Tiers != nil {
+		defaults := DefaultCommercialOverridePolicy().Tiers
+		tiers := make([]CommercialTier, 0, len(p.Tiers))
+		for _, tier := range p.Tiers {
+			if tier.MinPrice < price && slices.Contains(defaults, tier) {
+				continue
+			}
+			tiers = append(tiers, tier)
+		}
+		p.Tiers = tiers
+	}
+	p.MinPrice = price
+	return p, p.Validate()
+}
+
+// Validate checks thresholds are in range and remap only softens known recommendations.
+func (p CommercialOverridePolicy) Validate() error {
+	if p.MinPrice < 0 {
+		return fmt.Errorf("commercial policy: min_price %.0f is negative", p.MinPrice)
+	}
+	if p.MinSimilarity < 0 || p.MinSimilarity > 1 {
+		return fmt.Errorf("commercial policy: min_similarity %.2f outside 0-1", p.MinSimilarity)
+	}
+	if p.MaxViceScore < 0 || p.MaxViceScore > 5 {
+		return fmt.Errorf("commercial policy: max_vice_score %d outside 0-5", p.MaxViceScore)
+	}
+	if p.MaxTrademarkScore < 0 || p.MaxTrademarkScore > 5 {
+		return fmt.Errorf("commercial policy: max_trademark_score %d outside 0-5", p.MaxTrademarkScore)
+	}
+	for _, tier := range p.Tiers {
+		if strings.TrimSpace(tier.Name) == "" {
+			return fmt.Errorf("commercial policy: tier at min_price %.0f has no name", tier.MinPrice)
+		}
+		if tier.MinPrice < p.MinPrice {
+			return fmt.Errorf("commercial policy: tier %q min_price %.0f is below the policy min_price %.0f", tier.Name, tier.MinPrice, p.MinPrice)
+		}
+		if tier.MaxViceScore < 0 || tier.MaxViceScore > 5 {
+			return fmt.Errorf("commercial policy: tier %q max_vice_score %d outside 0-5", tier.Name, tier.MaxViceScore)
+		}
+		if tier.MaxTrademarkScore < 0 || tier.MaxTrademarkScore > 5 {
+			return fmt.Errorf("commercial policy: tier %q max_trademark_score %d outside 0-5", tier.Name, tier.MaxTrademarkScore)
+		}
+	}
+	for from, to := range p.Remap {
+		if !from.Valid() {
+			return fmt.Errorf("commercial policy: unknown recommendation %q", from)
+		}
+		if !to.Valid() {
+			return fmt.Errorf("commercial policy: unknown recommendation %q", to)
+		}
+		if to.Severity() > from.Severity() {
+			return fmt.Errorf("commercial policy: remap %s to %s is stricter; an override may only soften", from, to)
+		}
+	}
+	return nil
+}
+
+// Eligible reports whether a sale with the given similarity may override these scores.
+func (p CommercialOverridePolicy) Eligible(trademarkScore, viceScore int, similarity float64) bool {
+	return similarity >= p.MinSimilarity && viceScore <= p.MaxViceScore && trademarkScore <= p.MaxTrademarkScore
+}
+
+// Tier returns the tier a sale at price falls in: the configured tier with the highest MinPrice
+// not above price, or the policy's own thresholds named StandardCommercialTier.
+func (p CommercialOverridePolicy) Tier(price float64) CommercialTier {
+	tier := CommercialTier{
+		Name:              StandardCommercialTier,
+		MinPrice:          p.MinPrice,
+		MaxViceScore:      p.MaxViceScore,
+		MaxTrademarkScore: p.MaxTrademarkScore,
+	}
+	for _, candidate := range p.Tiers {
+		if price >= candidate.MinPrice && candidate.MinPrice >= tier.MinPrice {
+			tier = candidate
+		}
+	}
+	return tier
+}
+
+// EligibleAt is Eligible with the score maxima of the tier a sale at price falls in, which it
+// also returns. A sale below MinPrice is never eligible.
+func (p CommercialOverridePolicy) EligibleAt(trademarkScore, viceScore int, similarity, price float64) (CommercialTier, bool) {
+	tier := p.Tier(price)
+	ok := price >= p.MinPrice && similarity >= p.MinSimilarity && viceScore <= tier.MaxViceScore && trademarkScore <= tier.MaxTrademarkScore
+	return tier, ok
+}
+
+// Apply returns the softened recommendation.
+func (p CommercialOverridePolicy) Apply(recommendation Recommendation) Recommendation {
+	if to, ok := p.Remap[recommendation]; ok {
+		return to
+	}
+	return recommendation
+}
@@ -1,844 +1,95 @@
 package scoring
 
-import "strings"
+import (
+	"bufio"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
 
-var commonWordSet = map[string]struct{}{
-	"a":              {},
-	"able":           {},
-	"about":          {},
-	"above":          {},
-	"accept":         {},
-	"account":        {},
-	"across":         {},
-	"action":         {},
-	"active":         {},
-	"add":            {},
-	"after":          {},
-	"again":          {},
-	"against":        {},
-	"age":            {},
-	"ago":            {},
-	"air":            {},
-	"all":            {},
-	"allow":          {},
-	"almost":         {},
-	"alone":          {},
-	"along":          {},
-	"already":        {},
-	"also":           {},
-	"always":         {},
-	"am":             {},
-	"among":          {},
-	"amount":         {},
-	"an":             {},
-	"and":            {},
-	"animal":         {},
-	"another":        {},
-	"answer":         {},
-	"any":            {},
-	"anyone":         {},
-	"anything":       {},
-	"appear":         {},
-	"apply":          {},
-	"area":           {},
-	"argue":          {},
-	"arm":            {},
-	"around":         {},
-	"art":            {},
-	"as":             {},
-	"ask":            {},
-	"at":             {},
-	"attack":         {},
-	"attention":      {},
-	"author":         {},
-	"available":      {},
-	"away":           {},
-	"baby":           {},
-	"back":           {},
-	"bad":            {},
-	"bag":            {},
-	"ball":           {},
-	"bank":           {},
-	"base":           {},
-	"basic":          {},
-	"be":             {},
-	"bear":           {},
-	"beat":           {},
-	"beauty":         {},
-	"because":        {},
-	"become":         {},
-	"bed":            {},
-	"before":         {},
-	"begin":          {},
-	"behind":         {},
-	"believe":        {},
-	"below":          {},
-	"benefit":        {},
-	"best":           {},
-	"better":         {},
-	"between":        {},
-	"beyond":         {},
-	"big":            {},
-	"bill":           {},
-	"bird":           {},
-	"bit":            {},
-	"black":          {},
-	"blood":          {},
-	"blue":           {},
-	"board":          {},
-	"body":           {},
-	"book":           {},
-	"born":           {},
-	"both":           {},
-	"box":            {},
-	"boy":            {},
-	"break":          {},
-	"bring":          {},
-	"brother":        {},
-	"build":          {},
-	"business":       {},
-	"busy":           {},
-	"but":            {},
-	"buy":            {},
-	"by":             {},
-	"call":           {},
-	"camera":         {},
-	"can":            {},
-	"capital":        {},
-	"car":            {},
-	"card":           {},
-	"care":           {},
-	"carry":          {},
-	"case":           {},
-	"catch":          {},
-	"cause":          {},
-	"center":         {},
-	"central":        {},
-	"certain":        {},
-	"chair":          {},
-	"chance":         {},
-	"change":         {},
-	"chart":          {},
-	"check":          {},
-	"child":          {},
-	"choice":         {},
-	"choose":         {},
-	"church":         {},
-	"city":           {},
-	"claim":          {},
-	"class":          {},
-	"clean":          {},
-	"clear":          {},
-	"close":          {},
-	"club":           {},
-	"coat":           {},
-	"coffee":         {},
-	"cold":           {},
-	"college":        {},
-	"color":          {},
-	"come":           {},
-	"common":         {},
-	"company":        {},
-	"complete":       {},
-	"computer":       {},
-	"concern":        {},
-	"condition":      {},
-	"conference":     {},
-	"confirm":        {},
-	"consider":       {},
-	"contain":        {},
-	"continue":       {},
-	"control":        {},
-	"cost":           {},
-	"could":          {},
-	"country":        {},
-	"course":         {},
-	"court":          {},
-	"cover":          {},
-	"create":         {},
-	"credit":         {},
-	"crime":          {},
-	"culture":        {},
-	"cup":            {},
-	"current":        {},
-	"customer":       {},
-	"cut":            {},
-	"dad":            {},
-	"daily":          {},
-	"damage":         {},
-	"dance":          {},
-	"danger":         {},
-	"dark":           {},
-	"data":           {},
-	"date":           {},
-	"daughter":       {},
-	"day":            {},
-	"dead":           {},
-	"deal":           {},
-	"debate":         {},
-	"decide":         {},
-	"decision":       {},
-	"deep":           {},
-	"degree":         {},
-	"deliver":        {},
-	"demand":         {},
-	"department":     {},
-	"describe":       {},
-	"design":         {},
-	"desk":           {},
-	"detail":         {},
-	"develop":        {},
-	"die":            {},
-	"difference":     {},
-	"different":      {},
-	"difficult":      {},
-	"dinner":         {},
-	"direction":      {},
-	"director":       {},
-	"discover":       {},
-	"discuss":        {},
-	"district":       {},
-	"do":             {},
-	"doctor":         {},
-	"dog":            {},
-	"door":           {},
-	"down":           {},
-	"draw":           {},
-	"dream":          {},
-	"drive":          {},
-	"drop":           {},
-	"drug":           {},
-	"during":         {},
-	"each":           {},
-	"early":          {},
-	"east":           {},
-	"easy":           {},
-	"eat":            {},
-	"economy":        {},
-	"edge":           {},
-	"education":      {},
-	"effect":         {},
-	"effort":         {},
-	"either":         {},
-	"election":       {},
-	"else":           {},
-	"employee":       {},
-	"end":            {},
-	"energy":         {},
-	"enjoy":          {},
-	"enough":         {},
-	"enter":          {},
-	"entire":         {},
-	"environment":    {},
-	"equal":          {},
-	"especially":     {},
-	"establish":      {},
-	"even":           {},
-	"evening":        {},
-	"event":          {},
-	"ever":           {},
-	"every":          {},
-	"everyone":       {},
-	"everything":     {},
-	"example":        {},
-	"experience":     {},
-	"expert":         {},
-	"explain":        {},
-	"eye":            {},
-	"face":           {},
-	"fact":           {},
-	"fail":           {},
-	"fair":           {},
-	"fall":           {},
-	"family":         {},
-	"far":            {},
-	"farm":           {},
-	"fast":           {},
-	"father":         {},
-	"fear":           {},
-	"federal":        {},
-	"feel":           {},
-	"field":          {},
-	"fight":          {},
-	"figure":         {},
-	"fill":           {},
-	"film":           {},
-	"final":          {},
-	"find":           {},
-	"fine":           {},
-	"finger":         {},
-	"finish":         {},
-	"fire":           {},
-	"firm":           {},
-	"first":          {},
-	"fish":           {},
-	"floor":          {},
-	"follow":         {},
-	"food":           {},
-	"foot":           {},
-	"for":            {},
-	"force":          {},
-	"foreign":        {},
-	"forget":         {},
-	"form":           {},
-	"forward":        {},
-	"found":          {},
-	"four":           {},
-	"free":           {},
-	"friend":         {},
-	"from":           {},
-	"front":          {},
-	"full":           {},
-	"future":         {},
-	"game":           {},
-	"garden":         {},
-	"gas":            {},
-	"general":        {},
-	"girl":           {},
-	"give":           {},
-	"glass":          {},
-	"go":             {},
-	"goal":           {},
-	"good":           {},
-	"great":          {},
-	"green":          {},
-	"ground":         {},
-	"group":          {},
-	"grow":           {},
-	"guess":          {},
-	"gun":            {},
-	"guy":            {},
-	"hair":           {},
-	"half":           {},
-	"hand":           {},
-	"hang":           {},
-	"happen":         {},
-	"happy":          {},
-	"hard":           {},
-	"has":            {},
-	"have":           {},
-	"he":             {},
-	"head":           {},
-	"health":         {},
-	"hear":           {},
-	"heart":          {},
-	"help":           {},
-	"her":            {},
-	"here":           {},
-	"high":           {},
-	"him":            {},
-	"himself":        {},
-	"his":            {},
-	"history":        {},
-	"hit":            {},
-	"hold":           {},
-	"home":           {},
-	"hope":           {},
-	"hospital":       {},
-	"hot":            {},
-	"hotel":          {},
-	"hour":           {},
-	"house":          {},
-	"how":            {},
-	"huge":           {},
-	"human":          {},
-	"hundred":        {},
-	"husband":        {},
-	"idea":           {},
-	"identify":       {},
-	"if":             {},
-	"imagine":        {},
-	"impact":         {},
-	"important":      {},
-	"improve":        {},
-	"include":        {},
-	"increase":       {},
-	"industry":       {},
-	"information":    {},
-	"inside":         {},
-	"instead":        {},
-	"interest":       {},
-	"into":           {},
-	"invest":         {},
-	"issue":          {},
-	"it":             {},
-	"job":            {},
-	"join":           {},
-	"just":           {},
-	"keep":           {},
-	"key":            {},
-	"kid":            {},
-	"kill":           {},
-	"kind":           {},
-	"king":           {},
-	"kitchen":        {},
-	"know":           {},
-	"knowledge":      {},
-	"land":           {},
-	"language":       {},
-	"large":          {},
-	"last":           {},
-	"late":           {},
-	"laugh":          {},
-	"law":            {},
-	"lead":           {},
-	"learn":          {},
-	"leave":          {},
-	"left":           {},
-	"leg":            {},
-	"legal":          {},
-	"less":           {},
-	"let":            {},
-	"letter":         {},
-	"level":          {},
-	"life":           {},
-	"light":          {},
-	"like":           {},
-	"line":           {},
-	"list":           {},
-	"listen":         {},
-	"little":         {},
-	"live":           {},
-	"local":          {},
-	"long":           {},
-	"look":           {},
-	"lose":           {},
-	"loss":           {},
-	"lot":            {},
-	"love":           {},
-	"low":            {},
-	"machine":        {},
-	"main":           {},
-	"major":          {},
-	"make":           {},
-	"man":            {},
-	"manage":         {},
-	"many":           {},
-	"market":         {},
-	"marry":          {},
-	"master":         {},
-	"material":       {},
-	"may":            {},
-	"maybe":          {},
-	"me":             {},
-	"mean":           {},
-	"measure":        {},
-	"media":          {},
-	"medical":        {},
-	"meet":           {},
-	"member":         {},
-	"memory":         {},
-	"mention":        {},
-	"message":        {},
-	"method":         {},
-	"middle":         {},
-	"might":          {},
-	"military":       {},
-	"million":        {},
-	"mind":           {},
-	"minute":         {},
-	"miss":           {},
-	"money":          {},
-	"month":          {},
-	"more":           {},
-	"morning":        {},
-	"most":           {},
-	"mother":         {},
-	"move":           {},
-	"movie":          {},
-	"much":           {},
-	"music":          {},
-	"must":           {},
-	"my":             {},
-	"name":           {},
-	"nation":         {},
-	"natural":        {},
-	"nature":         {},
-	"near":           {},
-	"necessary":      {},
-	"need":           {},
-	"network":        {},
-	"never":          {},
-	"new":            {},
-	"news":           {},
-	"next":           {},
-	"nice":           {},
-	"night":          {},
-	"no":             {},
-	"none":           {},
-	"north":          {},
-	"not":            {},
-	"note":           {},
-	"nothing":        {},
-	"notice":         {},
-	"number":         {},
-	"object":         {},
-	"observe":        {},
-	"occur":          {},
-	"of":             {},
-	"off":            {},
-	"offer":          {},
-	"office":         {},
-	"officer":        {},
-	"often":          {},
-	"oh":             {},
-	"oil":            {},
-	"ok":             {},
-	"old":            {},
-	"on":             {},
-	"once":           {},
-	"one":            {},
-	"only":           {},
-	"onto":           {},
-	"open":           {},
-	"operate":        {},
-	"opinion":        {},
-	"opportunity":    {},
-	"option":         {},
-	"or":             {},
-	"order":          {},
-	"organize":       {},
-	"other":          {},
-	"our":            {},
-	"out":            {},
-	"outside":        {},
-	"over":           {},
-	"own":            {},
-	"owner":          {},
-	"page":           {},
-	"pain":           {},
-	"paint":          {},
-	"pair":           {},
-	"paper":          {},
-	"parent":         {},
-	"part":           {},
-	"partner":        {},
-	"party":          {},
-	"pass":           {},
-	"past":           {},
-	"patient":        {},
-	"pattern":        {},
-	"pay":            {},
-	"people":         {},
-	"per":            {},
-	"perform":        {},
-	"perhaps":        {},
-	"period":         {},
-	"person":         {},
-	"phone":          {},
-	"picture":        {},
-	"piece":          {},
-	"place":          {},
-	"plan":           {},
-	"plant":          {},
-	"play":           {},
-	"player":         {},
-	"point":          {},
-	"police":         {},
-	"policy":         {},
-	"political":      {},
-	"poor":           {},
-	"popular":        {},
-	"population":     {},
-	"position":       {},
-	"possible":       {},
-	"power":          {},
-	"practice":       {},
-	"prepare":        {},
-	"present":        {},
-	"president":      {},
-	"press":          {},
-	"pressure":       {},
-	"pretty":         {},
-	"price":          {},
-	"private":        {},
-	"probably":       {},
-	"problem":        {},
-	"process":        {},
-	"produce":        {},
-	"product":        {},
-	"program":        {},
-	"project":        {},
-	"property":       {},
-	"protect":        {},
-	"prove":          {},
-	"provide":        {},
-	"public":         {},
-	"pull":           {},
-	"purpose":        {},
-	"push":           {},
-	"put":            {},
-	"quality":        {},
-	"question":       {},
-	"quick":          {},
-	"quite":          {},
-	"race":           {},
-	"radio":          {},
-	"range":          {},
-	"rate":           {},
-	"reach":          {},
-	"read":           {},
-	"ready":          {},
-	"real":           {},
-	"reality":        {},
-	"really":         {},
-	"reason":         {},
-	"receive":        {},
-	"recent":         {},
-	"record":         {},
-	"red":            {},
-	"reduce":         {},
-	"refuse":         {},
-	"region":         {},
-	"relate":         {},
-	"relationship":   {},
-	"religion":       {},
-	"remain":         {},
-	"remember":       {},
-	"remove":         {},
-	"report":         {},
-	"represent":      {},
-	"require":        {},
-	"research":       {},
-	"resource":       {},
-	"respond":        {},
-	"responsibility": {},
-	"rest":           {},
-	"result":         {},
-	"return":         {},
-	"rise":           {},
-	"risk":           {},
-	"road":           {},
-	"room":           {},
-	"rule":           {},
-	"run":            {},
-	"safe":           {},
-	"same":           {},
-	"save":           {},
-	"say":            {},
-	"school":         {},
-	"science":        {},
-	"score":          {},
-	"season":         {},
-	"seat":           {},
-	"second":         {},
-	"section":        {},
-	"see":            {},
-	"sell":           {},
-	"send":           {},
-	"sense":          {},
-	"series":         {},
-	"serious":        {},
-	"service":        {},
-	"set":            {},
-	"seven":          {},
-	"several":        {},
-	"shake":          {},
-	"share":          {},
-	"she":            {},
-	"shoot":          {},
-	"short":          {},
-	"shot":           {},
-	"should":         {},
-	"show":           {},
-	"side":           {},
-	"sign":           {},
-	"significant":    {},
-	"similar":        {},
-	"simple":         {},
-	"since":          {},
-	"sing":           {},
-	"single":         {},
-	"sister":         {},
-	"sit":            {},
-	"site":           {},
-	"situation":      {},
-	"six":            {},
-	"size":           {},
-	"skill":          {},
-	"small":          {},
-	"smile":          {},
-	"so":             {},
-	"social":         {},
-	"society":        {},
-	"some":           {},
-	"someone":        {},
-	"something":      {},
-	"sometimes":      {},
-	"son":            {},
-	"song":           {},
-	"soon":           {},
-	"sort":           {},
-	"sound":          {},
-	"source":         {},
-	"south":          {},
-	"space":          {},
-	"speak":          {},
-	"special":        {},
-	"specific":       {},
-	"speech":         {},
-	"spend":          {},
-	"sport":          {},
-	"spring":         {},
-	"staff":          {},
-	"stage":          {},
-	"stand":          {},
-	"standard":       {},
-	"star":           {},
-	"start":          {},
-	"state":          {},
-	"station":        {},
-	"stay":           {},
-	"step":           {},
-	"still":          {},
-	"stock":          {},
-	"stop":           {},
-	"story":          {},
-	"strategy":       {},
-	"street":         {},
-	"strong":         {},
-	"student":        {},
-	"study":          {},
-	"stuff":          {},
-	"style":          {},
-	"subject":        {},
-	"success":        {},
-	"such":           {},
-	"suddenly":       {},
-	"suggest":        {},
-	"summer":         {},
-	"support":        {},
-	"sure":           {},
-	"system":         {},
-	"table":          {},
-	"take":           {},
-	"talk":           {},
-	"task":           {},
-	"tax":            {},
-	"teach":          {},
-	"team":           {},
-	"technology":     {},
-	"tell":           {},
-	"ten":            {},
-	"term":           {},
-	"test":           {},
-	"than":           {},
-	"thank":          {},
-	"that":           {},
-	"the":            {},
-	"their":          {},
-	"them":           {},
-	"themselves":     {},
-	"then":           {},
-	"theory":         {},
-	"there":          {},
-	"these":          {},
-	"they":           {},
-	"thing":          {},
-	"think":          {},
-	"third":          {},
-	"this":           {},
-	"those":          {},
-	"though":         {},
-	"thought":        {},
-	"thousand":       {},
-	"threat":         {},
-	"three":          {},
-	"through":        {},
-	"throw":          {},
-	"time":           {},
-	"today":          {},
-	"together":       {},
-	"tonight":        {},
-	"too":            {},
-	"top":            {},
-	"total":          {},
-	"town":           {},
-	"trade":          {},
-	"traditional":    {},
-	"traffic":        {},
-	"training":       {},
-	"travel":         {},
-	"treat":          {},
-	"treatment":      {},
-	"tree":           {},
-	"trial":          {},
-	"trip":           {},
-	"trouble":        {},
-	"true":           {},
-	"trust":          {},
-	"try":            {},
-	"turn":           {},
-	"TV":             {},
-	"two":            {},
-	"type":           {},
-	"under":          {},
-	"understand":     {},
-	"unit":           {},
-	"until":          {},
-	"up":             {},
-	"upon":           {},
-	"use":            {},
-	"value":          {},
-	"various":        {},
-	"very":           {},
-	"view":           {},
-	"vote":           {},
-	"wait":           {},
-	"walk":           {},
-	"wall":           {},
-	"want":           {},
-	"war":            {},
-	"watch":          {},
-	"water":          {},
-	"way":            {},
-	"we":             {},
-	"weapon":         {},
-	"wear":           {},
-	"week":           {},
-	"weight":         {},
-	"well":           {},
-	"west":           {},
-	"what":           {},
-	"when":           {},
-	"where":          {},
-	"whether":        {},
-	"which":          {},
-	"while":          {},
-	"white":          {},
-	"who":            {},
-	"whole":          {},
-	"whom":           {},
-	"whose":          {},
-	"why":            {},
-	"wide":           {},
-	"wife":           {},
-	"will":           {},
-	"win":            {},
-	"wind":           {},
-	"window":         {},
-	"wish":           {},
-	"with":           {},
-	"within":         {},
-	"without":        {},
-	"woman":          {},
-	"wonder":         {},
-	"word":           {},
-	"work":           {},
-	"worker":         {},
-	"world":          {},
-	"worry":          {},
-	"write":          {},
-	"writer":         {},
-	"wrong":          {},
-	"yard":           {},
-	"year":           {},
-	"yes":            {},
-	"yet":            {},
-	"you":            {},
-	"young":          {},
-	"your":           {},
-	"yourself":       {},
+//go:embed common_words.txt
+var defaultCommonWordsText string
+
+var (
+	commonMu      sync.RWMutex
+	commonWordSet = buildCommonWordSet(DefaultCommonWords())
+)
+
+// DefaultCommonWords returns the embedded dictionary of common words.
+func DefaultCommonWords() []string {
+	return parseWordList(defaultCommonWordsText)
+}
+
+// SetCommonWords replaces the common-word dictionary used to downgrade dictionary-word marks.
+func SetCommonWords(words []string) {
+	set := buildCommonWordSet(words)
+	commonMu.Lock()
+	commonWordSet = set
+	commonMu.Unlock()
+}
+
+// AddCommonWords extends the dictionary with domain-specific stopwords.
+func AddCommonWords(words ...string) {
+	commonMu.Lock()
+	defer commonMu.Unlock()
+	for _, word := range words {
+		if token := sanitizeDictionaryToken(word); token != "" {
+			commonWordSet[token] = struct{}{}
+		}
+	}
+}
+
+// CommonWordCount reports the size of the active dictionary.
+func CommonWordCount() int {
+	commonMu.RLock()
+	defer commonMu.RUnlock()
+	return len(commonWordSet)
+}
+
+// LoadCommonWords replaces the dictionary with the words in path, either a JSON array or a text
+// file with one word per line (# starts a comment line). It returns the number of words loaded.
+func LoadCommonWords(path string) (int, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return 0, fmt.Errorf("read common words: %w", err)
+	}
+	var words []string
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.Unmarshal(data, &words); err != nil {
+			return 0, fmt.Errorf("unmarshal common words: %w", err)
+		}
+	} else {
+		words = parseWordList(string(data))
+	}
+	SetCommonWords(words)
+	return CommonWordCount(), nil
+}
+
+func parseWordList(text string) []string {
+	var words []string
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	return words
+}
+
+func buildCommonWordSet(words []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		if token := sanitizeDictionaryToken(word); token != "" {
+			set[token] = struct{}{}
+		}
+	}
+	return set
 }
 
 func isCommonWord(token string) bool {
@@ -846,6 +97,8 @@ func isCommonWord(token string) bool {
 	if token == "" {
 		return false
 	}
+	commonMu.RLock()
+	defer commonMu.RUnlock()
 	_, ok := commonWordSet[token]
 	return ok
 }
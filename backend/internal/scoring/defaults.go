@@ -0,0 +1,34 @@
+package scoring
+
+import (
+	_ "embed"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Embedded copies of the default seed and vice lists, used when a configured file is missing so
+// the server does not depend on being started from the repo root.
+var (
+	//go:embed fanciful_seed.json
+	defaultSeedsJSON []byte
+	//go:embed vice_terms.json
+	defaultViceTermsJSON []byte
+)
+
+// readOrDefault reads path, falling back to the embedded default with a warning when the file
+// does not exist. Other read errors are returned unchanged.
+func readOrDefault(path string, fallback []byte, kind string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err == nil {
+		return data, nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		logrus.WithField("path", path).Warnf("%s file not found; using embedded defaults", kind)
+		return fallback, nil
+	}
+	return nil, err
+}
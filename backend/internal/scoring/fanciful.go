@@ -1,19 +1,54 @@
 package scoring
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
+
+// FancifulThresholds configures the heuristic that flags marks fanciful beyond the seed list: a
+// normalized mark of at least MinLength characters registered in at least MinClasses classes.
+type FancifulThresholds struct {
+	MinLength  int `json:"min_length"`
+	MinClasses int `json:"min_classes"`
+}
+
+// DefaultFancifulThresholds returns the historical heuristic: length ≥ 6 and ≥ 2 classes.
+func DefaultFancifulThresholds() FancifulThresholds {
+	return FancifulThresholds{MinLength: 6, MinClasses: 2}
+}
+
+// Validate rejects non-positive thresholds, which would flag every mark fanciful.
+func (t FancifulThresholds) Validate() error {
+	if t.MinLength < 1 {
+		return fmt.Errorf("fanciful min length must be positive, got %d", t.MinLength)
+	}
+	if t.MinClasses < 1 {
+		return fmt.Errorf("fanciful min classes must be positive, got %d", t.MinClasses)
+	}
+	return nil
+}
 
 // FancifulDecider implements xml.FancifulDecider using the seed list.
 type FancifulDecider struct {
-	seeds map[string]struct{}
+	seeds      map[string]struct{}
+	thresholds FancifulThresholds
 }
 
-// NewFancifulDecider constructs a decider from the provided seeds.
-func NewFancifulDecider(seedPath string) (*FancifulDecider, error) {
+// NewFancifulDecider constructs a decider from the provided seeds. A nil thresholds config uses
+// DefaultFancifulThresholds.
+func NewFancifulDecider(seedPath string, thresholds *FancifulThresholds) (*FancifulDecider, error) {
 	seeds, err := loadSeeds(seedPath)
 	if err != nil {
 		return nil, err
 	}
-	return &FancifulDecider{seeds: seeds}, nil
+	cfg := DefaultFancifulThresholds()
+	if thresholds != nil {
+		if err := thresholds.Validate(); err != nil {
+			return nil, err
+		}
+		cfg = *thresholds
+	}
+	return &FancifulDecider{seeds: seeds, thresholds: cfg}, nil
 }
 
 // Decide marks entries optionally fanciful using seeds and heuristics.
@@ -23,7 +58,7 @@ func (d *FancifulDecider) Decide(markNormalized string, classes []string, owner
 	if _, ok := d.seeds[key]; ok {
 		return true
 	}
-	if len(markNormalized) >= 6 && len(classes) >= 2 {
+	if len(markNormalized) >= d.thresholds.MinLength && len(classes) >= d.thresholds.MinClasses {
 		return true
 	}
 	return false
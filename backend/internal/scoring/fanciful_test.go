@@ -0,0 +1,45 @@
+package scoring
+
+import "testing"
+
+func TestFancifulDeciderThresholds(t *testing.T) {
+	seedPath := createSeedFile(t, []string{"zq"})
+	two := []string{"009", "025"}
+
+	cases := []struct {
+		name       string
+		thresholds *FancifulThresholds
+		mark       string
+		classes    []string
+		expected   bool
+	}{
+		{"default_at_boundary", nil, "abcdef", two, true},
+		{"default_short", nil, "abcde", two, false},
+		{"default_single_class", nil, "abcdef", []string{"009"}, false},
+		{"seed_ignores_thresholds", nil, "zq", nil, true},
+		{"custom_at_boundary", &FancifulThresholds{MinLength: 8, MinClasses: 3}, "abcdefgh", []string{"009", "025", "041"}, true},
+		{"custom_short", &FancifulThresholds{MinLength: 8, MinClasses: 3}, "abcdefg", []string{"009", "025", "041"}, false},
+		{"custom_too_few_classes", &FancifulThresholds{MinLength: 8, MinClasses: 3}, "abcdefgh", two, false},
+		{"custom_relaxed", &FancifulThresholds{MinLength: 3, MinClasses: 1}, "abc", []string{"009"}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			decider, err := NewFancifulDecider(seedPath, tc.thresholds)
+			if err != nil {
+				t.Fatalf("new decider: %v", err)
+			}
+			if got := decider.Decide(tc.mark, tc.classes, ""); got != tc.expected {
+				t.Fatalf("expected %v got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestFancifulThresholdsValidate(t *testing.T) {
+	seedPath := createSeedFile(t, nil)
+	for _, bad := range []FancifulThresholds{{MinLength: 0, MinClasses: 2}, {MinLength: 6, MinClasses: -1}} {
+		if _, err := NewFancifulDecider(seedPath, &bad); err == nil {
+			t.Fatalf("expected error for %+v", bad)
+		}
+	}
+}
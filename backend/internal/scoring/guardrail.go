@@ -0,0 +1,35 @@
+package scoring
+
+import "fmt"
+
+// DefaultAIGuardrailScore is the heuristic trademark or vice score from which the AI may no
+// longer relax a domain below REVIEW.
+const DefaultAIGuardrailScore = 5
+
+// GuardAIScore keeps a heuristic score at or above floor when the AI proposes a lower one, and
+// returns a reason when the proposal was rejected. A floor of 0 or less disables the guard.
+func GuardAIScore(kind string, heuristic, proposed, floor int) (int, string) {
+	if floor <= 0 || heuristic < floor || proposed >= heuristic {
+		return proposed, ""
+	}
+	return heuristic, fmt.Sprintf("AI %s score %d rejected: heuristic score %d is at or above the guardrail %d", kind, proposed, heuristic, floor)
+}
+
+// GuardAIRecommendation keeps proposed, the AI's recommendation, at REVIEW or stricter when the
+// heuristic trademark or vice score reached floor. A rejected proposal falls back to heuristic,
+// the recommendation before the AI, or REVIEW when that was laxer too, and a reason is
+// returned. A floor of 0 or less disables the guard.
+func GuardAIRecommendation(proposed, heuristic Recommendation, trademarkScore, viceScore, floor int) (Recommendation, string) {
+	if floor <= 0 || (trademarkScore < floor && viceScore < floor) {
+		return proposed, ""
+	}
+	if proposed.Severity() >= RecommendationReview.Severity() {
+		return proposed, ""
+	}
+	clamped := heuristic
+	if clamped.Severity() < RecommendationReview.Severity() {
+		clamped = RecommendationReview
+	}
+	return clamped, fmt.Sprintf("AI recommendation %s rejected: trademark score %d / vice score %d reach the guardrail %d, so %s is kept",
+		proposed, trademarkScore, viceScore, floor, clamped)
+}
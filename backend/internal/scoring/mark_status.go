@@ -0,0 +1,49 @@
+package scoring
+
+import (
+	"strconv"
+	"strings"
+
+	"domain-risk-eval/backend/internal/store"
+)
+
+// DeadMarkStatus reports whether a USPTO case status code means the mark is no longer live:
+// abandoned applications (6xx), cancelled registrations (710-719), and expired ones (9xx).
+// Pending (1xx-5xx), registered (700-709), and renewed (8xx) marks are live, and an empty or
+// unrecognised code is treated as live so marks ingested without a status are kept.
+func DeadMarkStatus(code string) bool {
+	value, err := strconv.Atoi(strings.TrimSpace(code))
+	if err != nil {
+		return false
+	}
+	switch {
+	case value >= 600 && value < 700:
+		return true
+	case value >= 710 && value < 720:
+		return true
+	case value >= 900 && value < 1000:
+		return true
+	}
+	return false
+}
+
+// liveMarkSQL is the negation of DeadMarkStatus as a condition on marks.status_code, so queries
+// can drop dead marks before applying a LIMIT. Codes that are blank or not all digits stay live.
+const liveMarkSQL = `NOT (
+	TRIM(COALESCE(marks.status_code, '')) <> '' AND TRIM(marks.status_code) NOT GLOB '*[^0-9]*' AND (
+		CAST(TRIM(marks.status_code) AS INTEGER) BETWEEN 600 AND 699 OR
+		CAST(TRIM(marks.status_code) AS INTEGER) BETWEEN 710 AND 719 OR
+		CAST(TRIM(marks.status_code) AS INTEGER) BETWEEN 900 AND 999))`
+
+// FilterLiveMarks drops marks whose status is dead per DeadMarkStatus, reusing the backing
+// array, and returns the live marks with the number dropped.
+func FilterLiveMarks(marks []store.Mark) ([]store.Mark, int) {
+	live := marks[:0]
+	for _, mark := range marks {
+		if DeadMarkStatus(mark.StatusCode) {
+			continue
+		}
+		live = append(live, mark)
+	}
+	return live, len(marks) - len(live)
+}
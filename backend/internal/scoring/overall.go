@@ -1,24 +1,24 @@
 package scoring
 
-import "strings"
+import "fmt"
 
 // OverallResult merges trademark and vice outcomes into a recommendation.
 type OverallResult struct {
-	Recommendation string  `json:"overall_recommendation"`
-	Confidence     float64 `json:"confidence"`
+	Recommendation Recommendation `json:"overall_recommendation"`
+	Confidence     float64        `json:"confidence"`
 }
 
 // CombineRecommendation applies BRD matrix logic to produce overall recommendation.
 func CombineRecommendation(tr TrademarkResult, vice ViceResult) OverallResult {
-	rec := "ALLOW"
+	rec := RecommendationAllow
 	if tr.Score >= 4 || vice.Score >= 4 {
-		rec = "BLOCK"
+		rec = RecommendationBlock
 	} else if vice.Score == 3 || tr.Score == 3 {
-		rec = "REVIEW"
+		rec = RecommendationReview
 	} else if tr.Score == 2 {
-		rec = "ALLOW_WITH_CAUTION"
+		rec = RecommendationAllowWithCaution
 	} else if tr.Score == 1 {
-		rec = "ALLOW_WITH_CAUTION"
+		rec = RecommendationAllowWithCaution
 	}
 
 	confidence := vice.Confidence
@@ -27,7 +27,21 @@ func CombineRecommendation(tr TrademarkResult, vice ViceResult) OverallResult {
 	}
 
 	return OverallResult{
-		Recommendation: strings.ToUpper(rec),
+		Recommendation: rec,
 		Confidence:     confidence,
 	}
 }
+
+// DefaultLowConfidenceThreshold is the confidence below which SoftenLowConfidence softens a BLOCK.
+const DefaultLowConfidenceThreshold = 0.5
+
+// SoftenLowConfidence lowers a BLOCK whose confidence is under threshold one step to REVIEW, so
+// weak evidence goes to a reviewer instead of being blocked outright, and returns a reason when
+// it changed. Other recommendations are left as they are.
+func SoftenLowConfidence(overall OverallResult, threshold float64) (OverallResult, string) {
+	if overall.Recommendation != RecommendationBlock || overall.Confidence >= threshold {
+		return overall, ""
+	}
+	overall.Recommendation = RecommendationReview
+	return overall, fmt.Sprintf("low confidence %.2f (below %.2f) softened BLOCK to REVIEW", overall.Confidence, threshold)
+}
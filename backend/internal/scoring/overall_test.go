@@ -7,7 +7,7 @@ func TestCombineRecommendation(t *testing.T) {
 		name     string
 		tr       TrademarkResult
 		vice     ViceResult
-		expected string
+		expected Recommendation
 	}{
 		{"tr block", TrademarkResult{Score: 5, Confidence: 1}, ViceResult{Score: 0, Confidence: 0.9}, "BLOCK"},
 		{"vice block", TrademarkResult{Score: 0, Confidence: 0.9}, ViceResult{Score: 4, Confidence: 0.95}, "BLOCK"},
@@ -25,3 +25,259 @@ func TestCombineRecommendation(t *testing.T) {
 		})
 	}
 }
+
+func TestTLDRiskTableAdjust(t *testing.T) {
+	table := &TLDRiskTable{High: []string{".zip"}, Elevated: []string{"shop"}}
+	table.index()
+
+	tests := []struct {
+		name     string
+		tld      string
+		rec      Recommendation
+		expected Recommendation
+		reason   bool
+	}{
+		{"high allow", "zip", "ALLOW", "ALLOW_WITH_CAUTION", true},
+		{"high caution", "ZIP", "ALLOW_WITH_CAUTION", "REVIEW", true},
+		{"high block unchanged", "zip", "BLOCK", "BLOCK", false},
+		{"elevated allow", "shop", "ALLOW", "ALLOW_WITH_CAUTION", true},
+		{"elevated caution unchanged", "shop", "ALLOW_WITH_CAUTION", "ALLOW_WITH_CAUTION", false},
+		{"unlisted", "com", "ALLOW", "ALLOW", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, reason := table.Adjust(tc.tld, OverallResult{Recommendation: tc.rec})
+			if result.Recommendation != tc.expected {
+				t.Fatalf("expected %s got %s", tc.expected, result.Recommendation)
+			}
+			if (reason != "") != tc.reason {
+				t.Fatalf("unexpected reason %q", reason)
+			}
+		})
+	}
+}
+
+func TestSoftenLowConfidence(t *testing.T) {
+	tests := []struct {
+		name       string
+		rec        Recommendation
+		confidence float64
+		expected   Recommendation
+		reason     bool
+	}{
+		{"weak block", "BLOCK", 0.4, "REVIEW", true},
+		{"block at threshold", "BLOCK", 0.5, "BLOCK", false},
+		{"confident block", "BLOCK", 0.9, "BLOCK", false},
+		{"weak review unchanged", "REVIEW", 0.2, "REVIEW", false},
+		{"weak allow unchanged", "ALLOW", 0.1, "ALLOW", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, reason := SoftenLowConfidence(OverallResult{Recommendation: tc.rec, Confidence: tc.confidence}, DefaultLowConfidenceThreshold)
+			if result.Recommendation != tc.expected {
+				t.Fatalf("expected %s got %s", tc.expected, result.Recommendation)
+			}
+			if (reason != "") != tc.reason {
+				t.Fatalf("unexpected reason %q", reason)
+			}
+		})
+	}
+}
+
+func TestGuardAIRecommendation(t *testing.T) {
+	tests := []struct {
+		name      string
+		proposed  Recommendation
+		heuristic Recommendation
+		trademark int
+		vice      int
+		expected  Recommendation
+	}{
+		{"fanciful match kept from allow", "ALLOW", "BLOCK", 5, 0, "BLOCK"},
+		{"laxer heuristic floors at review", "ALLOW_WITH_CAUTION", "ALLOW", 0, 5, "REVIEW"},
+		{"review is allowed", "REVIEW", "BLOCK", 5, 0, "REVIEW"},
+		{"below floor is free", "ALLOW", "BLOCK", 4, 4, "ALLOW"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, reason := GuardAIRecommendation(tc.proposed, tc.heuristic, tc.trademark, tc.vice, DefaultAIGuardrailScore)
+			if got != tc.expected || (reason != "") != (got != tc.proposed) {
+				t.Fatalf("expected %s got %s (reason %q)", tc.expected, got, reason)
+			}
+		})
+	}
+	if got, _ := GuardAIRecommendation("ALLOW", "BLOCK", 5, 5, -1); got != "ALLOW" {
+		t.Fatalf("disabled guardrail changed the recommendation to %s", got)
+	}
+	if score, reason := GuardAIScore("trademark", 5, 1, DefaultAIGuardrailScore); score != 5 || reason == "" {
+		t.Fatalf("expected the AI trademark score to be rejected, got %d %q", score, reason)
+	}
+	if score, _ := GuardAIScore("vice", 3, 1, DefaultAIGuardrailScore); score != 1 {
+		t.Fatalf("expected the AI vice score below the guardrail to apply, got %d", score)
+	}
+}
+
+func TestCommercialOverridePolicy(t *testing.T) {
+	policy := DefaultCommercialOverridePolicy()
+
+	tests := []struct {
+		name       string
+		tr         int
+		vice       int
+		similarity float64
+		rec        Recommendation
+		eligible   bool
+		expected   Recommendation
+	}{
+		{"block to review", 3, 2, 0.9, "BLOCK", true, "REVIEW"},
+		{"review to caution", 2, 0, 0.8, "REVIEW", true, "ALLOW_WITH_CAUTION"},
+		{"caution unchanged", 1, 0, 0.95, "ALLOW_WITH_CAUTION", true, "ALLOW_WITH_CAUTION"},
+		{"allow unchanged", 0, 0, 0.95, "ALLOW", true, "ALLOW"},
+		{"vice too high", 0, 3, 0.95, "REVIEW", false, "REVIEW"},
+		{"trademark too high", 4, 0, 0.95, "BLOCK", false, "BLOCK"},
+		{"similarity too low", 0, 0, 0.79, "REVIEW", false, "REVIEW"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			eligible := policy.Eligible(tc.tr, tc.vice, tc.similarity)
+			if eligible != tc.eligible {
+				t.Fatalf("expected eligible=%v got %v", tc.eligible, eligible)
+			}
+			rec := tc.rec
+			if eligible {
+				rec = policy.Apply(rec)
+			}
+			if rec != tc.expected {
+				t.Fatalf("expected %s got %s", tc.expected, rec)
+			}
+		})
+	}
+}
+
+func TestLoadCommercialOverridePolicy(t *testing.T) {
+	path := tempJSON(t, map[string]any{
+		"max_vice_score": 3,
+		"remap":          map[string]string{"block": "allow_with_caution"},
+	})
+	policy, err := LoadCommercialOverridePolicy(path)
+	if err != nil {
+		t.Fatalf("load policy: %v", err)
+	}
+	if policy.MaxViceScore != 3 || policy.MinPrice != 10000 {
+		t.Fatalf("unexpected thresholds %+v", policy)
+	}
+	if got := policy.Apply("BLOCK"); got != "ALLOW_WITH_CAUTION" {
+		t.Fatalf("expected custom BLOCK remap, got %s", got)
+	}
+	if got := policy.Apply("REVIEW"); got != "ALLOW_WITH_CAUTION" {
+		t.Fatalf("expected default REVIEW remap, got %s", got)
+	}
+
+	path = tempJSON(t, map[string]any{"remap": map[string]string{"review": "REVIEW"}})
+	policy, err = LoadCommercialOverridePolicy(path)
+	if err != nil {
+		t.Fatalf("load policy: %v", err)
+	}
+	if _, ok := policy.Remap[RecommendationReview]; ok || policy.Apply("BLOCK") != "REVIEW" {
+		t.Fatalf("expected REVIEW's default remap removed and BLOCK's kept, got %v", policy.Remap)
+	}
+
+	bad := tempJSON(t, map[string]any{"remap": map[string]string{"BLOCK": "MAYBE"}})
+	if _, err := LoadCommercialOverridePolicy(bad); err == nil {
+		t.Fatal("expected error for unknown recommendation")
+	}
+	stricter := tempJSON(t, map[string]any{"remap": map[string]string{"ALLOW": "BLOCK"}})
+	if _, err := LoadCommercialOverridePolicy(stricter); err == nil {
+		t.Fatal("expected error for a remap that raises the recommendation")
+	}
+}
+
+func TestParseRecommendation(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected Recommendation
+		ok       bool
+	}{
+		{"BLOCK", RecommendationBlock, true},
+		{" review ", RecommendationReview, true},
+		{"allow_with_caution", RecommendationAllowWithCaution, true},
+		{"ALLOWWITHCAUTION", RecommendationAllowWithCaution, true},
+		{"allow with caution", RecommendationAllowWithCaution, true},
+		{"Allow", RecommendationAllow, true},
+		{"MAYBE", "", false},
+		{"", "", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := ParseRecommendation(tc.in)
+			if (err == nil) != tc.ok {
+				t.Fatalf("unexpected error state: %v", err)
+			}
+			if got != tc.expected {
+				t.Fatalf("expected %q got %q", tc.expected, got)
+			}
+			if tc.ok && !got.Valid() {
+				t.Fatalf("%q should be valid", got)
+			}
+		})
+	}
+}
+
+func TestCommercialOverrideTiers(t *testing.T) {
+	policy := DefaultCommercialOverridePolicy()
+
+	tests := []struct {
+		name     string
+		tr       int
+		price    float64
+		tier     string
+		eligible bool
+	}{
+		{"standard sale", 3, 20000, StandardCommercialTier, true},
+		{"sale below min_price", 0, 9999, StandardCommercialTier, false},
+		{"standard sale at trademark 4", 4, 20000, StandardCommercialTier, false},
+		{"premium sale at trademark 4", 4, 500000, "premium", true},
+		{"premium sale at trademark 5", 5, 750000, "premium", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tier, ok := policy.EligibleAt(tc.tr, 0, 0.9, tc.price)
+			if tier.Name != tc.tier || ok != tc.eligible {
+				t.Fatalf("expected %s/%v got %s/%v", tc.tier, tc.eligible, tier.Name, ok)
+			}
+		})
+	}
+
+	path := tempJSON(t, map[string]any{"tiers": []map[string]any{{"name": "", "min_price": 50000}}})
+	if _, err := LoadCommercialOverridePolicy(path); err == nil {
+		t.Fatal("expected error for unnamed tier")
+	}
+}
+
+func TestCommercialOverridePolicyWithMinPrice(t *testing.T) {
+	raised, err := DefaultCommercialOverridePolicy().WithMinPrice(750000)
+	if err != nil {
+		t.Fatalf("expected the default premium tier to be dropped, got %v", err)
+	}
+	if raised.MinPrice != 750000 || len(raised.Tiers) != 0 {
+		t.Fatalf("expected min_price 750000 without tiers, got %+v", raised)
+	}
+	if len(DefaultCommercialOverridePolicy().Tiers) != 1 {
+		t.Fatal("expected the default policy to keep its tier")
+	}
+
+	lowered, err := DefaultCommercialOverridePolicy().WithMinPrice(5000)
+	if err != nil || len(lowered.Tiers) != 1 {
+		t.Fatalf("expected a lower min_price to keep the premium tier, got %+v (%v)", lowered.Tiers, err)
+	}
+
+	configured := DefaultCommercialOverridePolicy()
+	configured.Tiers = []CommercialTier{{Name: "gold", MinPrice: 100000, MaxViceScore: 2, MaxTrademarkScore: 4}}
+	if _, err := configured.WithMinPrice(200000); err == nil {
+		t.Fatal("expected a configured tier below the new min_price to fail validation")
+	}
+}
@@ -0,0 +1,84 @@
+package scoring
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ownerFloorScore is the minimum trademark score for marks held by a high-value owner; it maps to
+// REVIEW in CombineRecommendation.
+const ownerFloorScore = 3
+
+// OwnerWatchlist holds rights holders (e.g. major brands) whose marks always warrant review.
+type OwnerWatchlist struct {
+	names []string
+}
+
+// NewOwnerWatchlist builds a watchlist from owner names. Matching ignores case, punctuation, and
+// corporate suffixes that follow the configured name, so "Apple" matches "APPLE INC.".
+func NewOwnerWatchlist(names []string) *OwnerWatchlist {
+	w := &OwnerWatchlist{}
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		normalized := normalizeOwner(name)
+		if normalized == "" {
+			continue
+		}
+		if _, ok := seen[normalized]; ok {
+			continue
+		}
+		seen[normalized] = struct{}{}
+		w.names = append(w.names, normalized)
+	}
+	return w
+}
+
+// Len reports the number of watched owners.
+func (w *OwnerWatchlist) Len() int {
+	if w == nil {
+		return 0
+	}
+	return len(w.names)
+}
+
+// Match returns the watched name contained in owner as a whole-word sequence, or "".
+func (w *OwnerWatchlist) Match(owner string) string {
+	if w.Len() == 0 {
+		return ""
+	}
+	padded := " " + normalizeOwner(owner) + " "
+	if padded == "  " {
+		return ""
+	}
+	for _, name := range w.names {
+		if strings.Contains(padded, " "+name+" ") {
+			return name
+		}
+	}
+	return ""
+}
+
+// Apply raises the score of a matched mark held by a watched owner to at least REVIEW and returns
+// a reason when it changed the result.
+func (w *OwnerWatchlist) Apply(result TrademarkResult) (TrademarkResult, string) {
+	if result.MatchedTrademark == "" || result.Score >= ownerFloorScore {
+		return result, ""
+	}
+	name := w.Match(result.Owner)
+	if name == "" {
+		return result, ""
+	}
+	reason := fmt.Sprintf("mark %s is held by high-value owner %s; trademark score raised from %d to %d",
+		result.MatchedTrademark, result.Owner, result.Score, ownerFloorScore)
+	result.Score = ownerFloorScore
+	return result, reason
+}
+
+// normalizeOwner lowercases an owner name and collapses everything but letters and digits into
+// single spaces.
+func normalizeOwner(value string) string {
+	fields := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9')
+	})
+	return strings.Join(fields, " ")
+}
@@ -6,6 +6,14 @@ import (
 	"domain-risk-eval/backend/internal/store"
 )
 
+// PopularStore reads and rebuilds the persisted popular mark table; *store.Database implements
+// it.
+type PopularStore interface {
+	ListPopularMarks(limit int) ([]store.PopularMark, error)
+	PopularMarks(limit, minCount int) ([]store.PopularMark, error)
+	ReplacePopularMarks(rows []store.PopularMark) error
+}
+
 var (
 	popularMu     sync.RWMutex
 	popularTokens = defaultPopularTokens()
@@ -41,8 +49,15 @@ func IsPopularToken(token string) bool {
 	return ok
 }
 
+// PopularTokenCount reports the size of the in-memory popular token set, defaults included.
+func PopularTokenCount() int {
+	popularMu.RLock()
+	defer popularMu.RUnlock()
+	return len(popularTokens)
+}
+
 // LoadPopularTokensFromStore hydrates the in-memory set from the persisted popular mark table.
-func LoadPopularTokensFromStore(db *store.Database, limit int) (int, error) {
+func LoadPopularTokensFromStore(db PopularStore, limit int) (int, error) {
 	rows, err := db.ListPopularMarks(limit)
 	if err != nil {
 		return 0, err
@@ -61,7 +76,7 @@ func LoadPopularTokensFromStore(db *store.Database, limit int) (int, error) {
 
 // LoadPopularTokens aggregates popular marks on the fly and refreshes both the persisted table
 // and the in-memory set.
-func LoadPopularTokens(db *store.Database, limit, minCount int) (int, error) {
+func LoadPopularTokens(db PopularStore, limit, minCount int) (int, error) {
 	popular, err := db.PopularMarks(limit, minCount)
 	if err != nil {
 		return 0, err
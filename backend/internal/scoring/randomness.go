@@ -0,0 +1,70 @@
+package scoring
+
+import (
+	"math"
+	"strings"
+)
+
+// randomnessMinLength skips short labels, whose entropy and bigram statistics are too noisy.
+const randomnessMinLength = 6
+
+// commonBigrams holds frequent English letter pairs; labels made mostly of other pairs read as
+// machine-generated.
+var commonBigrams = func() map[string]struct{} {
+	list := strings.Fields(`th he in er an re on at en nd ti es or te of ed is it al ar st to nt ng
+		se ha as ou io le ve co me de hi ri ro ic ne ea ra ce li ch ll be ma si om ur ca el ta la ns
+		di fo ho pe ec pr no ct us ac ot il tr ly nc et ut ss so rs un lo wa ge ie wh ee wi em ad ol
+		rt po we na ul ni ts mo ow pa im mi ai sh ir su id os iv ia am fi ci vi pl ig tu ev ld ry mp
+		fe bl ab gh ty op wo sa ay ex ke fr oo av ag if ap gr od bo sp rd do uc bu ei ov by rm ep tt
+		oc fa ef cu rn sc gi da yo cr cl du ga qu ue ff ba ey ls va um pp ua up lu go ht ru ug ds lt
+		pi rc rr eg au ck ew mu br bi pt ak pu ui rg ib tl ny ki rk ys ob mm fu ph og ms ye ud mb ip
+		ub oi rl gu dr hr cc tw ft wn nu af hu nn eo vo rv nf xp gn sm fl iz ok nl my gl aw ju oa sy
+		eb ks ze za zo ax ox ix xi ya ka ko ku ek`)
+	set := make(map[string]struct{}, len(list))
+	for _, bigram := range list {
+		set[bigram] = struct{}{}
+	}
+	return set
+}()
+
+// RandomnessResult describes how machine-generated a label looks. It is an advisory signal and
+// does not feed the trademark or vice scores.
+type RandomnessResult struct {
+	Entropy         float64 `json:"entropy"`
+	RareBigramRatio float64 `json:"rare_bigram_ratio"`
+	Random          bool    `json:"random"`
+}
+
+// DetectRandomness scores a brand token by Shannon entropy and the share of bigrams that are
+// uncommon in English. Pairs involving digits count as uncommon.
+func DetectRandomness(token string) RandomnessResult {
+	label := sanitizeLabel(token)
+	if len(label) < randomnessMinLength {
+		return RandomnessResult{}
+	}
+
+	counts := make(map[rune]int)
+	for _, r := range label {
+		counts[r]++
+	}
+	entropy := 0.0
+	n := float64(len(label))
+	for _, count := range counts {
+		p := float64(count) / n
+		entropy -= p * math.Log2(p)
+	}
+
+	rare := 0
+	for i := 0; i+1 < len(label); i++ {
+		if _, ok := commonBigrams[label[i:i+2]]; !ok {
+			rare++
+		}
+	}
+	ratio := float64(rare) / float64(len(label)-1)
+
+	return RandomnessResult{
+		Entropy:         math.Round(entropy*100) / 100,
+		RareBigramRatio: math.Round(ratio*100) / 100,
+		Random:          ratio >= 0.6 || (ratio >= 0.45 && entropy >= 3.0),
+	}
+}
@@ -0,0 +1,31 @@
+package scoring
+
+import "testing"
+
+func TestDetectRandomness(t *testing.T) {
+	tests := []struct {
+		token  string
+		random bool
+	}{
+		{"xk4j9qz", true},
+		{"qwzxkvbp", true},
+		{"h7f2kq9xw", true},
+		{"zgvbqmtpl", true},
+		{"google", false},
+		{"facebook", false},
+		{"insurance", false},
+		{"bestcoffee", false},
+		{"thunderbolt", false},
+		{"shopnow", false},
+		{"abc", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.token, func(t *testing.T) {
+			result := DetectRandomness(tc.token)
+			if result.Random != tc.random {
+				t.Fatalf("expected random=%v got %+v", tc.random, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package scoring
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Recommendation is an overall decision bucket for a domain.
+type Recommendation string
+
+// Recommendation buckets, from least to most severe.
+const (
+	RecommendationAllow            Recommendation = "ALLOW"
+	RecommendationAllowWithCaution Recommendation = "ALLOW_WITH_CAUTION"
+	RecommendationReview           Recommendation = "REVIEW"
+	RecommendationBlock            Recommendation = "BLOCK"
+)
+
+// Recommendations lists every valid recommendation, from least to most severe. Adding a bucket
+// here is enough for parsing and validation to accept it.
+var Recommendations = []Recommendation{
+	RecommendationAllow,
+	RecommendationAllowWithCaution,
+	RecommendationReview,
+	RecommendationBlock,
+}
+
+// ParseRecommendation normalizes a recommendation read from config, the API, or a model
+// response. Case, surrounding space, and space or hyphen separators are ignored, and the
+// separator-less form (e.g. "ALLOWWITHCAUTION") is accepted. Unknown values are an error.
+func ParseRecommendation(value string) (Recommendation, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(value))
+	normalized = strings.NewReplacer(" ", "_", "-", "_").Replace(normalized)
+	compact := strings.ReplaceAll(normalized, "_", "")
+	for _, rec := range Recommendations {
+		if normalized == string(rec) || compact == strings.ReplaceAll(string(rec), "_", "") {
+			return rec, nil
+		}
+	}
+	return "", fmt.Errorf("unknown recommendation %q", value)
+}
+
+// Valid reports whether r is one of Recommendations.
+func (r Recommendation) Valid() bool {
+	for _, rec := range Recommendations {
+		if r == rec {
+			return true
+		}
+	}
+	return false
+}
+
+// Severity returns r's position in Recommendations, higher being more severe, or -1 when r is
+// not a valid recommendation.
+func (r Recommendation) Severity() int {
+	for i, rec := range Recommendations {
+		if r == rec {
+			return i
+		}
+	}
+	return -1
+}
+
+// RecommendationNames returns the valid recommendations as strings, e.g. for prompts and errors.
+func RecommendationNames() []string {
+	names := make([]string, 0, len(Recommendations))
+	for _, rec := range Recommendations {
+		names = append(names, string(rec))
+	}
+	return names
+}
@@ -0,0 +1,71 @@
+package scoring
+
+import (
+	"strings"
+
+	"domain-risk-eval/backend/internal/match"
+)
+
+// minSubdomainBrandLength skips short labels such as "m" or "us" that match marks by accident.
+const minSubdomainBrandLength = 3
+
+// SubdomainBrand reports a fanciful or popular mark found in a subdomain label of a registrable
+// domain that does not itself carry the mark, e.g. "paypal" in login-paypal.attacker.com.
+type SubdomainBrand struct {
+	Label       string          `json:"label"`
+	Token       string          `json:"token"`
+	Registrable string          `json:"registrable"`
+	Trademark   TrademarkResult `json:"trademark"`
+}
+
+// ScoreSubdomains looks up each subdomain label, and each hyphen-separated token of it, in the
+// trademark index. Only fanciful and popular hits on non-dictionary tokens count, and only when
+// the core label of the registrable domain does not contain the token. The highest-scoring hit
+// is returned.
+func (s *TrademarkScorer) ScoreSubdomains(profile match.DomainProfile) (SubdomainBrand, bool) {
+	if s == nil || s.index == nil || len(profile.Subdomains) == 0 {
+		return SubdomainBrand{}, false
+	}
+	core := sanitizeLabel(profile.Core)
+
+	var best SubdomainBrand
+	found := false
+	for _, label := range profile.Subdomains {
+		for _, token := range subdomainTokens(label) {
+			if len(token) < minSubdomainBrandLength || isCommonWord(token) || strings.Contains(core, token) {
+				continue
+			}
+			entry := s.index.lookupExact(token)
+			if entry == nil {
+				continue
+			}
+			result := s.scoreEntry(token, entry)
+			if result.Type != "fanciful" && result.Type != "popular" {
+				continue
+			}
+			result.Source = s.index.source(entry)
+			if !found || result.Score > best.Trademark.Score {
+				best = SubdomainBrand{Label: label, Token: token, Registrable: profile.Registrable, Trademark: result}
+				found = true
+			}
+		}
+	}
+	return best, found
+}
+
+// subdomainTokens returns the sanitized label followed by its separator-delimited parts.
+func subdomainTokens(label string) []string {
+	tokens := []string{sanitizeLabel(label)}
+	parts := strings.FieldsFunc(label, func(r rune) bool {
+		return r == '-' || r == '_'
+	})
+	if len(parts) < 2 {
+		return tokens
+	}
+	for _, part := range parts {
+		if token := sanitizeLabel(part); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,101 @@
+package scoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TLD risk levels.
+const (
+	TLDRiskHigh     = "high"
+	TLDRiskElevated = "elevated"
+)
+
+// TLDRiskTable lists TLDs with elevated abuse rates. High-risk TLDs raise ALLOW to
+// ALLOW_WITH_CAUTION and ALLOW_WITH_CAUTION to REVIEW; elevated TLDs only raise ALLOW to
+// ALLOW_WITH_CAUTION. REVIEW and BLOCK are never changed.
+type TLDRiskTable struct {
+	High     []string `json:"high"`
+	Elevated []string `json:"elevated"`
+
+	levels map[string]string
+}
+
+// DefaultTLDRiskTable returns the built-in table of commonly abused TLDs.
+func DefaultTLDRiskTable() *TLDRiskTable {
+	table := &TLDRiskTable{
+		High:     []string{"zip", "mov", "top", "xyz", "tk", "ml", "ga", "cf", "gq", "click", "link", "rest", "cam", "icu", "buzz", "loan"},
+		Elevated: []string{"online", "site", "shop", "live", "club", "info", "biz", "cc", "ws", "su"},
+	}
+	table.index()
+	return table
+}
+
+// LoadTLDRiskTable reads a JSON object with "high" and "elevated" TLD arrays from disk.
+func LoadTLDRiskTable(path string) (*TLDRiskTable, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read tld risk table: %w", err)
+	}
+	var table TLDRiskTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("unmarshal tld risk table: %w", err)
+	}
+	table.index()
+	return &table, nil
+}
+
+func (t *TLDRiskTable) index() {
+	t.levels = make(map[string]string, len(t.High)+len(t.Elevated))
+	for _, tld := range t.Elevated {
+		if key := normalizeTLD(tld); key != "" {
+			t.levels[key] = TLDRiskElevated
+		}
+	}
+	for _, tld := range t.High {
+		if key := normalizeTLD(tld); key != "" {
+			t.levels[key] = TLDRiskHigh
+		}
+	}
+}
+
+// Len reports the number of TLDs in the table.
+func (t *TLDRiskTable) Len() int {
+	if t == nil {
+		return 0
+	}
+	return len(t.levels)
+}
+
+// Level returns the risk level for the TLD, or an empty string when it is not listed.
+func (t *TLDRiskTable) Level(tld string) string {
+	if t == nil {
+		return ""
+	}
+	return t.levels[normalizeTLD(tld)]
+}
+
+// Adjust raises the recommendation for risky TLDs and returns a reason when it changed.
+func (t *TLDRiskTable) Adjust(tld string, overall OverallResult) (OverallResult, string) {
+	level := t.Level(tld)
+	if level == "" {
+		return overall, ""
+	}
+	before := overall.Recommendation
+	switch {
+	case before == RecommendationAllow:
+		overall.Recommendation = RecommendationAllowWithCaution
+	case before == RecommendationAllowWithCaution && level == TLDRiskHigh:
+		overall.Recommendation = RecommendationReview
+	default:
+		return overall, ""
+	}
+	return overall, fmt.Sprintf("%s-risk TLD .%s raised %s to %s", level, normalizeTLD(tld), before, overall.Recommendation)
+}
+
+func normalizeTLD(tld string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(tld)), ".")
+}
@@ -4,44 +4,119 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 
 	"domain-risk-eval/backend/internal/match"
 	"domain-risk-eval/backend/internal/store"
 )
 
+// Trademark match sources record which lookup produced a TrademarkResult.
+const (
+	MatchSourceSeed         = "seed"
+	MatchSourceIndex        = "index"
+	MatchSourceUSPTOExact   = "uspto_exact"
+	MatchSourceUSPTOSimilar = "uspto_similar"
+	MatchSourceCustom       = "custom"
+)
+
+// CustomMarkSerialPrefix starts the synthetic serial of a mark named in a request rather than
+// read from the store; such marks have no USPTO record, so results omit their serial.
+const CustomMarkSerialPrefix = "custom:"
+
+// TrademarkTypeEmbedded marks a result where a brand was found as a component of the SLD rather
+// than the whole SLD.
+const TrademarkTypeEmbedded = "embedded"
+
+// TrademarkTypeSimilarOnly marks a result where a live USPTO search found marks similar to the SLD
+// but none matching it, separating such domains from ones with no nearby marks at all.
+const TrademarkTypeSimilarOnly = "similar_only"
+
+// minEmbeddedTokenLength skips short components such as "my" or "go" that match marks by accident.
+const minEmbeddedTokenLength = 4
+
 // TrademarkResult captures the outcome of a trademark evaluation.
 type TrademarkResult struct {
-	Score            int     `json:"score"`
-	Type             string  `json:"type"`
-	MatchedTrademark string  `json:"matched_trademark"`
-	Confidence       float64 `json:"confidence"`
+	Score            int    `json:"score"`
+	Type             string `json:"type"`
+	MatchedTrademark string `json:"matched_trademark"`
+	Owner            string `json:"owner,omitempty"`
+	OwnerCountry     string `json:"owner_country,omitempty"`
+	// Serial and Registration identify the matched USPTO record for lookups in TSDR; either may
+	// be empty, e.g. for pending applications or request-only custom marks.
+	Serial       string  `json:"serial,omitempty"`
+	Registration string  `json:"registration,omitempty"`
+	Confidence   float64 `json:"confidence"`
+	// Source is one of the MatchSource constants, or empty when nothing matched.
+	Source string `json:"source,omitempty"`
 }
 
-// TrademarkScorer evaluates domains against the trademark index.
+// TrademarkScorer evaluates domains against the trademark index. It is not modified after
+// construction and is safe for concurrent use.
 type TrademarkScorer struct {
-	index *trademarkIndex
+	index    *trademarkIndex
+	scores   TrademarkScoreConfig
+	embedded bool
 }
 
-// NewTrademarkScorer builds an index from the provided marks with seed overrides.
-func NewTrademarkScorer(marks []store.Mark, seedPath string) (*TrademarkScorer, error) {
+// NewTrademarkScorer builds an index from the provided marks with seed overrides. A nil scores
+// config uses DefaultTrademarkScoreConfig.
+func NewTrademarkScorer(marks []store.Mark, seedPath string, scores *TrademarkScoreConfig) (*TrademarkScorer, error) {
 	seeds, err := loadSeeds(seedPath)
 	if err != nil {
 		return nil, err
 	}
+	cfg := DefaultTrademarkScoreConfig()
+	if scores != nil {
+		if err := scores.Validate(); err != nil {
+			return nil, err
+		}
+		cfg = *scores
+	}
 	idx := buildTrademarkIndex(marks, seeds)
-	return &TrademarkScorer{index: idx}, nil
+	return &TrademarkScorer{index: idx, scores: cfg}, nil
+}
+
+// WithEmbeddedMatches returns a copy of the scorer, sharing its index, that also checks each
+// token and alternate split of the SLD when the SLD itself has no exact match. It catches
+// brand-plus-generic squats such as applestore.com at the cost of more false positives on
+// generic tokens.
+func (s *TrademarkScorer) WithEmbeddedMatches(enabled bool) *TrademarkScorer {
+	if s == nil {
+		return nil
+	}
+	clone := *s
+	clone.embedded = enabled
+	return &clone
+}
+
+// WithCustomMarks returns a copy of the scorer whose index layers marks over the shared one, for
+// client-specific conflict checks within a single job. Custom marks are looked up first and
+// classified like any other mark, so callers mark the ones that must score as a conflict
+// IsFanciful; the shared index is not modified and stays safe to use from other jobs.
+func (s *TrademarkScorer) WithCustomMarks(marks []store.Mark) *TrademarkScorer {
+	if s == nil || len(marks) == 0 {
+		return s
+	}
+	idx := &trademarkIndex{}
+	if s.index != nil {
+		*idx = *s.index
+	}
+	idx.custom = buildExactMap(marks)
+	clone := *s
+	clone.index = idx
+	return &clone
 }
 
 // Score computes the trademark risk score for the provided domain profile.
 // Only fanciful exact matches between the domain's second-level label (SLD) and stored marks
 // are considered a high-risk trademark hit. Popular brands or public figures trigger a medium
-// review score, while generic words return a neutral score.
+// review score, while generic words return a neutral score. With embedded matching enabled, a
+// fanciful or popular mark found as one component of the SLD returns a reduced-confidence
+// TrademarkTypeEmbedded result.
 func (s *TrademarkScorer) Score(profile match.DomainProfile) TrademarkResult {
 	if s == nil || s.index == nil {
 		return TrademarkResult{Score: 0, Type: "none", Confidence: 0.2}
@@ -53,37 +128,104 @@ func (s *TrademarkScorer) Score(profile match.DomainProfile) TrademarkResult {
 	}
 
 	if entry := s.index.lookupExact(sld); entry != nil {
-		markType := s.index.classify(entry)
-		isCommon := isCommonWord(sld)
-		switch markType {
-		case "fanciful":
-			if isCommon {
-				return TrademarkResult{Score: 2, Type: "generic", MatchedTrademark: entry.Mark, Confidence: 0.6}
-			}
-			if IsPopularToken(sld) {
-				return TrademarkResult{Score: 3, Type: "popular", MatchedTrademark: entry.Mark, Confidence: 0.9}
-			}
-			return TrademarkResult{Score: 5, Type: markType, MatchedTrademark: entry.Mark, Confidence: 1.0}
-		case "popular":
-			if isCommon {
-				return TrademarkResult{Score: 2, Type: markType, MatchedTrademark: entry.Mark, Confidence: 0.75}
-			}
-			return TrademarkResult{Score: 3, Type: markType, MatchedTrademark: entry.Mark, Confidence: 0.9}
-		default:
-			if isCommon {
-				return TrademarkResult{Score: 2, Type: "generic", MatchedTrademark: entry.Mark, Confidence: 0.6}
-			}
-			return TrademarkResult{Score: 0, Type: markType, MatchedTrademark: entry.Mark, Confidence: 0.4}
+		result := s.scoreEntry(sld, entry)
+		result.Source = s.index.source(entry)
+		return result
+	}
+
+	if s.embedded {
+		if result, ok := s.scoreEmbedded(sld, profile); ok {
+			return result
 		}
 	}
 
 	return TrademarkResult{Score: 0, Type: "none", Confidence: 0.2}
 }
 
-// trademarkIndex stores precomputed mark lookups.
+// scoreEmbedded looks up the profile's tokens and alternate splits in the exact index. Only
+// fanciful and popular hits on non-dictionary components count; the highest-scoring mark is
+// rescored with the Embedded rule.
+func (s *TrademarkScorer) scoreEmbedded(sld string, profile match.DomainProfile) (TrademarkResult, bool) {
+	var best TrademarkResult
+	var bestEntry *store.Mark
+	seen := make(map[string]struct{})
+	candidates := append(append([]string{}, profile.Tokens...), profile.AltSplits...)
+	for _, candidate := range candidates {
+		token := sanitizeLabel(candidate)
+		if token == sld || len(token) < minEmbeddedTokenLength || isCommonWord(token) {
+			continue
+		}
+		if _, ok := seen[token]; ok {
+			continue
+		}
+		seen[token] = struct{}{}
+		entry := s.index.lookupExact(token)
+		if entry == nil {
+			continue
+		}
+		result := s.scoreEntry(token, entry)
+		if result.Type != "fanciful" && result.Type != "popular" {
+			continue
+		}
+		if bestEntry == nil || result.Score > best.Score {
+			best = result
+			bestEntry = entry
+		}
+	}
+	if bestEntry == nil {
+		return TrademarkResult{}, false
+	}
+	best.Type = TrademarkTypeEmbedded
+	best.Score = s.scores.Embedded.Score
+	best.Confidence = s.scores.Embedded.Confidence
+	best.Source = s.index.source(bestEntry)
+	return best, true
+}
+
+// markSerial returns the USPTO serial of entry, or "" for a request-only custom mark.
+func markSerial(entry *store.Mark) string {
+	if strings.HasPrefix(entry.Serial, CustomMarkSerialPrefix) {
+		return ""
+	}
+	return entry.Serial
+}
+
+// scoreEntry classifies an exact index hit for the SLD.
+func (s *TrademarkScorer) scoreEntry(sld string, entry *store.Mark) TrademarkResult {
+	markType := s.index.classify(entry)
+	isCommon := isCommonWord(sld)
+	result := func(rule TrademarkScoreRule, resultType string) TrademarkResult {
+		return TrademarkResult{Score: rule.Score, Type: resultType, MatchedTrademark: entry.Mark, Owner: entry.Owner, OwnerCountry: entry.OwnerCountry(),
+			Serial: markSerial(entry), Registration: entry.Registration, Confidence: rule.Confidence}
+	}
+	switch markType {
+	case "fanciful":
+		if isCommon {
+			return result(s.scores.FancifulCommon, "generic")
+		}
+		if IsPopularToken(sld) {
+			return result(s.scores.FancifulPopular, "popular")
+		}
+		return result(s.scores.Fanciful, markType)
+	case "popular":
+		if isCommon {
+			return result(s.scores.PopularCommon, markType)
+		}
+		return result(s.scores.Popular, markType)
+	default:
+		if isCommon {
+			return result(s.scores.GenericCommon, "generic")
+		}
+		return result(s.scores.Generic, markType)
+	}
+}
+
+// trademarkIndex stores precomputed mark lookups. custom, when set, holds per-job marks that take
+// precedence over exact.
 type trademarkIndex struct {
-	exact map[string]*store.Mark
-	seeds map[string]struct{}
+	exact  map[string]*store.Mark
+	seeds  map[string]struct{}
+	custom map[string]*store.Mark
 }
 
 func buildTrademarkIndex(marks []store.Mark, seeds map[string]struct{}) *trademarkIndex {
@@ -97,9 +239,40 @@ func (idx *trademarkIndex) lookupExact(token string) *store.Mark {
 	if idx == nil {
 		return nil
 	}
+	if mark, ok := idx.custom[token]; ok {
+		return mark
+	}
 	return idx.exact[token]
 }
 
+// isCustom reports whether the mark was layered over the shared index by WithCustomMarks.
+func (idx *trademarkIndex) isCustom(mark *store.Mark) bool {
+	if idx == nil || mark == nil || len(idx.custom) == 0 {
+		return false
+	}
+	return idx.custom[sanitizeLabel(mark.MarkNoSpaces)] == mark
+}
+
+// isSeed reports whether the mark is forced fanciful by the seed list.
+func (idx *trademarkIndex) isSeed(mark *store.Mark) bool {
+	if idx == nil || mark == nil {
+		return false
+	}
+	_, ok := idx.seeds[sanitizeLabel(mark.MarkNoSpaces)]
+	return ok
+}
+
+// source returns the MatchSource constant describing where an index hit came from.
+func (idx *trademarkIndex) source(mark *store.Mark) string {
+	switch {
+	case idx.isCustom(mark):
+		return MatchSourceCustom
+	case idx.isSeed(mark):
+		return MatchSourceSeed
+	}
+	return MatchSourceIndex
+}
+
 func (idx *trademarkIndex) classify(mark *store.Mark) string {
 	if idx == nil || mark == nil {
 		return "generic"
@@ -137,7 +310,7 @@ func loadSeeds(path string) (map[string]struct{}, error) {
 	if path == "" {
 		return map[string]struct{}{}, nil
 	}
-	data, err := os.ReadFile(filepath.Clean(path))
+	data, err := readOrDefault(path, defaultSeedsJSON, "fanciful seed")
 	if err != nil {
 		return nil, fmt.Errorf("read seeds: %w", err)
 	}
@@ -155,17 +328,28 @@ func loadSeeds(path string) (map[string]struct{}, error) {
 	return set, nil
 }
 
-// LoadMarks loads marks from the database with an optional limit.
-func LoadMarks(db *store.Database, limit int) ([]store.Mark, error) {
+// MarkSource gives LoadMarks the handle holding the marks and popular_marks tables;
+// *store.Database implements it.
+type MarkSource interface {
+	MarksGORM() *gorm.DB
+}
+
+// LoadMarks loads marks from the database with an optional limit. With liveOnly set, marks whose
+// status is dead (see DeadMarkStatus) are excluded in the query, before the limit, so abandoned
+// or cancelled marks neither reach the exact index nor take the places of live ones.
+func LoadMarks(db MarkSource, limit int, liveOnly bool) ([]store.Mark, error) {
 	if db == nil {
 		return nil, errors.New("db is nil")
 	}
 	var marks []store.Mark
 	start := time.Now()
-	query := db.GORM().Table("popular_marks").
+	query := db.MarksGORM().Table("popular_marks").
 		Select("marks.*").
 		Joins("JOIN marks ON marks.mark_no_spaces = popular_marks.normalized").
 		Order("popular_marks.total DESC, marks.updated_at DESC")
+	if liveOnly {
+		query = query.Where(liveMarkSQL)
+	}
 	if limit > 0 {
 		query = query.Limit(limit)
 	}
@@ -184,6 +368,7 @@ func LoadMarks(db *store.Database, limit int) ([]store.Mark, error) {
 	}
 	logrus.WithFields(logrus.Fields{
 		"marks_returned": len(marks),
+		"live_only":      liveOnly,
 		"marks_limit":    limit,
 		"duration":       time.Since(start),
 	}).Info("queried popular marks for scoring")
@@ -203,7 +388,7 @@ func extractSLD(profile match.DomainProfile) string {
 }
 
 func sanitizeLabel(label string) string {
-	label = strings.ToLower(strings.TrimSpace(label))
+	label = match.Fold(strings.TrimSpace(label))
 	if label == "" {
 		return ""
 	}
@@ -0,0 +1,83 @@
+package scoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// TrademarkScoreRule sets the score and confidence assigned to one exact-match outcome.
+type TrademarkScoreRule struct {
+	Score      int     `json:"score"`
+	Confidence float64 `json:"confidence"`
+}
+
+// TrademarkScoreConfig maps each (mark type, common word, popular token) outcome of an exact SLD
+// match to a score. Fanciful marks whose SLD is also popular are scored as popular. Embedded
+// applies to a fanciful or popular mark found as one component of the SLD when embedded matching
+// is enabled.
+type TrademarkScoreConfig struct {
+	Fanciful        TrademarkScoreRule `json:"fanciful"`
+	FancifulCommon  TrademarkScoreRule `json:"fanciful_common"`
+	FancifulPopular TrademarkScoreRule `json:"fanciful_popular"`
+	Popular         TrademarkScoreRule `json:"popular"`
+	PopularCommon   TrademarkScoreRule `json:"popular_common"`
+	Generic         TrademarkScoreRule `json:"generic"`
+	GenericCommon   TrademarkScoreRule `json:"generic_common"`
+	Embedded        TrademarkScoreRule `json:"embedded"`
+}
+
+// DefaultTrademarkScoreConfig returns the built-in trademark score mapping.
+func DefaultTrademarkScoreConfig() TrademarkScoreConfig {
+	return TrademarkScoreConfig{
+		Fanciful:        TrademarkScoreRule{Score: 5, Confidence: 1.0},
+		FancifulCommon:  TrademarkScoreRule{Score: 2, Confidence: 0.6},
+		FancifulPopular: TrademarkScoreRule{Score: 3, Confidence: 0.9},
+		Popular:         TrademarkScoreRule{Score: 3, Confidence: 0.9},
+		PopularCommon:   TrademarkScoreRule{Score: 2, Confidence: 0.75},
+		Generic:         TrademarkScoreRule{Score: 0, Confidence: 0.4},
+		GenericCommon:   TrademarkScoreRule{Score: 2, Confidence: 0.6},
+		Embedded:        TrademarkScoreRule{Score: 3, Confidence: 0.5},
+	}
+}
+
+// LoadTrademarkScoreConfig reads a JSON score mapping. Rules or fields missing from the file keep
+// their default values.
+func LoadTrademarkScoreConfig(path string) (TrademarkScoreConfig, error) {
+	cfg := DefaultTrademarkScoreConfig()
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return cfg, fmt.Errorf("read trademark score config: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("unmarshal trademark score config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// Validate checks every rule has a score between 0 and 5 and a confidence between 0 and 1.
+func (c TrademarkScoreConfig) Validate() error {
+	rules := map[string]TrademarkScoreRule{
+		"fanciful":         c.Fanciful,
+		"fanciful_common":  c.FancifulCommon,
+		"fanciful_popular": c.FancifulPopular,
+		"popular":          c.Popular,
+		"popular_common":   c.PopularCommon,
+		"generic":          c.Generic,
+		"generic_common":   c.GenericCommon,
+		"embedded":         c.Embedded,
+	}
+	for name, rule := range rules {
+		if rule.Score < 0 || rule.Score > 5 {
+			return fmt.Errorf("trademark score config %s: score %d outside 0-5", name, rule.Score)
+		}
+		if rule.Confidence < 0 || rule.Confidence > 1 {
+			return fmt.Errorf("trademark score config %s: confidence %.2f outside 0-1", name, rule.Confidence)
+		}
+	}
+	return nil
+}
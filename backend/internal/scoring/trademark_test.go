@@ -3,6 +3,8 @@ package scoring
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"domain-risk-eval/backend/internal/match"
@@ -18,7 +20,7 @@ func TestTrademarkScoringExactMatchOnly(t *testing.T) {
 	}
 
 	seedPath := createSeedFile(t, []string{"google"})
-	scorer, err := NewTrademarkScorer(marks, seedPath)
+	scorer, err := NewTrademarkScorer(marks, seedPath, nil)
 	if err != nil {
 		t.Fatalf("new scorer: %v", err)
 	}
@@ -55,6 +57,137 @@ func TestTrademarkScoringExactMatchOnly(t *testing.T) {
 	}
 }
 
+func TestTrademarkScoringFoldsUnicode(t *testing.T) {
+	match.SetUnicodeFolding(true)
+	defer match.SetUnicodeFolding(false)
+	marks := []store.Mark{{Serial: "1", Mark: "Zorblé", MarkNoSpaces: "zorblé", IsFanciful: true}}
+	scorer, err := NewTrademarkScorer(marks, createSeedFile(t, nil), nil)
+	if err != nil {
+		t.Fatalf("new scorer: %v", err)
+	}
+	for _, domain := range []string{"zorble.com", "zorblé.com", "ZORBLÉ.com", "ｚｏｒｂｌｅ.com"} {
+		t.Run(domain, func(t *testing.T) {
+			result := scorer.Score(match.NormalizeDomain(domain))
+			if result.Type != "fanciful" || result.MatchedTrademark != "Zorblé" {
+				t.Fatalf("expected fanciful match on Zorblé, got %q on %q", result.Type, result.MatchedTrademark)
+			}
+		})
+	}
+}
+
+func TestTrademarkScoringCustomConfig(t *testing.T) {
+	marks := []store.Mark{
+		{Serial: "1", Mark: "ZORBLAX", MarkNoSpaces: "zorblax", IsFanciful: true},
+		{Serial: "2", Mark: "Master", MarkNoSpaces: "master"},
+		{Serial: "3", Mark: "Quixel", MarkNoSpaces: "quixel"},
+	}
+	seedPath := createSeedFile(t, []string{"zorblax"})
+
+	cfg := DefaultTrademarkScoreConfig()
+	cfg.Fanciful = TrademarkScoreRule{Score: 4, Confidence: 0.85}
+	cfg.GenericCommon = TrademarkScoreRule{Score: 1, Confidence: 0.5}
+	cfg.Generic = TrademarkScoreRule{Score: 1, Confidence: 0.3}
+
+	defaults, err := NewTrademarkScorer(marks, seedPath, nil)
+	if err != nil {
+		t.Fatalf("default scorer: %v", err)
+	}
+	custom, err := NewTrademarkScorer(marks, seedPath, &cfg)
+	if err != nil {
+		t.Fatalf("custom scorer: %v", err)
+	}
+
+	testCases := []struct {
+		name         string
+		domain       string
+		defaultScore int
+		customScore  int
+		customConf   float64
+		expectType   string
+		expectSource string
+	}{
+		{"fanciful seed", "zorblax.com", 5, 4, 0.85, "fanciful", MatchSourceSeed},
+		{"common generic", "master.ai", 2, 1, 0.5, "generic", MatchSourceIndex},
+		{"uncommon generic", "quixel.io", 0, 1, 0.3, "generic", MatchSourceIndex},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			profile := match.NormalizeDomain(tc.domain)
+			if got := defaults.Score(profile); got.Score != tc.defaultScore {
+				t.Fatalf("default config: expected score %d got %d", tc.defaultScore, got.Score)
+			}
+			got := custom.Score(profile)
+			if got.Score != tc.customScore || got.Confidence != tc.customConf {
+				t.Fatalf("custom config: expected score %d/%.2f got %d/%.2f", tc.customScore, tc.customConf, got.Score, got.Confidence)
+			}
+			if got.Type != tc.expectType {
+				t.Fatalf("expected type %q got %q", tc.expectType, got.Type)
+			}
+			if got.Source != tc.expectSource {
+				t.Fatalf("expected source %q got %q", tc.expectSource, got.Source)
+			}
+		})
+	}
+}
+
+func TestCommonWordDictionaryDowngradesMatch(t *testing.T) {
+	t.Cleanup(func() { SetCommonWords(DefaultCommonWords()) })
+
+	marks := []store.Mark{{Serial: "1", Mark: "ZORBLAX", MarkNoSpaces: "zorblax", IsFanciful: true}}
+	scorer, err := NewTrademarkScorer(marks, "", nil)
+	if err != nil {
+		t.Fatalf("new scorer: %v", err)
+	}
+	profile := match.NormalizeDomain("zorblax.com")
+
+	if got := scorer.Score(profile); got.Score != 5 || got.Type != "fanciful" {
+		t.Fatalf("expected fanciful 5 before dictionary update, got %s %d", got.Type, got.Score)
+	}
+
+	AddCommonWords("zorblax")
+	if got := scorer.Score(profile); got.Score != 2 || got.Type != "generic" {
+		t.Fatalf("expected generic 2 after AddCommonWords, got %s %d", got.Type, got.Score)
+	}
+
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(path, []byte("# custom dictionary\nmaster\n"), 0o644); err != nil {
+		t.Fatalf("write dictionary: %v", err)
+	}
+	count, err := LoadCommonWords(path)
+	if err != nil || count != 1 {
+		t.Fatalf("expected 1 word loaded, got %d (%v)", count, err)
+	}
+	if got := scorer.Score(profile); got.Score != 5 {
+		t.Fatalf("expected loaded dictionary to replace additions, got %d", got.Score)
+	}
+}
+
+func TestLoadTrademarkScoreConfig(t *testing.T) {
+	path := tempJSON(t, map[string]any{
+		"fanciful": map[string]any{"score": 4},
+		"popular":  map[string]any{"score": 2, "confidence": 0.7},
+	})
+	cfg, err := LoadTrademarkScoreConfig(path)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	defaults := DefaultTrademarkScoreConfig()
+	if cfg.Fanciful.Score != 4 || cfg.Fanciful.Confidence != defaults.Fanciful.Confidence {
+		t.Fatalf("expected fanciful score override with default confidence, got %+v", cfg.Fanciful)
+	}
+	if cfg.Popular != (TrademarkScoreRule{Score: 2, Confidence: 0.7}) {
+		t.Fatalf("expected popular override, got %+v", cfg.Popular)
+	}
+	if cfg.GenericCommon != defaults.GenericCommon {
+		t.Fatalf("expected generic_common default, got %+v", cfg.GenericCommon)
+	}
+
+	invalid := tempJSON(t, map[string]any{"generic": map[string]any{"score": 7}})
+	if _, err := LoadTrademarkScoreConfig(invalid); err == nil {
+		t.Fatalf("expected error for out-of-range score")
+	}
+}
+
 func createSeedFile(t *testing.T, seeds []string) string {
 	t.Helper()
 	tmp, err := os.CreateTemp(t.TempDir(), "seed-*.json")
@@ -78,3 +211,227 @@ func toJSON(t *testing.T, v any) string {
 	}
 	return string(data)
 }
+
+func TestOwnerWatchlistRaisesToReview(t *testing.T) {
+	watchlist := NewOwnerWatchlist([]string{"Apple", " apple ", "Acme Holdings"})
+	if watchlist.Len() != 2 {
+		t.Fatalf("expected 2 owners, got %d", watchlist.Len())
+	}
+
+	cases := []struct {
+		name     string
+		result   TrademarkResult
+		expected int
+		reason   bool
+	}{
+		{"watched_owner", TrademarkResult{Score: 0, Type: "generic", MatchedTrademark: "APPLE", Owner: "APPLE INC."}, 3, true},
+		{"already_high", TrademarkResult{Score: 5, Type: "fanciful", MatchedTrademark: "APPLE", Owner: "Apple Inc"}, 5, false},
+		{"partial_word", TrademarkResult{Score: 1, Type: "generic", MatchedTrademark: "PINE", Owner: "Pineapple LLC"}, 1, false},
+		{"no_match", TrademarkResult{Score: 0, Type: "none", Owner: "Apple Inc"}, 0, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, reason := watchlist.Apply(tc.result)
+			if got.Score != tc.expected {
+				t.Fatalf("expected score %d got %d", tc.expected, got.Score)
+			}
+			if (reason != "") != tc.reason {
+				t.Fatalf("unexpected reason %q", reason)
+			}
+		})
+	}
+}
+
+func TestScoreSubdomainsFlagsBrandOnUnrelatedDomain(t *testing.T) {
+	marks := []store.Mark{
+		{Serial: "1", Mark: "ZORBLAX", MarkNoSpaces: "zorblax", IsFanciful: true},
+		{Serial: "2", Mark: "Master", MarkNoSpaces: "master"},
+	}
+	scorer, err := NewTrademarkScorer(marks, createSeedFile(t, []string{"zorblax"}), nil)
+	if err != nil {
+		t.Fatalf("new scorer: %v", err)
+	}
+
+	tests := []struct {
+		domain      string
+		expectHit   bool
+		expectLabel string
+		registrable string
+	}{
+		{"https://login-zorblax.attacker.com/signin?next=1", true, "login-zorblax", "attacker.com"},
+		{"zorblax.secure.attacker.co.uk", true, "zorblax", "attacker.co.uk"},
+		{"login.zorblax.com", false, "", "zorblax.com"},
+		{"zorblax.com", false, "", "zorblax.com"},
+		{"master.attacker.com", false, "", "attacker.com"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.domain, func(t *testing.T) {
+			profile := match.NormalizeDomain(tc.domain)
+			if profile.Registrable != tc.registrable {
+				t.Fatalf("expected registrable %q got %q", tc.registrable, profile.Registrable)
+			}
+			hit, ok := scorer.ScoreSubdomains(profile)
+			if ok != tc.expectHit {
+				t.Fatalf("expected hit=%v got %+v", tc.expectHit, hit)
+			}
+			if ok && (hit.Label != tc.expectLabel || hit.Trademark.MatchedTrademark != "ZORBLAX") {
+				t.Fatalf("unexpected hit %+v", hit)
+			}
+		})
+	}
+}
+
+func TestEmbeddedMatchesFlagBrandComponents(t *testing.T) {
+	marks := []store.Mark{
+		{Serial: "1", Mark: "ZORBLAX", MarkNoSpaces: "zorblax", IsFanciful: true},
+		{Serial: "2", Mark: "Master", MarkNoSpaces: "master"},
+		{Serial: "3", Mark: "Quixel", MarkNoSpaces: "quixel"},
+	}
+	seedPath := createSeedFile(t, []string{"zorblax"})
+	scorer, err := NewTrademarkScorer(marks, seedPath, nil)
+	if err != nil {
+		t.Fatalf("new scorer: %v", err)
+	}
+	embedded := scorer.WithEmbeddedMatches(true)
+
+	if result := scorer.Score(match.NormalizeDomain("zorblaxstore.com")); result.Score != 0 {
+		t.Fatalf("expected no match without embedded matching, got %+v", result)
+	}
+
+	tests := []struct {
+		domain      string
+		expectType  string
+		expectMatch string
+	}{
+		{"zorblaxstore.com", TrademarkTypeEmbedded, "ZORBLAX"},
+		{"buy-zorblax.net", TrademarkTypeEmbedded, "ZORBLAX"},
+		{"zorblax.com", "fanciful", "ZORBLAX"},
+		{"master-deals.com", "none", ""},
+		{"quixel-deals.com", "none", ""},
+	}
+
+	defaults := DefaultTrademarkScoreConfig()
+	for _, tc := range tests {
+		t.Run(tc.domain, func(t *testing.T) {
+			result := embedded.Score(match.NormalizeDomain(tc.domain))
+			if result.Type != tc.expectType || result.MatchedTrademark != tc.expectMatch {
+				t.Fatalf("expected %s %q got %+v", tc.expectType, tc.expectMatch, result)
+			}
+			if tc.expectType == TrademarkTypeEmbedded && (result.Score != defaults.Embedded.Score || result.Confidence != defaults.Embedded.Confidence) {
+				t.Fatalf("expected embedded rule score, got %+v", result)
+			}
+		})
+	}
+}
+
+func TestWithCustomMarksLayersOverSharedIndex(t *testing.T) {
+	marks := []store.Mark{{Serial: "1", Mark: "QUIXEL", MarkNoSpaces: "quixel"}}
+	shared, err := NewTrademarkScorer(marks, "", nil)
+	if err != nil {
+		t.Fatalf("new scorer: %v", err)
+	}
+	custom := shared.WithCustomMarks([]store.Mark{
+		{Serial: "custom:quixel", Mark: "Quixel", MarkNoSpaces: "quixel", Owner: "Client Co", IsFanciful: true},
+		{Serial: "custom:vandelay", Mark: "Vandelay", MarkNoSpaces: "vandelay", IsFanciful: true},
+		{Serial: "2", Mark: "MEADOW", MarkNoSpaces: "meadow", Owner: "Client Co"},
+	})
+
+	got := custom.Score(match.NormalizeDomain("quixel.io"))
+	if got.Type != "fanciful" || got.Source != MatchSourceCustom || got.Owner != "Client Co" {
+		t.Fatalf("expected custom fanciful hit, got %+v", got)
+	}
+	if got := custom.Score(match.NormalizeDomain("vandelay.com")); got.Source != MatchSourceCustom {
+		t.Fatalf("expected custom-only mark to match, got %+v", got)
+	}
+	if got := custom.Score(match.NormalizeDomain("meadow.com")); got.Type != "generic" || got.Source != MatchSourceCustom {
+		t.Fatalf("expected a layered owner mark to keep its own classification, got %+v", got)
+	}
+
+	if got := shared.Score(match.NormalizeDomain("quixel.io")); got.Type != "generic" || got.Source != MatchSourceIndex {
+		t.Fatalf("shared scorer changed: %+v", got)
+	}
+	if got := shared.Score(match.NormalizeDomain("vandelay.com")); got.Type != "none" {
+		t.Fatalf("custom mark leaked into shared scorer: %+v", got)
+	}
+}
+
+func TestScoreRecordsMatchedSerialAndRegistration(t *testing.T) {
+	marks := []store.Mark{{Serial: "1", Registration: "7000001", Mark: "QUIXEL", MarkNoSpaces: "quixel"}}
+	shared, err := NewTrademarkScorer(marks, "", nil)
+	if err != nil {
+		t.Fatalf("new scorer: %v", err)
+	}
+	if got := shared.Score(match.NormalizeDomain("quixel.io")); got.Serial != "1" || got.Registration != "7000001" {
+		t.Fatalf("expected serial 1 and registration 7000001, got %+v", got)
+	}
+	custom := shared.WithCustomMarks([]store.Mark{{Serial: "custom:vandelay", Mark: "Vandelay", MarkNoSpaces: "vandelay", IsFanciful: true}})
+	if got := custom.Score(match.NormalizeDomain("vandelay.com")); got.Serial != "" || got.Registration != "" {
+		t.Fatalf("expected a custom mark to carry no USPTO numbers, got %+v", got)
+	}
+}
+
+func TestFilterLiveMarks(t *testing.T) {
+	marks := []store.Mark{
+		{Serial: "1", MarkNoSpaces: "registered", StatusCode: "700"},
+		{Serial: "2", MarkNoSpaces: "abandoned", StatusCode: "602"},
+		{Serial: "3", MarkNoSpaces: "cancelled", StatusCode: "710"},
+		{Serial: "4", MarkNoSpaces: "renewed", StatusCode: "800"},
+		{Serial: "5", MarkNoSpaces: "expired", StatusCode: "900"},
+		{Serial: "6", MarkNoSpaces: "pending", StatusCode: "630 "},
+		{Serial: "7", MarkNoSpaces: "unknown"},
+	}
+	live, dropped := FilterLiveMarks(marks)
+	var serials []string
+	for _, mark := range live {
+		serials = append(serials, mark.Serial)
+	}
+	if dropped != 4 || strings.Join(serials, ",") != "1,4,7" {
+		t.Fatalf("expected marks 1,4,7 with 4 dropped, got %v with %d dropped", serials, dropped)
+	}
+}
+
+func TestLoadMarksDropsDeadMarksBeforeLimit(t *testing.T) {
+	db, err := store.Open(filepath.Join(t.TempDir(), "marks.sqlite"), true, store.Config{})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+	// Dead marks are the most popular, so a limit applied before filtering would return none.
+	marks := []store.Mark{
+		{Serial: "1", MarkNoSpaces: "abandoned", StatusCode: "602"},
+		{Serial: "2", MarkNoSpaces: "cancelled", StatusCode: "710"},
+		{Serial: "3", MarkNoSpaces: "expired", StatusCode: " 900"},
+		{Serial: "4", MarkNoSpaces: "registered", StatusCode: "700"},
+		{Serial: "5", MarkNoSpaces: "unknown"},
+		{Serial: "6", MarkNoSpaces: "renewed", StatusCode: "800"},
+	}
+	for i := range marks {
+		if err := db.UpsertMark(&marks[i]); err != nil {
+			t.Fatalf("upsert mark: %v", err)
+		}
+		popular := store.PopularMark{Normalized: marks[i].MarkNoSpaces, Total: 100 - i}
+		if err := db.GORM().Create(&popular).Error; err != nil {
+			t.Fatalf("create popular mark: %v", err)
+		}
+	}
+
+	live, err := LoadMarks(db, 2, true)
+	if err != nil {
+		t.Fatalf("load marks: %v", err)
+	}
+	var serials []string
+	for _, mark := range live {
+		serials = append(serials, mark.Serial)
+	}
+	if strings.Join(serials, ",") != "4,5" {
+		t.Fatalf("expected live marks 4,5, got %v", serials)
+	}
+	all, err := LoadMarks(db, 0, false)
+	if err != nil {
+		t.Fatalf("load all marks: %v", err)
+	}
+	if len(all) != len(marks) {
+		t.Fatalf("expected %d marks without liveOnly, got %d", len(marks), len(all))
+	}
+}
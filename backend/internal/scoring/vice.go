@@ -4,51 +4,211 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 	"sort"
 	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
 
 	"domain-risk-eval/backend/internal/match"
 )
 
+// defaultViceLanguage tags terms supplied in the legacy flat severity format.
+const defaultViceLanguage = "en"
+
+// viceConfidenceKey names the optional severity → confidence section of the vice terms file.
+const viceConfidenceKey = "confidence"
+
+// ViceConfidenceCurve maps a vice severity (0 for no hit, 1-5) to the confidence reported with it.
+type ViceConfidenceCurve map[int]float64
+
+// DefaultViceConfidenceCurve returns the built-in severity → confidence mapping.
+func DefaultViceConfidenceCurve() ViceConfidenceCurve {
+	return ViceConfidenceCurve{0: 0.99, 1: 0.60, 2: 0.70, 3: 0.80, 4: 0.95, 5: 0.95}
+}
+
+// Validate checks every severity is between 0 and 5 and every confidence between 0 and 1.
+func (c ViceConfidenceCurve) Validate() error {
+	for severity, confidence := range c {
+		if severity < 0 || severity > 5 {
+			return fmt.Errorf("vice confidence: severity %d outside 0-5", severity)
+		}
+		if confidence < 0 || confidence > 1 {
+			return fmt.Errorf("vice confidence for severity %d: %.2f outside 0-1", severity, confidence)
+		}
+	}
+	return nil
+}
+
+// For returns the confidence for a severity, falling back to the default curve.
+func (c ViceConfidenceCurve) For(severity int) float64 {
+	if confidence, ok := c[severity]; ok {
+		return confidence
+	}
+	return DefaultViceConfidenceCurve()[severity]
+}
+
 // ViceResult captures vice detection output.
 type ViceResult struct {
 	Score      int      `json:"score"`
 	Categories []string `json:"categories"`
+	Languages  []string `json:"languages,omitempty"`
 	Confidence float64  `json:"confidence"`
+	// Allowlisted describes the allowlist entry that suppressed or capped the match, if any.
+	Allowlisted string `json:"allowlisted,omitempty"`
+	// Suppressed lists the terms that matched before the allowlist was applied.
+	Suppressed    []string `json:"suppressed,omitempty"`
+	OriginalScore int      `json:"original_score,omitempty"`
 }
 
 // ViceScorer evaluates domains against vice term lists.
 type ViceScorer struct {
-	terms map[int][]string
+	terms         map[int][]string
+	termLanguages map[string][]string
+	allowlist     *ViceAllowlist
+	confidence    ViceConfidenceCurve
 }
 
-// NewViceScorer constructs a vice scorer from the provided JSON file.
+// NewViceScorer constructs a vice scorer from the provided JSON file. The file may either be a
+// flat severity → terms map (treated as English) or a language → severity → terms map; every
+// language is applied when scoring. An optional "confidence" section maps severities to the
+// reported confidence, e.g. {"confidence": {"3": 0.85}}; omitted severities keep
+// DefaultViceConfidenceCurve. A missing file falls back to the embedded default terms.
 func NewViceScorer(path string) (*ViceScorer, error) {
-	data, err := os.ReadFile(filepath.Clean(path))
+	data, err := readOrDefault(path, defaultViceTermsJSON, "vice terms")
 	if err != nil {
 		return nil, fmt.Errorf("read vice terms: %w", err)
 	}
-	var raw map[string][]string
+	byLanguage, err := parseViceTerms(data)
+	if err != nil {
+		return nil, err
+	}
+	confidence, err := parseViceConfidence(data)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := make(map[int][]string)
+	termLanguages := make(map[string][]string)
+	seen := make(map[int]map[string]struct{})
+	for _, lang := range sortedLanguages(byLanguage) {
+		for severity, list := range byLanguage[lang] {
+			if seen[severity] == nil {
+				seen[severity] = make(map[string]struct{})
+			}
+			for _, term := range list {
+				term = normalizeTerm(term)
+				if term == "" {
+					continue
+				}
+				termLanguages[term] = appendLanguage(termLanguages[term], lang)
+				if _, ok := seen[severity][term]; ok {
+					continue
+				}
+				seen[severity][term] = struct{}{}
+				terms[severity] = append(terms[severity], term)
+			}
+		}
+	}
+	return &ViceScorer{terms: terms, termLanguages: termLanguages, confidence: confidence}, nil
+}
+
+// parseViceConfidence reads the optional confidence section over the default curve.
+func parseViceConfidence(data []byte) (ViceConfidenceCurve, error) {
+	var file struct {
+		Confidence map[string]float64 `json:"confidence"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("unmarshal vice confidence: %w", err)
+	}
+	curve := DefaultViceConfidenceCurve()
+	for key, value := range file.Confidence {
+		if !isSeverityKey(key) {
+			return nil, fmt.Errorf("vice confidence: invalid severity %q", key)
+		}
+		curve[atoiSafe(key)] = value
+	}
+	if err := curve.Validate(); err != nil {
+		return nil, err
+	}
+	return curve, nil
+}
+
+// parseViceTerms decodes the vice term file into language → severity → terms.
+func parseViceTerms(data []byte) (map[string]map[int][]string, error) {
+	var raw map[string]json.RawMessage
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return nil, fmt.Errorf("unmarshal vice terms: %w", err)
 	}
-	terms := make(map[int][]string)
-	for k, v := range raw {
-		severity := atoiSafe(k)
-		var list []string
-		for _, term := range v {
-			term = normalizeTerm(term)
-			if term != "" {
-				list = append(list, term)
+	out := make(map[string]map[int][]string)
+	add := func(lang string, severityKey string, list []string) {
+		if out[lang] == nil {
+			out[lang] = make(map[int][]string)
+		}
+		severity := atoiSafe(severityKey)
+		out[lang][severity] = append(out[lang][severity], list...)
+	}
+	for key, value := range raw {
+		if key == viceConfidenceKey {
+			continue
+		}
+		if isSeverityKey(key) {
+			var list []string
+			if err := json.Unmarshal(value, &list); err != nil {
+				return nil, fmt.Errorf("unmarshal vice terms for severity %s: %w", key, err)
 			}
+			add(defaultViceLanguage, key, list)
+			continue
+		}
+		lang := strings.ToLower(strings.TrimSpace(key))
+		if lang == "" {
+			continue
+		}
+		var bySeverity map[string][]string
+		if err := json.Unmarshal(value, &bySeverity); err != nil {
+			return nil, fmt.Errorf("unmarshal vice terms for language %s: %w", key, err)
+		}
+		for severityKey, list := range bySeverity {
+			add(lang, severityKey, list)
 		}
-		if len(list) > 0 {
-			terms[severity] = list
+	}
+	return out, nil
+}
+
+func isSeverityKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if r < '0' || r > '9' {
+			return false
 		}
 	}
-	return &ViceScorer{terms: terms}, nil
+	return true
+}
+
+// sortedLanguages orders languages with the default language first so its terms keep priority.
+func sortedLanguages(byLanguage map[string]map[int][]string) []string {
+	langs := make([]string, 0, len(byLanguage))
+	for lang := range byLanguage {
+		langs = append(langs, lang)
+	}
+	sort.Slice(langs, func(i, j int) bool {
+		if langs[i] == defaultViceLanguage || langs[j] == defaultViceLanguage {
+			return langs[i] == defaultViceLanguage
+		}
+		return langs[i] < langs[j]
+	})
+	return langs
+}
+
+func appendLanguage(langs []string, lang string) []string {
+	for _, existing := range langs {
+		if existing == lang {
+			return langs
+		}
+	}
+	return append(langs, lang)
 }
 
 // Score inspects the domain profile and returns vice scoring output.
@@ -72,15 +232,53 @@ func (v *ViceScorer) Score(profile match.DomainProfile) ViceResult {
 			}
 		}
 		if len(hits) > 0 {
-			return ViceResult{
+			return v.applyAllowlist(profile, ViceResult{
 				Score:      severity,
 				Categories: dedupe(hits),
-				Confidence: confidenceForSeverity(severity),
-			}
+				Languages:  v.languagesFor(hits),
+				Confidence: v.confidence.For(severity),
+			})
 		}
 	}
 
-	return ViceResult{Score: 0, Categories: nil, Confidence: confidenceForSeverity(0)}
+	return ViceResult{Score: 0, Categories: nil, Confidence: v.confidence.For(0)}
+}
+
+// SetAllowlist installs the allowlist consulted before a vice hit is returned.
+func (v *ViceScorer) SetAllowlist(allowlist *ViceAllowlist) {
+	if v == nil {
+		return
+	}
+	v.allowlist = allowlist
+}
+
+func (v *ViceScorer) applyAllowlist(profile match.DomainProfile, result ViceResult) ViceResult {
+	entry, ok := v.allowlist.Match(profile)
+	if !ok || result.Score <= entry.MaxScore {
+		return result
+	}
+	capped := ViceResult{
+		Score:         entry.MaxScore,
+		Confidence:    v.confidence.For(entry.MaxScore),
+		Allowlisted:   entry.Describe(),
+		Suppressed:    result.Categories,
+		OriginalScore: result.Score,
+	}
+	if entry.MaxScore > 0 {
+		capped.Categories = result.Categories
+		capped.Languages = result.Languages
+	}
+	return capped
+}
+
+func (v *ViceScorer) languagesFor(hits []string) []string {
+	var langs []string
+	for _, hit := range hits {
+		for _, lang := range v.termLanguages[hit] {
+			langs = append(langs, lang)
+		}
+	}
+	return dedupe(langs)
 }
 
 func dedupe(in []string) []string {
@@ -100,39 +298,22 @@ func dedupe(in []string) []string {
 	return out
 }
 
-func confidenceForSeverity(severity int) float64 {
-	switch severity {
-	case 5, 4:
-		return 0.95
-	case 3:
-		return 0.80
-	case 2:
-		return 0.70
-	case 1:
-		return 0.60
-	default:
-		return 0.99
-	}
-}
-
 func normalizeTerm(term string) string {
-	term = strings.ToLower(term)
+	term = match.Fold(term)
 	term = strings.TrimSpace(term)
 	return stripDiacritics(term)
 }
 
+// stripDiacritics decomposes accented characters, drops the combining marks, and keeps letters
+// and digits from any script so Cyrillic, Greek, or CJK terms survive normalization.
 func stripDiacritics(in string) string {
 	var b strings.Builder
 	b.Grow(len(in))
-	for _, r := range in {
-		if r == ' ' || r == '-' || r == '_' {
-			continue
-		}
-		if r >= 'a' && r <= 'z' {
-			b.WriteRune(r)
+	for _, r := range norm.NFD.String(in) {
+		if unicode.Is(unicode.Mn, r) {
 			continue
 		}
-		if r >= '0' && r <= '9' {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
 			b.WriteRune(r)
 		}
 	}
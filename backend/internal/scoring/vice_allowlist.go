@@ -0,0 +1,98 @@
+package scoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"domain-risk-eval/backend/internal/match"
+)
+
+// ViceAllowlistEntry suppresses or caps vice matches for a known-benign SLD. Exactly one of SLD
+// (exact match after normalization) or Pattern (regular expression tested against the lowercase
+// SLD label) should be set. Patterns should be anchored so they do not mask unrelated terms.
+type ViceAllowlistEntry struct {
+	SLD      string `json:"sld,omitempty"`
+	Pattern  string `json:"pattern,omitempty"`
+	MaxScore int    `json:"max_score,omitempty"`
+	Note     string `json:"note,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// ViceAllowlist holds the entries consulted before a vice hit is returned.
+type ViceAllowlist struct {
+	entries []ViceAllowlistEntry
+}
+
+// LoadViceAllowlist reads a JSON array of allowlist entries from disk.
+func LoadViceAllowlist(path string) (*ViceAllowlist, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read vice allowlist: %w", err)
+	}
+	var entries []ViceAllowlistEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("unmarshal vice allowlist: %w", err)
+	}
+	list := &ViceAllowlist{}
+	for i, entry := range entries {
+		entry.SLD = normalizeTerm(entry.SLD)
+		entry.Pattern = strings.TrimSpace(entry.Pattern)
+		if entry.SLD == "" && entry.Pattern == "" {
+			return nil, fmt.Errorf("vice allowlist entry %d: sld or pattern required", i)
+		}
+		if entry.Pattern != "" {
+			re, err := regexp.Compile(entry.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("vice allowlist entry %d: %w", i, err)
+			}
+			entry.re = re
+		}
+		if entry.MaxScore < 0 {
+			entry.MaxScore = 0
+		}
+		list.entries = append(list.entries, entry)
+	}
+	return list, nil
+}
+
+// Len reports the number of allowlist entries.
+func (a *ViceAllowlist) Len() int {
+	if a == nil {
+		return 0
+	}
+	return len(a.entries)
+}
+
+// Match returns the first entry that applies to the profile's SLD.
+func (a *ViceAllowlist) Match(profile match.DomainProfile) (ViceAllowlistEntry, bool) {
+	if a == nil {
+		return ViceAllowlistEntry{}, false
+	}
+	label := strings.ToLower(strings.TrimSpace(extractSLD(profile)))
+	if label == "" {
+		return ViceAllowlistEntry{}, false
+	}
+	normalized := normalizeTerm(label)
+	for _, entry := range a.entries {
+		if entry.SLD != "" && entry.SLD == normalized {
+			return entry, true
+		}
+		if entry.re != nil && entry.re.MatchString(label) {
+			return entry, true
+		}
+	}
+	return ViceAllowlistEntry{}, false
+}
+
+// Describe renders a short label identifying the entry.
+func (e ViceAllowlistEntry) Describe() string {
+	if e.SLD != "" {
+		return "sld " + e.SLD
+	}
+	return "pattern " + e.Pattern
+}
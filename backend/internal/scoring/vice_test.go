@@ -3,6 +3,7 @@ package scoring
 import (
 	"encoding/json"
 	"os"
+	"strings"
 	"testing"
 
 	"domain-risk-eval/backend/internal/match"
@@ -60,3 +61,117 @@ func tempJSON(t *testing.T, value any) string {
 	}
 	return f.Name()
 }
+
+func TestViceScoringMultilingual(t *testing.T) {
+	match.SetUnicodeFolding(true)
+	defer match.SetUnicodeFolding(false)
+	terms := map[string]map[string][]string{
+		"en": {"3": {"casino"}},
+		"es": {"3": {"apuestas", "casino"}, "4": {"cocaína"}},
+		"ru": {"3": {"казино"}},
+	}
+
+	path := tempJSON(t, terms)
+	scorer, err := NewViceScorer(path)
+	if err != nil {
+		t.Fatalf("vice scorer: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		domain    string
+		expected  int
+		languages []string
+	}{
+		{"shared term", "casino-royale.com", 3, []string{"en", "es"}},
+		{"accented term", "cocaina-facil.es", 4, []string{"es"}},
+		{"accented domain", "apuéstas.mx", 3, []string{"es"}},
+		{"full-width domain", "ｃａｓｉｎｏ.com", 3, []string{"en", "es"}},
+		{"full-width accented domain", "ｃｏｃａíｎａ.es", 4, []string{"es"}},
+		{"cyrillic", "казино.рф", 3, []string{"ru"}},
+		{"clean", "flores.es", 0, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := scorer.Score(match.NormalizeDomain(tc.domain))
+			if result.Score != tc.expected {
+				t.Fatalf("expected %d got %d", tc.expected, result.Score)
+			}
+			if strings.Join(result.Languages, ",") != strings.Join(tc.languages, ",") {
+				t.Fatalf("expected languages %v got %v", tc.languages, result.Languages)
+			}
+		})
+	}
+}
+
+func TestViceAllowlist(t *testing.T) {
+	terms := map[string][]string{
+		"4": {"meth"},
+		"2": {"wine"},
+	}
+	scorer, err := NewViceScorer(tempJSON(t, terms))
+	if err != nil {
+		t.Fatalf("vice scorer: %v", err)
+	}
+	allowlist, err := LoadViceAllowlist(tempJSON(t, []map[string]any{
+		{"sld": "method"},
+		{"pattern": "^winery$", "max_score": 1},
+	}))
+	if err != nil {
+		t.Fatalf("allowlist: %v", err)
+	}
+	scorer.SetAllowlist(allowlist)
+
+	tests := []struct {
+		name        string
+		domain      string
+		expected    int
+		allowlisted bool
+	}{
+		{"exact sld suppressed", "method.com", 0, true},
+		{"pattern downgraded", "winery.com", 1, true},
+		{"unlisted still hits", "methlab.com", 4, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := scorer.Score(match.NormalizeDomain(tc.domain))
+			if result.Score != tc.expected {
+				t.Fatalf("expected %d got %d", tc.expected, result.Score)
+			}
+			if (result.Allowlisted != "") != tc.allowlisted {
+				t.Fatalf("expected allowlisted=%v got %q", tc.allowlisted, result.Allowlisted)
+			}
+		})
+	}
+}
+
+func TestViceConfidenceCurve(t *testing.T) {
+	terms := map[string]any{
+		"en":         map[string][]string{"3": {"casino"}, "4": {"meth"}},
+		"confidence": map[string]float64{"3": 0.5, "0": 0.9},
+	}
+	scorer, err := NewViceScorer(tempJSON(t, terms))
+	if err != nil {
+		t.Fatalf("vice scorer: %v", err)
+	}
+
+	tests := []struct {
+		domain     string
+		confidence float64
+	}{
+		{"casino-royale.com", 0.5},
+		{"meth-lab.com", 0.95},
+		{"flowers.com", 0.9},
+	}
+	for _, tc := range tests {
+		if got := scorer.Score(match.NormalizeDomain(tc.domain)).Confidence; got != tc.confidence {
+			t.Fatalf("%s: expected confidence %.2f got %.2f", tc.domain, tc.confidence, got)
+		}
+	}
+
+	for _, invalid := range []map[string]float64{{"3": 1.2}, {"7": 0.5}} {
+		if _, err := NewViceScorer(tempJSON(t, map[string]any{"3": []string{"casino"}, "confidence": invalid})); err == nil {
+			t.Fatalf("expected error for confidence %v", invalid)
+		}
+	}
+}
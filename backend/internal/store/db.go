@@ -1,8 +1,11 @@
 package store
 
 import (
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -18,19 +21,81 @@ import (
 type Database struct {
 	gorm *gorm.DB
 	mu   sync.Mutex
+	// marks, when set by OpenMarks, holds the marks and popular_marks tables in a separate file.
+	marks         *gorm.DB
+	marksReadOnly bool
+	cfg           Config
 }
 
+// Config tunes the SQLite connections. The zero value uses DefaultBusyTimeout and leaves the
+// connection pool unbounded.
+type Config struct {
+	// BusyTimeout is how long a connection waits on a locked database before failing with
+	// "database is locked"; zero uses DefaultBusyTimeout.
+	BusyTimeout time.Duration
+	// MaxOpenConns caps open connections; 1 serializes all access, trading WAL's concurrent
+	// reads for never contending on the write lock. Zero means no limit.
+	MaxOpenConns int
+	// MaxIdleConns is how many idle connections are kept; zero keeps database/sql's default.
+	MaxIdleConns int
+	// DecodeDomain, when set, canonicalizes a lowercased domain before it is used as a key, e.g.
+	// decoding punycode labels so both spellings of an IDN share one row. Nil keys on the
+	// lowercased value alone.
+	DecodeDomain func(string) string
+}
+
+// DefaultBusyTimeout is the busy timeout used when Config.BusyTimeout is unset. Evaluation
+// workers and batched saves contend on the single SQLite writer, so a few seconds of waiting
+// beats failing the write.
+const DefaultBusyTimeout = 5 * time.Second
+
+// dsn appends the connection options to path; the busy timeout goes in the DSN so every pooled
+// connection gets it, which a one-off PRAGMA would not.
+func (c Config) dsn(path string) string {
+	timeout := c.BusyTimeout
+	if timeout <= 0 {
+		timeout = DefaultBusyTimeout
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return fmt.Sprintf("%s%s_busy_timeout=%d", path, sep, timeout.Milliseconds())
+}
+
+// applyPool sets the connection pool limits on db.
+func (c Config) applyPool(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	if c.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(c.MaxOpenConns)
+	}
+	if c.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(c.MaxIdleConns)
+	}
+	return nil
+}
+
+// ErrMarksReadOnly is returned when writing marks to a marks database opened read-only.
+var ErrMarksReadOnly = errors.New("marks database is read-only")
+
 // Open initializes the SQLite-backed database at the provided path.
-func Open(path string, silent bool) (*Database, error) {
+func Open(path string, silent bool, conn Config) (*Database, error) {
 	cfg := &gorm.Config{}
 	if silent {
 		cfg.Logger = logger.Default.LogMode(logger.Silent)
 	}
-	db, err := gorm.Open(sqlite.Open(path), cfg)
+	db, err := gorm.Open(sqlite.Open(conn.dsn(path)), cfg)
 	if err != nil {
 		return nil, fmt.Errorf("open database: %w", err)
 	}
-	if err := db.AutoMigrate(&Mark{}, &Domain{}, &Evaluation{}, &CommercialSale{}, &PopularMark{}, &CSVBatch{}, &BatchRequest{}, &DomainBatch{}, &JobState{}); err != nil {
+	if err := conn.applyPool(db); err != nil {
+		closeGORM(db)
+		return nil, fmt.Errorf("configure connection pool: %w", err)
+	}
+	if err := db.AutoMigrate(&Mark{}, &Domain{}, &Evaluation{}, &CommercialSale{}, &PopularMark{}, &CSVBatch{}, &BatchRequest{}, &DomainBatch{}, &JobState{}, &UploadKey{}, &SkippedDomain{}); err != nil {
 		return nil, fmt.Errorf("auto migrate: %w", err)
 	}
 	if err := db.Exec("PRAGMA journal_mode=WAL").Error; err != nil {
@@ -42,7 +107,59 @@ func Open(path string, silent bool) (*Database, error) {
 	if err := applyIndexes(db); err != nil {
 		return nil, fmt.Errorf("apply indexes: %w", err)
 	}
-	return &Database{gorm: db}, nil
+	database := &Database{gorm: db, cfg: conn}
+	if err := database.rekeyDomains(); err != nil {
+		return nil, fmt.Errorf("rekey domains: %w", err)
+	}
+	return database, nil
+}
+
+// OpenMarks moves mark storage to a separate SQLite file so the large, rarely changing trademark
+// corpus does not share locks or backups with the operational tables. A read-only marks database
+// is never migrated and rejects mark writes with ErrMarksReadOnly, which lets it be shipped as a
+// prebuilt artifact; a writable one is migrated like the main database. It uses the connection
+// settings the Database was opened with. Call it before the Database is shared.
+func (d *Database) OpenMarks(path string, readOnly, silent bool) error {
+	cfg := &gorm.Config{}
+	if silent {
+		cfg.Logger = logger.Default.LogMode(logger.Silent)
+	}
+	dsn := path
+	if readOnly {
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("open marks database: %w", err)
+		}
+		dsn = "file:" + path + "?mode=ro"
+	}
+	db, err := gorm.Open(sqlite.Open(d.cfg.dsn(dsn)), cfg)
+	if err != nil {
+		return fmt.Errorf("open marks database: %w", err)
+	}
+	if err := d.cfg.applyPool(db); err != nil {
+		closeGORM(db)
+		return fmt.Errorf("configure marks connection pool: %w", err)
+	}
+	if readOnly {
+		if !db.Migrator().HasTable(&Mark{}) || !db.Migrator().HasTable(&PopularMark{}) {
+			closeGORM(db)
+			return fmt.Errorf("marks database %s lacks the marks or popular_marks table", path)
+		}
+	} else {
+		if err := db.AutoMigrate(&Mark{}, &PopularMark{}); err != nil {
+			closeGORM(db)
+			return fmt.Errorf("auto migrate marks: %w", err)
+		}
+		if err := db.Exec("PRAGMA journal_mode=WAL").Error; err != nil {
+			logrus.WithError(err).Warn("enable WAL mode for marks database")
+		}
+		if err := applyMarkIndexes(db); err != nil {
+			closeGORM(db)
+			return fmt.Errorf("apply mark indexes: %w", err)
+		}
+	}
+	d.marks = db
+	d.marksReadOnly = readOnly
+	return nil
 }
 
 // GORM exposes the raw gorm.DB handle.
@@ -50,12 +167,35 @@ func (d *Database) GORM() *gorm.DB {
 	return d.gorm
 }
 
-// Close closes the underlying database connection.
+// MarksGORM exposes the handle holding the marks and popular_marks tables: the separate marks
+// database when one is open, otherwise the main handle.
+func (d *Database) MarksGORM() *gorm.DB {
+	if d.marks != nil {
+		return d.marks
+	}
+	return d.gorm
+}
+
+// MarksReadOnly reports whether marks live in a read-only marks database.
+func (d *Database) MarksReadOnly() bool {
+	return d.marks != nil && d.marksReadOnly
+}
+
+// Close closes the underlying database connections.
 func (d *Database) Close() error {
 	if d == nil {
 		return nil
 	}
-	sqlDB, err := d.gorm.DB()
+	if d.marks != nil {
+		if err := closeGORM(d.marks); err != nil {
+			logrus.WithError(err).Warn("close marks database")
+		}
+	}
+	return closeGORM(d.gorm)
+}
+
+func closeGORM(db *gorm.DB) error {
+	sqlDB, err := db.DB()
 	if err != nil {
 		return err
 	}
@@ -67,11 +207,14 @@ func (d *Database) UpsertMark(mark *Mark) error {
 	if mark == nil {
 		return errors.New("mark is nil")
 	}
+	if d.MarksReadOnly() {
+		return ErrMarksReadOnly
+	}
 	d.mu.Lock()
 	defer d.mu.Unlock()
-	return d.gorm.Clauses(clause.OnConflict{
+	return d.MarksGORM().Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "serial"}},
-		DoUpdates: clause.AssignmentColumns([]string{"registration", "mark", "mark_normalized", "mark_no_spaces", "owner", "classes_json", "is_fanciful", "updated_at"}),
+		DoUpdates: clause.AssignmentColumns([]string{"registration", "mark", "mark_normalized", "mark_no_spaces", "owner", "owners_json", "classes_json", "is_fanciful", "status_code", "updated_at"}),
 	}).Create(mark).Error
 }
 
@@ -81,7 +224,7 @@ func (d *Database) SaveDomain(domain *Domain) error {
 		return errors.New("domain is nil")
 	}
 	domain.Domain = strings.TrimSpace(domain.Domain)
-	domain.DomainNormalized = normalizeDomainKey(domain.Domain)
+	domain.DomainNormalized = d.domainKey(domain.Domain)
 	d.mu.Lock()
 	defer d.mu.Unlock()
 	return d.gorm.Clauses(clause.OnConflict{
@@ -101,7 +244,12 @@ func (d *Database) SaveEvaluation(e *Evaluation) error {
 		"trademark_score",
 		"trademark_type",
 		"matched_trademark",
+		"matched_owner",
+		"matched_serial",
+		"matched_registration",
+		"trademark_source",
 		"trademark_confidence",
+		"trademark_conflict",
 		"vice_score",
 		"vice_categories_json",
 		"vice_confidence",
@@ -111,9 +259,16 @@ func (d *Database) SaveEvaluation(e *Evaluation) error {
 		"commercial_override",
 		"commercial_source",
 		"commercial_similarity",
+		"commercial_price",
+		"commercial_match_json",
+		"reasons_json",
+		"close_matches_json",
+		"lookup_ms",
+		"ai_ms",
+		"updated_at",
 	}
 	e.Domain = strings.TrimSpace(e.Domain)
-	e.DomainNormalized = normalizeDomainKey(e.Domain)
+	e.DomainNormalized = d.domainKey(e.Domain)
 	return d.gorm.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "domain_normalized"}},
 		DoUpdates: clause.AssignmentColumns(append(columns, "domain", "domain_normalized")),
@@ -200,6 +355,69 @@ func (d *Database) ClearEvaluations() error {
 	return d.gorm.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&Evaluation{}).Error
 }
 
+// BatchesSharingDomains returns the other batches that contain at least one of the batch's
+// domains, and so share its evaluations.
+func (d *Database) BatchesSharingDomains(batchID uint) ([]uint, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.batchesSharingDomains(batchID)
+}
+
+func (d *Database) batchesSharingDomains(batchID uint) ([]uint, error) {
+	var affected []uint
+	err := d.gorm.Raw(`
+		SELECT DISTINCT other.batch_id
+		FROM domain_batches other
+		JOIN domain_batches db ON db.domain_normalized = other.domain_normalized
+		WHERE db.batch_id = ? AND other.batch_id <> ?
+		ORDER BY other.batch_id`, batchID, batchID).Scan(&affected).Error
+	return affected, err
+}
+
+// ClearBatchEvaluations deletes the evaluations of every domain in the batch, along with its
+// skipped-row records, and returns how many evaluations were removed. Evaluations are keyed by
+// domain, so other batches containing the same domains (see BatchesSharingDomains) lose those
+// results too; their processed counts are refreshed along with the batch's own.
+func (d *Database) ClearBatchEvaluations(batchID uint) (int64, error) {
+	d.mu.Lock()
+	affected, err := d.batchesSharingDomains(batchID)
+	if err != nil {
+		d.mu.Unlock()
+		return 0, err
+	}
+	res := d.gorm.Where("domain_normalized IN (?)",
+		d.gorm.Model(&DomainBatch{}).Select("domain_normalized").Where("batch_id = ?", batchID)).
+		Delete(&Evaluation{})
+	if res.Error != nil {
+		d.mu.Unlock()
+		return 0, res.Error
+	}
+	err = d.gorm.Where("batch_id = ?", batchID).Delete(&SkippedDomain{}).Error
+	d.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := d.gorm.Model(&CSVBatch{}).
+		Where("id = ?", batchID).
+		Updates(map[string]any{
+			"processed_domains": 0,
+			"last_evaluated_at": nil,
+		}).Error; err != nil {
+		return res.RowsAffected, err
+	}
+	for _, id := range affected {
+		processed, err := d.CountBatchResults(id)
+		if err != nil {
+			return res.RowsAffected, err
+		}
+		if err := d.gorm.Model(&CSVBatch{}).Where("id = ?", id).Update("processed_domains", processed).Error; err != nil {
+			return res.RowsAffected, err
+		}
+	}
+	return res.RowsAffected, nil
+}
+
 // ClearDomains removes existing domain entries (used before re-importing a CSV).
 func (d *Database) ClearDomains() error {
 	d.mu.Lock()
@@ -210,12 +428,45 @@ func (d *Database) ClearDomains() error {
 // CountMarks returns the number of mark entries.
 func (d *Database) CountMarks() (int64, error) {
 	var count int64
-	if err := d.gorm.Model(&Mark{}).Count(&count).Error; err != nil {
+	if err := d.MarksGORM().Model(&Mark{}).Count(&count).Error; err != nil {
 		return 0, err
 	}
 	return count, nil
 }
 
+// MarksVersion fingerprints the mark tables by row count and newest updated_at. It changes when
+// marks are ingested, updated, or deleted and when the popular marks are replaced, so callers can
+// skip reloading marks that have not changed.
+type MarksVersion struct {
+	Marks          int64
+	MarksUpdated   string
+	Popular        int64
+	PopularUpdated string
+}
+
+// MarksVersion returns the current fingerprint of the marks and popular_marks tables.
+func (d *Database) MarksVersion() (MarksVersion, error) {
+	var version MarksVersion
+	for _, table := range []struct {
+		name    string
+		count   *int64
+		updated *string
+	}{
+		{"marks", &version.Marks, &version.MarksUpdated},
+		{"popular_marks", &version.Popular, &version.PopularUpdated},
+	} {
+		var row struct {
+			Total   int64
+			Updated sql.NullString
+		}
+		if err := d.MarksGORM().Raw("SELECT COUNT(*) AS total, MAX(updated_at) AS updated FROM " + table.name).Scan(&row).Error; err != nil {
+			return MarksVersion{}, fmt.Errorf("%s version: %w", table.name, err)
+		}
+		*table.count, *table.updated = row.Total, row.Updated.String
+	}
+	return version, nil
+}
+
 // CountDomains returns the domain count.
 func (d *Database) CountDomains() (int64, error) {
 	var count int64
@@ -253,11 +504,75 @@ type EvaluationQuery struct {
 	Offset         int
 	Limit          int
 	BatchID        uint
+	// MinCommercialPrice keeps evaluations whose matched commercial sale is at least this price.
+	MinCommercialPrice float64
+	// UpdatedSince keeps evaluations saved at or after this time; zero disables the filter.
+	UpdatedSince time.Time
+	// CommercialOverride, when set, keeps only evaluations whose recommendation was (true) or was
+	// not (false) softened by a commercial sale.
+	CommercialOverride *bool
+	// Recommendations, when non-empty, keeps evaluations with any of these recommendations; it is
+	// applied together with Recommendation.
+	Recommendations []string
+	// ViceCategory keeps evaluations whose vice categories include this one.
+	ViceCategory string
 }
 
 // ListEvaluations returns paginated evaluation records applying optional filters.
 func (d *Database) ListEvaluations(opts EvaluationQuery) ([]Evaluation, int64, error) {
+	base := d.filterEvaluations(opts)
 	var total int64
+	if err := base.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := orderForSort(opts.Sort)
+	queryBuilder := base.Order(order).Offset(opts.Offset)
+	if opts.Limit > 0 {
+		queryBuilder = queryBuilder.Limit(opts.Limit)
+	}
+
+	var rows []Evaluation
+	if err := queryBuilder.Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+// EachEvaluation calls fn for every evaluation matching the filters, reading rows from the
+// database one at a time instead of loading the full result set. Pagination fields are ignored.
+// Iteration stops at the first error returned by fn.
+func (d *Database) EachEvaluation(opts EvaluationQuery, fn func(Evaluation) error) error {
+	tx := d.filterEvaluations(opts).Order(orderForSort(opts.Sort))
+	rows, err := tx.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var row Evaluation
+		if err := tx.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// CountEvaluations returns the number of evaluations matching the filters without loading rows.
+// Sort and pagination fields are ignored.
+func (d *Database) CountEvaluations(opts EvaluationQuery) (int64, error) {
+	var total int64
+	if err := d.filterEvaluations(opts).Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// filterEvaluations applies the EvaluationQuery filters shared by listing and counting.
+func (d *Database) filterEvaluations(opts EvaluationQuery) *gorm.DB {
 	base := d.gorm.Model(&Evaluation{})
 	if opts.BatchID > 0 {
 		base = base.Where("domain_normalized IN (SELECT domain_normalized FROM domain_batches WHERE batch_id = ?)", opts.BatchID)
@@ -282,22 +597,24 @@ func (d *Database) ListEvaluations(opts EvaluationQuery) ([]Evaluation, int64, e
 	if rec := strings.TrimSpace(opts.Recommendation); rec != "" {
 		base = base.Where("overall_recommendation = ?", strings.ToUpper(rec))
 	}
-
-	if err := base.Count(&total).Error; err != nil {
-		return nil, 0, err
+	if len(opts.Recommendations) > 0 {
+		base = base.Where("overall_recommendation IN ?", opts.Recommendations)
 	}
-
-	order := orderForSort(opts.Sort)
-	queryBuilder := base.Order(order).Offset(opts.Offset)
-	if opts.Limit > 0 {
-		queryBuilder = queryBuilder.Limit(opts.Limit)
+	if opts.MinCommercialPrice > 0 {
+		base = base.Where("commercial_price >= ?", opts.MinCommercialPrice)
 	}
-
-	var rows []Evaluation
-	if err := queryBuilder.Find(&rows).Error; err != nil {
-		return nil, 0, err
+	if opts.CommercialOverride != nil {
+		base = base.Where("commercial_override = ?", *opts.CommercialOverride)
 	}
-	return rows, total, nil
+	if !opts.UpdatedSince.IsZero() {
+		base = base.Where("updated_at >= ?", opts.UpdatedSince)
+	}
+	if category := strings.ToLower(strings.TrimSpace(opts.ViceCategory)); category != "" {
+		// Categories are stored as a JSON string array, so match the quoted element.
+		quoted, _ := json.Marshal(category)
+		base = base.Where(`vice_categories_json LIKE ? ESCAPE '\'`, "%"+EscapeLike(string(quoted))+"%")
+	}
+	return base
 }
 
 func orderForSort(sort string) string {
@@ -314,10 +631,18 @@ func orderForSort(sort string) string {
 		return "evaluations.vice_score DESC, evaluations.trademark_score DESC, evaluations.id DESC"
 	case "vice_asc":
 		return "evaluations.vice_score ASC, evaluations.id DESC"
+	case "price_desc":
+		return "evaluations.commercial_price DESC, evaluations.id DESC"
+	case "price_asc":
+		return "evaluations.commercial_price ASC, evaluations.id DESC"
 	case "created_asc":
 		return "evaluations.created_at ASC"
 	case "created_desc":
 		return "evaluations.created_at DESC"
+	case "updated_asc":
+		return "evaluations.updated_at ASC, evaluations.id ASC"
+	case "updated_desc":
+		return "evaluations.updated_at DESC, evaluations.id DESC"
 	default:
 		return "evaluations.id DESC"
 	}
@@ -330,26 +655,104 @@ type BatchDomain struct {
 	HasResult        bool
 }
 
-func normalizeDomainKey(value string) string {
+// likeEscaper escapes the LIKE wildcards, and the escape character itself, for a pattern used
+// with ESCAPE '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike makes value match literally inside a LIKE pattern declared with ESCAPE '\'.
+func EscapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+// NormalizeDomainKey returns the trimmed, lowercased domain. Stored keys additionally pass
+// through Config.DecodeDomain; callers building keys outside the store must apply the same.
+func NormalizeDomainKey(value string) string {
 	return strings.ToLower(strings.TrimSpace(value))
 }
 
+// domainKey returns the key domains are deduplicated and stored under.
+func (d *Database) domainKey(value string) string {
+	key := NormalizeDomainKey(value)
+	if d.cfg.DecodeDomain != nil {
+		key = d.cfg.DecodeDomain(key)
+	}
+	return key
+}
+
+// rekeyDomains rewrites punycode keys stored before Config.DecodeDomain was applied. Where the
+// decoded key already has a domain or evaluation row, the stale punycode row is dropped.
+func (d *Database) rekeyDomains() error {
+	if d.cfg.DecodeDomain == nil {
+		return nil
+	}
+	return d.gorm.Transaction(func(tx *gorm.DB) error {
+		for _, table := range []struct {
+			name   string
+			unique bool
+		}{{"domains", true}, {"evaluations", true}, {"domain_batches", false}} {
+			var rows []struct {
+				ID               uint
+				DomainNormalized string
+			}
+			if err := tx.Table(table.name).Select("id", "domain_normalized").
+				Where("domain_normalized LIKE ? OR domain_normalized LIKE ?", "xn--%", "%.xn--%").
+				Find(&rows).Error; err != nil {
+				return err
+			}
+			for _, row := range rows {
+				key := d.cfg.DecodeDomain(row.DomainNormalized)
+				if key == row.DomainNormalized {
+					continue
+				}
+				if table.unique {
+					var taken int64
+					if err := tx.Table(table.name).Where("domain_normalized = ?", key).Count(&taken).Error; err != nil {
+						return err
+					}
+					if taken > 0 {
+						if err := tx.Exec("DELETE FROM "+table.name+" WHERE id = ?", row.ID).Error; err != nil {
+							return err
+						}
+						continue
+					}
+				}
+				if err := tx.Table(table.name).Where("id = ?", row.ID).Update("domain_normalized", key).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
 func applyIndexes(db *gorm.DB) error {
 	stmts := []string{
 		"UPDATE domains SET domain_normalized = LOWER(domain) WHERE domain IS NOT NULL AND (domain_normalized IS NULL OR domain_normalized = '')",
 		"UPDATE domain_batches SET domain_normalized = LOWER(domain) WHERE domain IS NOT NULL AND (domain_normalized IS NULL OR domain_normalized = '')",
 		"UPDATE evaluations SET domain_normalized = LOWER(domain) WHERE domain IS NOT NULL AND (domain_normalized IS NULL OR domain_normalized = '')",
+		"UPDATE evaluations SET updated_at = created_at WHERE updated_at IS NULL",
 		"CREATE UNIQUE INDEX IF NOT EXISTS idx_domains_domain_normalized ON domains(domain_normalized)",
 		"CREATE INDEX IF NOT EXISTS idx_domains_brand_token ON domains(brand_token)",
 		"CREATE INDEX IF NOT EXISTS idx_domain_batches_batch_domain_normalized ON domain_batches(batch_id, domain_normalized)",
 		"CREATE UNIQUE INDEX IF NOT EXISTS idx_evaluations_domain_normalized ON evaluations(domain_normalized)",
 		"CREATE INDEX IF NOT EXISTS idx_evaluations_trademark_score ON evaluations(trademark_score)",
 		"CREATE INDEX IF NOT EXISTS idx_evaluations_vice_score ON evaluations(vice_score)",
+		"CREATE INDEX IF NOT EXISTS idx_job_states_status_updated ON job_states(status, updated_at)",
+		"CREATE INDEX IF NOT EXISTS idx_job_states_batch ON job_states(batch_id)",
+	}
+	for _, stmt := range stmts {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return applyMarkIndexes(db)
+}
+
+func applyMarkIndexes(db *gorm.DB) error {
+	stmts := []string{
 		"CREATE INDEX IF NOT EXISTS idx_marks_mark_normalized ON marks(mark_normalized)",
 		"CREATE INDEX IF NOT EXISTS idx_marks_mark_no_spaces ON marks(mark_no_spaces)",
 		"CREATE INDEX IF NOT EXISTS idx_marks_owner ON marks(owner)",
-		"CREATE INDEX IF NOT EXISTS idx_job_states_status_updated ON job_states(status, updated_at)",
-		"CREATE INDEX IF NOT EXISTS idx_job_states_batch ON job_states(batch_id)",
 	}
 	for _, stmt := range stmts {
 		if err := db.Exec(stmt).Error; err != nil {
@@ -394,8 +797,75 @@ func (d *Database) ReplaceDomainBatch(batchID uint, rows []DomainBatch) error {
 	})
 }
 
+// NewBatchDomainKeys returns the normalized keys that are not yet part of the batch.
+func (d *Database) NewBatchDomainKeys(batchID uint, keys []string) (map[string]struct{}, error) {
+	result := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if key != "" {
+			result[key] = struct{}{}
+		}
+	}
+	unique := make([]string, 0, len(result))
+	for key := range result {
+		unique = append(unique, key)
+	}
+
+	const chunkSize = 1000
+	for i := 0; i < len(unique); i += chunkSize {
+		end := i + chunkSize
+		if end > len(unique) {
+			end = len(unique)
+		}
+		var rows []string
+		if err := d.gorm.Model(&DomainBatch{}).
+			Where("batch_id = ? AND domain_normalized IN ?", batchID, unique[i:end]).
+			Distinct().
+			Pluck("domain_normalized", &rows).Error; err != nil {
+			return nil, err
+		}
+		for _, dom := range rows {
+			delete(result, dom)
+		}
+	}
+	return result, nil
+}
+
+// AppendDomainBatch adds rows to an existing batch, numbering them after the batch's current
+// rows so evaluation order follows upload order.
+func (d *Database) AppendDomainBatch(batchID uint, rows []DomainBatch) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return d.gorm.Transaction(func(tx *gorm.DB) error {
+		var maxRow int
+		if err := tx.Model(&DomainBatch{}).
+			Where("batch_id = ?", batchID).
+			Select("COALESCE(MAX(row_index), 0)").
+			Scan(&maxRow).Error; err != nil {
+			return err
+		}
+		for i := range rows {
+			rows[i].BatchID = batchID
+			rows[i].RowIndex += maxRow
+		}
+		return tx.CreateInBatches(rows, 500).Error
+	})
+}
+
 // ExistingEvaluationKeys returns a set of domains that already have evaluation results.
 func (d *Database) ExistingEvaluationKeys(domains []string) (map[string]struct{}, error) {
+	return d.existingDomainKeys(&Evaluation{}, domains)
+}
+
+// ExistingDomainKeys returns the normalized domains that already have a row in the domains table,
+// i.e. were part of an earlier upload.
+func (d *Database) ExistingDomainKeys(domains []string) (map[string]struct{}, error) {
+	return d.existingDomainKeys(&Domain{}, domains)
+}
+
+// existingDomainKeys checks which normalized domains have a row in model's table, querying in
+// chunks to stay below SQLite's bound-parameter limit.
+func (d *Database) existingDomainKeys(model any, domains []string) (map[string]struct{}, error) {
 	result := make(map[string]struct{})
 	if len(domains) == 0 {
 		return result, nil
@@ -404,7 +874,7 @@ func (d *Database) ExistingEvaluationKeys(domains []string) (map[string]struct{}
 	unique := make([]string, 0, len(domains))
 	seen := make(map[string]struct{})
 	for _, dom := range domains {
-		key := normalizeDomainKey(dom)
+		key := d.domainKey(dom)
 		if key == "" {
 			continue
 		}
@@ -428,7 +898,7 @@ func (d *Database) ExistingEvaluationKeys(domains []string) (map[string]struct{}
 		chunk := unique[i:end]
 
 		var rows []string
-		if err := d.gorm.Model(&Evaluation{}).
+		if err := d.gorm.Model(model).
 			Where("domain_normalized IN ?", chunk).
 			Pluck("domain_normalized", &rows).Error; err != nil {
 			return nil, err
@@ -440,6 +910,68 @@ func (d *Database) ExistingEvaluationKeys(domains []string) (map[string]struct{}
 	return result, nil
 }
 
+// EvaluationsByDomain loads the stored evaluations for the given normalized domains. Domains
+// without an evaluation are omitted.
+func (d *Database) EvaluationsByDomain(keys []string) ([]Evaluation, error) {
+	const chunkSize = 1000
+	var result []Evaluation
+	for i := 0; i < len(keys); i += chunkSize {
+		end := i + chunkSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		var rows []Evaluation
+		if err := d.gorm.Where("domain_normalized IN ?", keys[i:end]).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		result = append(result, rows...)
+	}
+	return result, nil
+}
+
+// FindDomainsByPattern returns up to limit uploaded domains whose normalized form matches the
+// SQLite GLOB pattern, in alphabetical order, with the total number of matches. A pattern with a
+// literal prefix can use the domain_normalized index.
+func (d *Database) FindDomainsByPattern(glob string, limit int) ([]string, int64, error) {
+	base := d.gorm.Model(&Domain{}).Where("domain_normalized GLOB ?", glob)
+	var total int64
+	if err := base.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var keys []string
+	if err := base.Order("domain_normalized ASC").Limit(limit).Pluck("domain_normalized", &keys).Error; err != nil {
+		return nil, 0, err
+	}
+	return keys, total, nil
+}
+
+// SaveSkippedDomain records a skipped batch row, replacing the record of an earlier run.
+func (d *Database) SaveSkippedDomain(row *SkippedDomain) error {
+	if row == nil {
+		return errors.New("skipped domain is nil")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.gorm.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "batch_id"}, {Name: "row_index"}},
+		DoUpdates: clause.AssignmentColumns([]string{"domain", "reason", "created_at"}),
+	}).Create(row).Error
+}
+
+// ListSkippedDomains returns a page of a batch's skipped rows in upload order with their total.
+func (d *Database) ListSkippedDomains(batchID uint, offset, limit int) ([]SkippedDomain, int64, error) {
+	base := d.gorm.Model(&SkippedDomain{}).Where("batch_id = ?", batchID)
+	var total int64
+	if err := base.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []SkippedDomain
+	if err := base.Order("row_index ASC").Offset(offset).Limit(limit).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
 // CountBatchDomains returns the number of distinct domains in a batch.
 func (d *Database) CountBatchDomains(batchID uint) (int, error) {
 	var count int64
@@ -451,6 +983,29 @@ func (d *Database) CountBatchDomains(batchID uint) (int, error) {
 	return int(count), nil
 }
 
+// CountBatchRows returns the number of stored rows in a batch, duplicates included.
+func (d *Database) CountBatchRows(batchID uint) (int, error) {
+	var count int64
+	if err := d.gorm.Model(&DomainBatch{}).Where("batch_id = ?", batchID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// CountBatchPriorResults returns the number of domains in a batch whose evaluation was created
+// before since, i.e. that were already evaluated when the batch was uploaded.
+func (d *Database) CountBatchPriorResults(batchID uint, since time.Time) (int, error) {
+	var count int64
+	query := d.gorm.Table("domain_batches AS db").
+		Select("COUNT(DISTINCT e.domain_normalized)").
+		Joins("JOIN evaluations e ON e.domain_normalized = db.domain_normalized").
+		Where("db.batch_id = ? AND e.created_at < ?", batchID, since)
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
 // CountBatchResults returns the number of domains in a batch that already have evaluation results.
 func (d *Database) CountBatchResults(batchID uint) (int, error) {
 	var count int64
@@ -498,19 +1053,15 @@ func (d *Database) EvaluatedDomainsForBatch(batchID uint) ([]string, error) {
 	return rows, nil
 }
 
-// CreateBatchRequest records a new evaluation request for a batch.
-func (d *Database) CreateBatchRequest(batchID uint, requestType, status, jobID string) (*BatchRequest, error) {
-	request := &BatchRequest{
-		BatchID:   batchID,
-		Type:      requestType,
-		Status:    status,
-		JobID:     jobID,
-		StartedAt: time.Now(),
+// CreateBatchRequest records a new evaluation request for a batch; StartedAt defaults to now.
+func (d *Database) CreateBatchRequest(request *BatchRequest) error {
+	if request == nil {
+		return errors.New("batch request is nil")
 	}
-	if err := d.gorm.Create(request).Error; err != nil {
-		return nil, err
+	if request.StartedAt.IsZero() {
+		request.StartedAt = time.Now()
 	}
-	return request, nil
+	return d.gorm.Create(request).Error
 }
 
 // UpdateBatchRequest updates the status and timestamps of a batch request.
@@ -523,6 +1074,66 @@ func (d *Database) UpdateBatchRequest(requestID uint, status string) error {
 	return d.gorm.Model(&BatchRequest{}).Where("id = ?", requestID).Updates(updates).Error
 }
 
+// SaveBatchRequestSummary records the run summary of a finished batch request.
+func (d *Database) SaveBatchRequestSummary(requestID uint, summary RunSummary) error {
+	var request BatchRequest
+	request.SetSummary(summary)
+	return d.gorm.Model(&BatchRequest{}).Where("id = ?", requestID).Update("summary_json", request.SummaryJSON).Error
+}
+
+// BatchRequestQuery filters and paginates ListBatchRequests; empty filters match every request
+// and a non-positive Limit returns every match.
+type BatchRequestQuery struct {
+	Status string
+	Type   string
+	Offset int
+	Limit  int
+}
+
+// batchRequestsSQL matches a batch's own requests plus the re-evaluations that may have re-scored
+// its domains. Re-evaluations are recorded without a batch, so their stored parameters tell
+// whether the filter named this batch or no batch at all.
+const batchRequestsSQL = `(batch_id = ? OR (batch_id = 0 AND type = 'reevaluate' AND
+	CASE WHEN json_valid(params_json) THEN COALESCE(json_extract(params_json, '$.batch_id'), 0) END IN (0, ?)))`
+
+// ListBatchRequests returns a page of the evaluation requests for a batch, oldest first, and how
+// many match in total. Re-evaluations filtered to the batch or run across every batch are included.
+func (d *Database) ListBatchRequests(batchID uint, opts BatchRequestQuery) ([]BatchRequest, int64, error) {
+	base := d.gorm.Model(&BatchRequest{}).Where(batchRequestsSQL, batchID, batchID)
+	if status := strings.TrimSpace(opts.Status); status != "" {
+		base = base.Where("status = ?", strings.ToLower(status))
+	}
+	if kind := strings.TrimSpace(opts.Type); kind != "" {
+		base = base.Where("type = ?", strings.ToLower(kind))
+	}
+	var total int64
+	if err := base.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	query := base.Order("started_at ASC, id ASC").Offset(max(opts.Offset, 0))
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+	var rows []BatchRequest
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+// LatestBatchRequest returns the most recent evaluation request for a batch, or nil when the
+// batch has never been evaluated.
+func (d *Database) LatestBatchRequest(batchID uint) (*BatchRequest, error) {
+	var rows []BatchRequest
+	if err := d.gorm.Where("batch_id = ?", batchID).Order("id DESC").Limit(1).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &rows[0], nil
+}
+
 // UpdateBatchProcessingInfo refreshes processed counts and timestamp for a batch.
 func (d *Database) UpdateBatchProcessingInfo(batchID uint) error {
 	processed, err := d.CountBatchResults(batchID)
@@ -564,6 +1175,31 @@ func (d *Database) GetCSVBatch(batchID uint) (*CSVBatch, error) {
 	return &batch, nil
 }
 
+// FindUploadKey returns the upload recorded under key no earlier than since, or
+// gorm.ErrRecordNotFound.
+func (d *Database) FindUploadKey(key string, since time.Time) (*UploadKey, error) {
+	var row UploadKey
+	if err := d.gorm.Where("key = ? AND created_at >= ?", key, since).First(&row).Error; err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+// SaveUploadKey records the batch produced for key, along with the hash of the upload's body, and
+// prunes keys older than expiredBefore.
+func (d *Database) SaveUploadKey(key, bodyHash string, batchID uint, merged bool, expiredBefore time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.gorm.Where("created_at < ?", expiredBefore).Delete(&UploadKey{}).Error; err != nil {
+		return err
+	}
+	row := UploadKey{Key: key, BodyHash: bodyHash, BatchID: batchID, Merged: merged, CreatedAt: time.Now()}
+	return d.gorm.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"body_hash", "batch_id", "merged", "created_at"}),
+	}).Create(&row).Error
+}
+
 // GetBatchRequest fetches a batch request record by ID.
 func (d *Database) GetBatchRequest(requestID uint) (*BatchRequest, error) {
 	var request BatchRequest
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrEmptyMarkQuery rejects a substring search with no letters or digits, which would match every
+// mark.
+var ErrEmptyMarkQuery = errors.New("contains search needs at least one letter or digit")
+
+// MarkQuery filters and paginates mark listings. Query matches the start of the normalized mark
+// (or its space-free form) unless Contains requests a substring match, which cannot use the indexes.
+type MarkQuery struct {
+	Query    string
+	Contains bool
+	Offset   int
+	Limit    int
+}
+
+// ListMarks returns marks matching the query ordered by normalized mark.
+func (d *Database) ListMarks(opts MarkQuery) ([]Mark, int64, error) {
+	base := d.MarksGORM().Model(&Mark{})
+	normalized := strings.Join(strings.Fields(strings.ToLower(opts.Query)), " ")
+	noSpaces := alphaNumOnly(normalized)
+	switch {
+	case normalized == "":
+	case opts.Contains:
+		if noSpaces == "" {
+			return nil, 0, ErrEmptyMarkQuery
+		}
+		base = base.Where(`mark_normalized LIKE ? ESCAPE '\' OR mark_no_spaces LIKE ? ESCAPE '\'`,
+			"%"+EscapeLike(normalized)+"%", "%"+EscapeLike(noSpaces)+"%")
+	case noSpaces == "":
+		base = base.Where("mark_normalized >= ? AND mark_normalized < ?", normalized, prefixUpperBound(normalized))
+	default:
+		// Range comparisons keep both lookups on the mark_normalized/mark_no_spaces indexes,
+		// which LIKE cannot use under SQLite's default case-insensitive LIKE.
+		base = base.Where("(mark_normalized >= ? AND mark_normalized < ?) OR (mark_no_spaces >= ? AND mark_no_spaces < ?)",
+			normalized, prefixUpperBound(normalized), noSpaces, prefixUpperBound(noSpaces))
+	}
+
+	var total int64
+	if err := base.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query := base.Order("mark_normalized ASC, serial ASC").Offset(opts.Offset)
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+	var marks []Mark
+	if err := query.Find(&marks).Error; err != nil {
+		return nil, 0, err
+	}
+	return marks, total, nil
+}
+
+// GetMark fetches a mark by serial number.
+func (d *Database) GetMark(serial string) (*Mark, error) {
+	var mark Mark
+	if err := d.MarksGORM().Where("serial = ?", strings.TrimSpace(serial)).First(&mark).Error; err != nil {
+		return nil, err
+	}
+	return &mark, nil
+}
+
+// MarksByOwner returns up to limit marks whose owner equals owner, ignoring ASCII case, most
+// recently updated first. The owner column is not indexed, so this scans the marks table.
+func (d *Database) MarksByOwner(owner string, limit int) ([]Mark, error) {
+	owner = strings.TrimSpace(owner)
+	if owner == "" {
+		return nil, nil
+	}
+	query := d.MarksGORM().Where("owner = ? COLLATE NOCASE", owner).Order("updated_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	var marks []Mark
+	if err := query.Find(&marks).Error; err != nil {
+		return nil, err
+	}
+	return marks, nil
+}
+
+// prefixUpperBound returns the smallest string greater than every string with the given prefix.
+func prefixUpperBound(prefix string) string {
+	b := []byte(prefix)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < 0xff {
+			b[i]++
+			return string(b[:i+1])
+		}
+	}
+	return prefix + "\xff"
+}
+
+func alphaNumOnly(in string) string {
+	var b strings.Builder
+	for _, r := range in {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 	"time"
 )
@@ -14,10 +15,14 @@ type Mark struct {
 	MarkNormalized string `gorm:"size:256;index"`
 	MarkNoSpaces   string `gorm:"size:256;index"`
 	Owner          string `gorm:"size:256"`
+	OwnersJSON     string `gorm:"type:text"`
 	ClassesJSON    string `gorm:"type:text"`
 	IsFanciful     bool   `gorm:"index"`
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
+	// StatusCode is the USPTO case status code (e.g. "700" registered, "602" abandoned) captured
+	// at ingest; empty for marks ingested before it was recorded.
+	StatusCode string `gorm:"size:8"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
 }
 
 // PopularMark stores aggregated mark usage counts for popularity scoring.
@@ -50,6 +55,52 @@ func (m *Mark) Classes() []string {
 	return out
 }
 
+// MarkOwner is one owner entry of a mark's case file. Owner on Mark keeps the first owner's name.
+type MarkOwner struct {
+	Name        string `json:"name"`
+	Address1    string `json:"address_1,omitempty"`
+	Address2    string `json:"address_2,omitempty"`
+	City        string `json:"city,omitempty"`
+	State       string `json:"state,omitempty"`
+	Country     string `json:"country,omitempty"`
+	Postcode    string `json:"postcode,omitempty"`
+	Nationality string `json:"nationality,omitempty"`
+}
+
+// SetOwners persists the owner list as JSON.
+func (m *Mark) SetOwners(owners []MarkOwner) {
+	if owners == nil {
+		m.OwnersJSON = "[]"
+		return
+	}
+	payload, _ := json.Marshal(owners)
+	m.OwnersJSON = string(payload)
+}
+
+// Owners returns the unmarshalled owner entries.
+func (m *Mark) Owners() []MarkOwner {
+	if strings.TrimSpace(m.OwnersJSON) == "" {
+		return nil
+	}
+	var out []MarkOwner
+	if err := json.Unmarshal([]byte(m.OwnersJSON), &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+// OwnerCountry returns the primary owner's country, or "" when it is unknown.
+func (m *Mark) OwnerCountry() string {
+	owners := m.Owners()
+	if len(owners) == 0 {
+		return ""
+	}
+	if owners[0].Country != "" {
+		return owners[0].Country
+	}
+	return owners[0].Nationality
+}
+
 // Domain represents a domain under evaluation.
 type Domain struct {
 	ID               uint   `gorm:"primaryKey"`
@@ -87,7 +138,12 @@ type Evaluation struct {
 	TrademarkScore        int
 	TrademarkType         string `gorm:"size:32"`
 	MatchedTrademark      string `gorm:"size:255"`
+	MatchedOwner          string `gorm:"size:255"`
+	MatchedSerial         string `gorm:"size:32"`
+	MatchedRegistration   string `gorm:"size:32"`
+	TrademarkSource       string `gorm:"size:32"`
 	TrademarkConfidence   float64
+	TrademarkConflict     bool
 	ViceScore             int
 	ViceCategoriesJSON    string `gorm:"type:text"`
 	ViceConfidence        float64
@@ -97,7 +153,19 @@ type Evaluation struct {
 	CommercialOverride    bool
 	CommercialSource      string `gorm:"size:255"`
 	CommercialSimilarity  float64
-	CreatedAt             time.Time `gorm:"autoCreateTime"`
+	CommercialPrice       float64 `gorm:"index"`
+	// CommercialMatchJSON holds the CommercialMatch behind CommercialSource, which is its display
+	// form; it is empty when no comparable sale matched and for rows saved before it was stored.
+	CommercialMatchJSON string    `gorm:"type:text"`
+	ReasonsJSON         string    `gorm:"type:text"`
+	CloseMatchesJSON    string    `gorm:"type:text"`
+	CreatedAt           time.Time `gorm:"autoCreateTime"`
+	// UpdatedAt changes on every save, including re-evaluations that upsert an existing row.
+	UpdatedAt time.Time `gorm:"autoUpdateTime;index"`
+	// LookupMs and AIMs break ProcessingTimeMs down into the USPTO lookup and the AI call; they
+	// are only recorded when timing persistence is enabled and are nil otherwise.
+	LookupMs *int64
+	AIMs     *int64 `gorm:"column:ai_ms"`
 }
 
 // CSVBatch represents an uploaded CSV dataset.
@@ -126,6 +194,42 @@ type BatchRequest struct {
 	StartedAt  time.Time
 	FinishedAt *time.Time
 	CreatedAt  time.Time
+	// SummaryJSON holds the RunSummary recorded when the job finished.
+	SummaryJSON string `gorm:"type:text"`
+	// ParamsJSON holds the evaluate request that started the job, so it can be retried as sent.
+	ParamsJSON string `gorm:"type:text"`
+	// RetryOf links a retry to the request it re-runs.
+	RetryOf *uint `gorm:"index"`
+}
+
+// RunSummary aggregates the results of one evaluation job. Reused evaluations count towards
+// Recommendations and CommercialOverrides but not AvgProcessingMs, which covers fresh ones only.
+type RunSummary struct {
+	Evaluated           int            `json:"evaluated"`
+	Reused              int            `json:"reused"`
+	Skipped             int            `json:"skipped"`
+	Recommendations     map[string]int `json:"recommendations"`
+	CommercialOverrides int            `json:"commercial_overrides"`
+	AvgProcessingMs     float64        `json:"avg_processing_ms"`
+	DurationMs          int64          `json:"duration_ms"`
+}
+
+// SetSummary persists the run summary as JSON.
+func (r *BatchRequest) SetSummary(summary RunSummary) {
+	payload, _ := json.Marshal(summary)
+	r.SummaryJSON = string(payload)
+}
+
+// Summary returns the recorded run summary, or nil when none was saved.
+func (r *BatchRequest) Summary() *RunSummary {
+	if strings.TrimSpace(r.SummaryJSON) == "" {
+		return nil
+	}
+	var out RunSummary
+	if err := json.Unmarshal([]byte(r.SummaryJSON), &out); err != nil {
+		return nil
+	}
+	return &out
 }
 
 // DomainBatch links domains to CSV batches (one row per domain occurrence).
@@ -138,6 +242,28 @@ type DomainBatch struct {
 	CreatedAt        time.Time
 }
 
+// SkippedDomain records a batch row that evaluation skipped because it could not be scored, with
+// the reason. A row skipped again on a later run replaces its earlier record.
+type SkippedDomain struct {
+	ID        uint   `gorm:"primaryKey"`
+	BatchID   uint   `gorm:"uniqueIndex:idx_skipped_domains_batch_row"`
+	RowIndex  int    `gorm:"uniqueIndex:idx_skipped_domains_batch_row"`
+	Domain    string `gorm:"size:255"`
+	Reason    string `gorm:"size:255"`
+	CreatedAt time.Time
+}
+
+// UploadKey maps an upload idempotency key to the batch the original upload created or merged
+// into, so retried uploads replay that result instead of creating a duplicate batch. BodyHash
+// fingerprints the original file and batch fields so a key reused for another upload is caught.
+type UploadKey struct {
+	Key       string `gorm:"primaryKey;size:128"`
+	BodyHash  string `gorm:"size:64"`
+	BatchID   uint   `gorm:"index"`
+	Merged    bool
+	CreatedAt time.Time `gorm:"index"`
+}
+
 // JobState persists evaluation job metadata across restarts.
 type JobState struct {
 	JobID         string `gorm:"primaryKey;size:64"`
@@ -180,3 +306,85 @@ func (e *Evaluation) ViceCategories() []string {
 	}
 	return out
 }
+
+// CommercialMatch is the comparable sale that informed an evaluation's commercial signal.
+type CommercialMatch struct {
+	// Source is the kind of commercial evidence; "sale" for a historical sale.
+	Source     string  `json:"source"`
+	SLD        string  `json:"sld"`
+	Price      float64 `json:"price"`
+	Similarity float64 `json:"similarity"`
+}
+
+// Display renders the match for people, e.g. "sale $1200".
+func (m CommercialMatch) Display() string {
+	return fmt.Sprintf("%s $%.0f", m.Source, m.Price)
+}
+
+// SetCommercialMatch saves the commercial match as JSON and its display form as
+// CommercialSource; nil clears both.
+func (e *Evaluation) SetCommercialMatch(match *CommercialMatch) {
+	if match == nil {
+		e.CommercialMatchJSON, e.CommercialSource = "", ""
+		return
+	}
+	payload, _ := json.Marshal(match)
+	e.CommercialMatchJSON = string(payload)
+	e.CommercialSource = match.Display()
+}
+
+// CommercialMatch returns the decoded commercial match, or nil when none was stored.
+func (e *Evaluation) CommercialMatch() *CommercialMatch {
+	if strings.TrimSpace(e.CommercialMatchJSON) == "" {
+		return nil
+	}
+	var out CommercialMatch
+	if err := json.Unmarshal([]byte(e.CommercialMatchJSON), &out); err != nil {
+		return nil
+	}
+	return &out
+}
+
+// SetReasons saves the evaluation reasons (notes explaining adjustments to the scores) as JSON.
+func (e *Evaluation) SetReasons(reasons []string) {
+	if len(reasons) == 0 {
+		e.ReasonsJSON = ""
+		return
+	}
+	payload, _ := json.Marshal(reasons)
+	e.ReasonsJSON = string(payload)
+}
+
+// SetCloseMatches saves the near-miss trademarks considered for the domain as JSON.
+func (e *Evaluation) SetCloseMatches(matches []string) {
+	if len(matches) == 0 {
+		e.CloseMatchesJSON = ""
+		return
+	}
+	payload, _ := json.Marshal(matches)
+	e.CloseMatchesJSON = string(payload)
+}
+
+// CloseMatches returns the decoded close trademark matches.
+func (e *Evaluation) CloseMatches() []string {
+	if strings.TrimSpace(e.CloseMatchesJSON) == "" {
+		return nil
+	}
+	var out []string
+	if err := json.Unmarshal([]byte(e.CloseMatchesJSON), &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+// Reasons returns the decoded evaluation reasons.
+func (e *Evaluation) Reasons() []string {
+	if strings.TrimSpace(e.ReasonsJSON) == "" {
+		return nil
+	}
+	var out []string
+	if err := json.Unmarshal([]byte(e.ReasonsJSON), &out); err != nil {
+		return nil
+	}
+	return out
+}
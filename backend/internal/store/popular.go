@@ -21,7 +21,7 @@ func (d *Database) PopularMarks(limit int, minCount int) ([]PopularMark, error)
 	}
 
 	var results []PopularMark
-	query := d.gorm.Table("marks").
+	query := d.MarksGORM().Table("marks").
 		Select("LOWER(mark_no_spaces) AS normalized, MAX(mark) AS mark, COUNT(*) AS total").
 		Group("LOWER(mark_no_spaces)").
 		Having("COUNT(*) >= ?", minCount).
@@ -39,7 +39,10 @@ func (d *Database) ReplacePopularMarks(marks []PopularMark) error {
 	if d == nil {
 		return errors.New("database is nil")
 	}
-	return d.gorm.Transaction(func(tx *gorm.DB) error {
+	if d.MarksReadOnly() {
+		return ErrMarksReadOnly
+	}
+	return d.MarksGORM().Transaction(func(tx *gorm.DB) error {
 		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&PopularMark{}).Error; err != nil {
 			return err
 		}
@@ -67,7 +70,7 @@ func (d *Database) ListPopularMarks(limit int) ([]PopularMark, error) {
 	if d == nil {
 		return nil, errors.New("database is nil")
 	}
-	query := d.gorm.Model(&PopularMark{}).Order("total DESC")
+	query := d.MarksGORM().Model(&PopularMark{}).Order("total DESC")
 	if limit > 0 {
 		query = query.Limit(limit)
 	}
@@ -0,0 +1,153 @@
+package usp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"domain-risk-eval/backend/internal/commercial"
+)
+
+const (
+	// bulkTermsPerRequest bounds how many terms are OR-ed into one searchText query.
+	bulkTermsPerRequest = 20
+	// bulkMaxRows caps the rows requested for a bulk query.
+	bulkMaxRows = 500
+)
+
+// BulkLookup resolves several terms, serving cached results first and OR-ing the rest into
+// shared searchText queries. Results are keyed by the lowercased term and stored in the cache
+// exactly as LookupExact would store them. Terms a bulk query cannot vouch for are looked up
+// singly, and when the endpoint rejects a bulk query the client stops trying bulk requests and
+// falls back to single lookups.
+func (c *Client) BulkLookup(ctx context.Context, terms []string) (map[string]LookupResult, error) {
+	if c == nil {
+		return nil, errors.New("usp client is nil")
+	}
+
+	results := make(map[string]LookupResult, len(terms))
+	pending := make([]string, 0, len(terms))
+	for _, term := range terms {
+		key := strings.ToLower(strings.TrimSpace(term))
+		if key == "" {
+			continue
+		}
+		if _, ok := results[key]; ok {
+			continue
+		}
+		if entry, ok := c.cache.Load(key); ok {
+			cached := entry.(cacheEntry)
+			if time.Since(cached.at) < c.cacheTTL {
+				results[key] = cached.result
+				continue
+			}
+			c.cache.Delete(key)
+		}
+		results[key] = LookupResult{}
+		pending = append(pending, key)
+	}
+
+	for start := 0; start < len(pending); start += bulkTermsPerRequest {
+		end := start + bulkTermsPerRequest
+		if end > len(pending) {
+			end = len(pending)
+		}
+		chunk := pending[start:end]
+
+		var (
+			chunkResults map[string]LookupResult
+			err          error
+		)
+		if !c.bulkUnsupported.Load() && len(chunk) > 1 {
+			chunkResults, err = c.performBulkRequest(ctx, chunk)
+			var status *StatusError
+			if errors.As(err, &status) && status.Code >= http.StatusBadRequest && status.Code < http.StatusInternalServerError && status.Code != http.StatusTooManyRequests {
+				c.bulkUnsupported.Store(true)
+			}
+		}
+		if chunkResults == nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return results, ctxErr
+			}
+			chunkResults = make(map[string]LookupResult, len(chunk))
+		}
+		for _, term := range chunk {
+			if _, ok := chunkResults[term]; ok {
+				continue
+			}
+			result, lookupErr := c.LookupExact(ctx, term)
+			if lookupErr != nil {
+				return results, lookupErr
+			}
+			chunkResults[term] = result
+		}
+
+		now := time.Now()
+		for _, term := range chunk {
+			result := chunkResults[term]
+			results[term] = result
+			c.cache.Store(term, cacheEntry{at: now, result: result})
+		}
+	}
+	return results, nil
+}
+
+// performBulkRequest issues one OR-ed query and distributes the marks back to their terms. A
+// mark belongs to every term it is related to (see relatedMark) and is split into exact and similar
+// matches like performRequest does. When the query fills every requested row, a term without an
+// exact match may have been crowded out by a broader one, so it is left out of the results for
+// the caller to look up on its own rather than reported as having no live mark.
+func (c *Client) performBulkRequest(ctx context.Context, terms []string) (map[string]LookupResult, error) {
+	clauses := make([]string, len(terms))
+	for i, term := range terms {
+		clauses[i] = markClause(term)
+	}
+	rows := c.rows * len(terms)
+	if rows > bulkMaxRows {
+		rows = bulkMaxRows
+	}
+	payload, err := c.search(ctx, fmt.Sprintf("mark:(%s) AND status:(\"LIVE\")", strings.Join(clauses, " OR ")), rows)
+	if err != nil {
+		return nil, err
+	}
+
+	cleanTerms := make(map[string]string, len(terms))
+	results := make(map[string]LookupResult, len(terms))
+	for _, term := range terms {
+		cleanTerms[term] = cleanKey(term)
+		results[term] = LookupResult{Term: term, Checked: true}
+	}
+	for _, item := range payload.Results {
+		record, ok := item.toMark()
+		if !ok {
+			continue
+		}
+		cleanMark := cleanKey(record.Mark)
+		for _, term := range terms {
+			cleanTerm := cleanTerms[term]
+			if !relatedMark(cleanTerm, cleanMark) {
+				continue
+			}
+			result := results[term]
+			scored := record
+			scored.Similarity = commercial.LevenshteinSimilarity(cleanTerm, cleanMark)
+			if scored.Similarity == 1 {
+				result.ExactMatches = append(result.ExactMatches, scored)
+			} else if len(result.Similar) < c.rows {
+				result.Similar = append(result.Similar, scored)
+			}
+			results[term] = result
+		}
+	}
+	if len(payload.Results) >= rows {
+		for term, result := range results {
+			if len(result.ExactMatches) == 0 {
+				delete(results, term)
+			}
+		}
+	}
+	return results, nil
+}
@@ -5,11 +5,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"domain-risk-eval/backend/internal/commercial"
+	"domain-risk-eval/backend/internal/util"
 )
 
 // Config drives USPTO client behaviour.
@@ -19,6 +24,8 @@ type Config struct {
 	Timeout  time.Duration
 	CacheTTL time.Duration
 	Rows     int
+	// MaxIdleConnsPerHost sizes the connection pool; zero uses util.DefaultMaxIdleConnsPerHost.
+	MaxIdleConnsPerHost int
 }
 
 // Mark captures the subset of USPTO data we need for scoring.
@@ -32,6 +39,9 @@ type Mark struct {
 	StatusCategory     string
 	Classes            []string
 	IsLive             bool
+	// Similarity scores the mark against the looked-up term (0-1, normalized edit distance over
+	// the cleaned strings); exact matches score 1.
+	Similarity float64
 }
 
 // LookupResult returns exact and similar matches for a query.
@@ -39,7 +49,9 @@ type LookupResult struct {
 	Term         string
 	ExactMatches []Mark
 	Similar      []Mark
-	Checked      bool
+	// Checked reports that USPTO answered the query; a checked result without matches means no
+	// live mark was found, whereas failed lookups are never checked.
+	Checked bool
 }
 
 // Client performs USPTO API lookups with basic caching and rate limiting.
@@ -50,6 +62,8 @@ type Client struct {
 	rows       int
 	cacheTTL   time.Duration
 	cache      sync.Map // map[string]cacheEntry
+	// bulkUnsupported is set once the endpoint rejects an OR-ed query.
+	bulkUnsupported atomic.Bool
 }
 
 type cacheEntry struct {
@@ -87,7 +101,7 @@ func NewClient(cfg Config) (*Client, error) {
 	}
 
 	return &Client{
-		httpClient: &http.Client{Timeout: timeout},
+		httpClient: util.NewHTTPClient(timeout, cfg.MaxIdleConnsPerHost),
 		baseURL:    baseURL,
 		apiKey:     cfg.APIKey,
 		rows:       rows,
@@ -124,9 +138,52 @@ func (c *Client) LookupExact(ctx context.Context, term string) (LookupResult, er
 }
 
 func (c *Client) performRequest(ctx context.Context, term string) (LookupResult, error) {
+	payload, err := c.search(ctx, fmt.Sprintf("mark:(%s) AND status:(\"LIVE\")", markClause(term)), c.rows)
+	if err != nil {
+		return LookupResult{}, err
+	}
+
+	cleanTerm := cleanKey(term)
+	var exact []Mark
+	var similar []Mark
+
+	for _, item := range payload.Results {
+		record, ok := item.toMark()
+		if !ok {
+			continue
+		}
+		record.Similarity = commercial.LevenshteinSimilarity(cleanTerm, cleanKey(record.Mark))
+		if record.Similarity == 1 {
+			exact = append(exact, record)
+		} else {
+			similar = append(similar, record)
+		}
+	}
+
+	return LookupResult{
+		Term:         term,
+		ExactMatches: exact,
+		Similar:      similar,
+		Checked:      true,
+	}, nil
+}
+
+// Retry schedule for search.
+const (
+	// rateLimitBackoff is how long a 429 is waited out before the single retry it gets.
+	rateLimitBackoff = 5 * time.Second
+	// decodeAttempts bounds how often a query is sent while its 200 responses cannot be decoded,
+	// typically because the body was truncated in transit. Waits start at decodeBackoff and double.
+	decodeAttempts = 3
+	decodeBackoff  = 500 * time.Millisecond
+)
+
+// search runs one searchText query, retrying once after a 429 and up to decodeAttempts times
+// while the body cannot be decoded. A well-formed response without results is not an error.
+func (c *Client) search(ctx context.Context, searchText string, rows int) (searchResponse, error) {
 	params := url.Values{}
-	params.Set("searchText", fmt.Sprintf("mark:(\"%s\") AND status:(\"LIVE\")", term))
-	params.Set("rows", fmt.Sprintf("%d", c.rows))
+	params.Set("searchText", searchText)
+	params.Set("rows", fmt.Sprintf("%d", rows))
 	params.Set("start", "0")
 
 	endpoint := c.baseURL
@@ -136,9 +193,46 @@ func (c *Client) performRequest(ctx context.Context, term string) (LookupResult,
 		endpoint = endpoint + "?" + params.Encode()
 	}
 
+	rateLimited := false
+	decodeFailures := 0
+	delay := decodeBackoff
+	for {
+		payload, err := c.fetch(ctx, endpoint)
+		if err == nil {
+			return payload, nil
+		}
+
+		var wait time.Duration
+		var status *StatusError
+		var decodeErr *DecodeError
+		switch {
+		case errors.As(err, &status) && status.Code == http.StatusTooManyRequests && !rateLimited:
+			rateLimited = true
+			wait = rateLimitBackoff
+		case errors.As(err, &decodeErr):
+			decodeFailures++
+			if decodeFailures >= decodeAttempts {
+				return searchResponse{}, err
+			}
+			wait = delay
+			delay *= 2
+		default:
+			return searchResponse{}, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return searchResponse{}, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// fetch sends one search request and decodes its body.
+func (c *Client) fetch(ctx context.Context, endpoint string) (searchResponse, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
-		return LookupResult{}, err
+		return searchResponse{}, err
 	}
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
@@ -146,78 +240,47 @@ func (c *Client) performRequest(ctx context.Context, term string) (LookupResult,
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return LookupResult{}, err
+		return searchResponse{}, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusTooManyRequests {
-		// back off for 5 seconds and retry once
-		select {
-		case <-ctx.Done():
-			return LookupResult{}, ctx.Err()
-		case <-time.After(5 * time.Second):
-		}
-		// second attempt
-		resp.Body.Close()
-		retryReq, retryErr := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
-		if retryErr != nil {
-			return LookupResult{}, retryErr
-		}
-		retryReq.Header = req.Header.Clone()
-		resp, err = c.httpClient.Do(retryReq)
-		if err != nil {
-			return LookupResult{}, err
-		}
-		defer resp.Body.Close()
-	}
-
 	if resp.StatusCode != http.StatusOK {
-		return LookupResult{}, fmt.Errorf("usp to api status %d", resp.StatusCode)
+		return searchResponse{}, &StatusError{Code: resp.StatusCode}
 	}
 
+	// Read the whole body first so a connection cut mid-body is reported as a decode failure,
+	// like the truncated JSON it leaves behind.
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return searchResponse{}, &DecodeError{Err: err}
+	}
 	var payload searchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return LookupResult{}, fmt.Errorf("decode usp to response: %w", err)
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return searchResponse{}, &DecodeError{Err: err}
 	}
+	return payload, nil
+}
 
-	cleanTerm := cleanKey(term)
-	var exact []Mark
-	var similar []Mark
+// DecodeError reports a 200 response whose body could not be read or parsed as search results.
+type DecodeError struct {
+	Err error
+}
 
-	for _, item := range payload.Results {
-		mark := strings.TrimSpace(item.MarkIdentification)
-		if mark == "" {
-			continue
-		}
-		record := Mark{
-			SerialNumber:       strings.TrimSpace(item.SerialNumber),
-			RegistrationNumber: strings.TrimSpace(item.RegistrationNumber),
-			Mark:               mark,
-			Owner:              strings.TrimSpace(item.OwnerName),
-			Status:             strings.TrimSpace(item.MarkCurrentStatus),
-			StatusCode:         strings.TrimSpace(item.MarkCurrentStatusCode),
-			StatusCategory:     strings.TrimSpace(item.MarkCurrentStatusCategory),
-			Classes:            collapseStrings(item.InternationalClasses),
-		}
-		statusUpper := strings.ToUpper(record.Status)
-		categoryUpper := strings.ToUpper(record.StatusCategory)
-		if strings.Contains(statusUpper, "LIVE") || strings.Contains(categoryUpper, "LIVE") {
-			record.IsLive = true
-		}
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decode usp to response: %v", e.Err)
+}
 
-		if cleanKey(mark) == cleanTerm {
-			exact = append(exact, record)
-		} else {
-			similar = append(similar, record)
-		}
-	}
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
 
-	return LookupResult{
-		Term:         term,
-		ExactMatches: exact,
-		Similar:      similar,
-		Checked:      true,
-	}, nil
+// StatusError reports a non-200 response from the USPTO API.
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("usp to api status %d", e.Code)
 }
 
 type searchResponse struct {
@@ -235,6 +298,29 @@ type searchResult struct {
 	InternationalClasses      interface{} `json:"internationalClasses"`
 }
 
+func (item searchResult) toMark() (Mark, bool) {
+	mark := strings.TrimSpace(item.MarkIdentification)
+	if mark == "" {
+		return Mark{}, false
+	}
+	record := Mark{
+		SerialNumber:       strings.TrimSpace(item.SerialNumber),
+		RegistrationNumber: strings.TrimSpace(item.RegistrationNumber),
+		Mark:               mark,
+		Owner:              strings.TrimSpace(item.OwnerName),
+		Status:             strings.TrimSpace(item.MarkCurrentStatus),
+		StatusCode:         strings.TrimSpace(item.MarkCurrentStatusCode),
+		StatusCategory:     strings.TrimSpace(item.MarkCurrentStatusCategory),
+		Classes:            collapseStrings(item.InternationalClasses),
+	}
+	statusUpper := strings.ToUpper(record.Status)
+	categoryUpper := strings.ToUpper(record.StatusCategory)
+	if strings.Contains(statusUpper, "LIVE") || strings.Contains(categoryUpper, "LIVE") {
+		record.IsLive = true
+	}
+	return record, true
+}
+
 func collapseStrings(raw interface{}) []string {
 	switch v := raw.(type) {
 	case []string:
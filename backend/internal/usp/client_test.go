@@ -0,0 +1,141 @@
+package usp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLookupExactRetriesTruncatedResponses(t *testing.T) {
+	tests := []struct {
+		name      string
+		bodies    []string
+		wantErr   bool
+		wantCalls int32
+		wantExact int
+	}{
+		{"empty results are checked", []string{`{"results": []}`}, false, 1, 0},
+		{"truncated then valid", []string{`{"results": [{"serialNumber": "1"`, `{"results": [{"serialNumber": "1", "markIdentification": "ZORBLAX"}]}`}, false, 2, 1},
+		{"always truncated", []string{`{"resu`}, true, decodeAttempts, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(calls.Add(1)) - 1
+				w.Write([]byte(tc.bodies[min(n, len(tc.bodies)-1)]))
+			}))
+			defer srv.Close()
+
+			client, err := NewClient(Config{APIKey: "test", BaseURL: srv.URL})
+			if err != nil {
+				t.Fatalf("new client: %v", err)
+			}
+			result, err := client.LookupExact(context.Background(), "zorblax")
+			if got := calls.Load(); got != tc.wantCalls {
+				t.Fatalf("expected %d requests got %d", tc.wantCalls, got)
+			}
+			if tc.wantErr {
+				var decodeErr *DecodeError
+				if !errors.As(err, &decodeErr) {
+					t.Fatalf("expected decode error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("lookup: %v", err)
+			}
+			if !result.Checked || len(result.ExactMatches) != tc.wantExact {
+				t.Fatalf("unexpected result %+v", result)
+			}
+		})
+	}
+}
+
+func TestBulkLookupRechecksSaturatedChunks(t *testing.T) {
+	var bulkCalls, singleCalls atomic.Int32
+	var singleTerms []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("searchText")
+		if strings.Contains(query, "apple") {
+			bulkCalls.Add(1)
+			// "apple" fills all 4 rows (2 per term), crowding out "zorblax".
+			w.Write([]byte(`{"results": [{"markIdentification": "APPLE"}, {"markIdentification": "APPLE MUSIC"}, {"markIdentification": "APPLE PAY"}, {"markIdentification": "APPLEBEES"}]}`))
+			return
+		}
+		singleCalls.Add(1)
+		singleTerms = append(singleTerms, query)
+		w.Write([]byte(`{"results": [{"markIdentification": "ZORBLAX"}, {"markIdentification": "ZORBLAX LABS"}]}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(Config{APIKey: "test", BaseURL: srv.URL, Rows: 2})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	results, err := client.BulkLookup(context.Background(), []string{"apple", "zorblax"})
+	if err != nil {
+		t.Fatalf("bulk lookup: %v", err)
+	}
+	if bulkCalls.Load() != 1 || singleCalls.Load() != 1 || !strings.Contains(singleTerms[0], "zorblax") {
+		t.Fatalf("expected one bulk query and a single re-check of zorblax, got %d bulk and %v", bulkCalls.Load(), singleTerms)
+	}
+	if apple := results["apple"]; !apple.Checked || len(apple.ExactMatches) != 1 || len(apple.Similar) != 2 {
+		t.Fatalf("unexpected apple result %+v", apple)
+	}
+	if zorblax := results["zorblax"]; !zorblax.Checked || len(zorblax.ExactMatches) != 1 || len(zorblax.Similar) != 1 {
+		t.Fatalf("expected zorblax's own lookup, got %+v", zorblax)
+	}
+}
+
+func TestLookupExactFindsTyposquattedMarks(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("searchText")
+		w.Write([]byte(`{"results": [{"markIdentification": "PAYPAL", "markCurrentStatusCategory": "LIVE"}]}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(Config{APIKey: "test", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	result, err := client.LookupExact(context.Background(), "paypa1")
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	if !strings.Contains(query, "paypa1~2") {
+		t.Fatalf("expected a fuzzy clause in %q", query)
+	}
+	if len(result.Similar) != 1 || result.Similar[0].Mark != "PAYPAL" || result.Similar[0].Similarity < 0.83 || result.Similar[0].Similarity > 0.84 {
+		t.Fatalf("expected PAYPAL as a similar mark, got %+v", result.Similar)
+	}
+}
+
+func TestRelatedMark(t *testing.T) {
+	tests := []struct {
+		term, mark string
+		want       bool
+	}{
+		{"apple", "applemusic", true},
+		{"paypa1", "paypal", true},
+		{"paypa1", "paypl", true},
+		{"paypa1", "zorblax", false},
+		{"", "paypal", false},
+	}
+	for _, tc := range tests {
+		if got := relatedMark(tc.term, tc.mark); got != tc.want {
+			t.Fatalf("relatedMark(%q, %q) = %v, want %v", tc.term, tc.mark, got, tc.want)
+		}
+	}
+	if got := markClause("app"); got != `"app"` {
+		t.Fatalf("expected short terms to stay a phrase, got %s", got)
+	}
+	if got := markClause("pay-pal"); got != `"pay-pal"` {
+		t.Fatalf("expected punctuated terms to stay a phrase, got %s", got)
+	}
+}
@@ -0,0 +1,45 @@
+package usp
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"domain-risk-eval/backend/internal/commercial"
+)
+
+// fuzzyEdits is the edit distance of the fuzzy clause searched next to each quoted term, so
+// typosquats such as "paypa1" still return the mark they imitate. Terms shorter than
+// fuzzyMinLength are searched as a phrase only, since two edits would match most short marks.
+const (
+	fuzzyEdits     = 2
+	fuzzyMinLength = 5
+)
+
+// markClause returns the searchText clause for term: the quoted phrase, OR-ed with a fuzzy query
+// when term is a plain alphanumeric token long enough to fuzz.
+func markClause(term string) string {
+	if len(term) < fuzzyMinLength {
+		return fmt.Sprintf("\"%s\"", term)
+	}
+	for _, r := range term {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return fmt.Sprintf("\"%s\"", term)
+		}
+	}
+	return fmt.Sprintf("\"%s\" OR %s~%d", term, term, fuzzyEdits)
+}
+
+// relatedMark reports whether a cleaned mark returned by a query answers the cleaned term: it
+// contains the term or lies within fuzzyEdits edits of it.
+func relatedMark(term, mark string) bool {
+	if term == "" {
+		return false
+	}
+	if strings.Contains(mark, term) {
+		return true
+	}
+	longest := max(len([]rune(term)), len([]rune(mark)))
+	edits := math.Round((1 - commercial.LevenshteinSimilarity(term, mark)) * float64(longest))
+	return edits <= fuzzyEdits
+}
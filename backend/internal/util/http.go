@@ -0,0 +1,25 @@
+package util
+
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultMaxIdleConnsPerHost keeps enough warm connections for the evaluation worker pool;
+// net/http defaults to 2, which forces a new TLS handshake for most concurrent calls.
+const DefaultMaxIdleConnsPerHost = 16
+
+// NewHTTPClient returns a client with the given timeout whose transport is cloned from
+// http.DefaultTransport and keeps up to maxIdlePerHost idle connections per host. Callers share
+// the returned client so repeated requests reuse pooled connections.
+func NewHTTPClient(timeout time.Duration, maxIdlePerHost int) *http.Client {
+	if maxIdlePerHost <= 0 {
+		maxIdlePerHost = DefaultMaxIdleConnsPerHost
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = maxIdlePerHost
+	if transport.MaxIdleConns < maxIdlePerHost {
+		transport.MaxIdleConns = maxIdlePerHost
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
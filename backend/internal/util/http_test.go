@@ -0,0 +1,32 @@
+package util
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPClientPoolsConnections(t *testing.T) {
+	tests := []struct {
+		maxIdlePerHost int
+		want           int
+	}{
+		{0, DefaultMaxIdleConnsPerHost},
+		{4, 4},
+		{500, 500},
+	}
+	for _, tc := range tests {
+		client := NewHTTPClient(5*time.Second, tc.maxIdlePerHost)
+		transport, ok := client.Transport.(*http.Transport)
+		if !ok {
+			t.Fatalf("expected an *http.Transport, got %T", client.Transport)
+		}
+		if transport.MaxIdleConnsPerHost != tc.want || transport.MaxIdleConns < tc.want {
+			t.Fatalf("%d: expected %d idle connections per host, got %d (total %d)",
+				tc.maxIdlePerHost, tc.want, transport.MaxIdleConnsPerHost, transport.MaxIdleConns)
+		}
+		if client.Timeout != 5*time.Second {
+			t.Fatalf("expected timeout 5s, got %s", client.Timeout)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"domain-risk-eval/backend/internal/match"
 	"domain-risk-eval/backend/internal/store"
 )
 
@@ -20,15 +21,30 @@ type FancifulDecider interface {
 	Decide(markNormalized string, classes []string, owner string) bool
 }
 
+// MarkWriter persists ingested marks; *store.Database implements it.
+type MarkWriter interface {
+	UpsertMark(mark *store.Mark) error
+}
+
 // IngestOptions configures the XML ingestion routine.
 type IngestOptions struct {
 	Path     string
-	DB       *store.Database
+	DB       MarkWriter
 	Decider  FancifulDecider
 	Progress func(count int)
 	Context  context.Context
+	// FancifulMinLength and FancifulMinClasses tune the fallback heuristic used when Decider is
+	// nil; zero keeps the defaults of 6 characters and 2 classes.
+	FancifulMinLength  int
+	FancifulMinClasses int
 }
 
+// Default fallback fanciful thresholds, matching scoring.DefaultFancifulThresholds.
+const (
+	defaultFancifulMinLength  = 6
+	defaultFancifulMinClasses = 2
+)
+
 // Ingest parses the USPTO XML (optionally zipped) and persists marks into the database.
 func Ingest(opts IngestOptions) (int, error) {
 	if opts.DB == nil {
@@ -50,6 +66,13 @@ func Ingest(opts IngestOptions) (int, error) {
 
 	decoder := xml.NewDecoder(bufio.NewReader(r))
 	count := 0
+	minLength, minClasses := opts.FancifulMinLength, opts.FancifulMinClasses
+	if minLength <= 0 {
+		minLength = defaultFancifulMinLength
+	}
+	if minClasses <= 0 {
+		minClasses = defaultFancifulMinClasses
+	}
 
 	for {
 		select {
@@ -84,7 +107,7 @@ func Ingest(opts IngestOptions) (int, error) {
 			continue
 		}
 
-		markRecord.IsFanciful = decideFanciful(opts.Decider, markRecord.MarkNormalized, markRecord.Classes(), markRecord.Owner)
+		markRecord.IsFanciful = decideFanciful(opts.Decider, markRecord.MarkNormalized, markRecord.Classes(), markRecord.Owner, minLength, minClasses)
 		if err := opts.DB.UpsertMark(markRecord); err != nil {
 			return count, fmt.Errorf("upsert mark: %w", err)
 		}
@@ -95,11 +118,11 @@ func Ingest(opts IngestOptions) (int, error) {
 	}
 }
 
-func decideFanciful(decider FancifulDecider, markNormalized string, classes []string, owner string) bool {
+func decideFanciful(decider FancifulDecider, markNormalized string, classes []string, owner string, minLength, minClasses int) bool {
 	if decider != nil {
 		return decider.Decide(markNormalized, classes, owner)
 	}
-	if len(markNormalized) >= 6 && len(classes) >= 2 {
+	if len(markNormalized) >= minLength && len(classes) >= minClasses {
 		return true
 	}
 	return false
@@ -158,6 +181,7 @@ type caseFile struct {
 
 type caseFileHeader struct {
 	MarkIdentification string `xml:"mark-identification"`
+	StatusCode         string `xml:"status-code"`
 }
 
 type caseFileOwners struct {
@@ -165,7 +189,42 @@ type caseFileOwners struct {
 }
 
 type caseFileOwner struct {
-	PartyName string `xml:"party-name"`
+	PartyName   string               `xml:"party-name"`
+	Address1    string               `xml:"address-1"`
+	Address2    string               `xml:"address-2"`
+	City        string               `xml:"city"`
+	State       string               `xml:"state"`
+	Country     string               `xml:"country"`
+	Postcode    string               `xml:"postcode"`
+	Nationality caseOwnerNationality `xml:"nationality"`
+}
+
+// caseOwnerNationality holds either a country code or, for US owners, a state code.
+type caseOwnerNationality struct {
+	Country string `xml:"country"`
+	State   string `xml:"state"`
+}
+
+// toOwner flattens the XML entry; US owners carry only a state, so their nationality is "US".
+func (o caseFileOwner) toOwner() store.MarkOwner {
+	nationality := cleanString(o.Nationality.Country)
+	if nationality == "" && cleanString(o.Nationality.State) != "" {
+		nationality = "US"
+	}
+	country := cleanString(o.Country)
+	if country == "" && cleanString(o.State) != "" {
+		country = "US"
+	}
+	return store.MarkOwner{
+		Name:        cleanString(o.PartyName),
+		Address1:    cleanString(o.Address1),
+		Address2:    cleanString(o.Address2),
+		City:        cleanString(o.City),
+		State:       cleanString(o.State),
+		Country:     country,
+		Postcode:    cleanString(o.Postcode),
+		Nationality: nationality,
+	}
 }
 
 type caseClassifications struct {
@@ -183,8 +242,16 @@ func (cf caseFile) toMark() *store.Mark {
 		return &store.Mark{}
 	}
 	owner := ""
-	if len(cf.Owners.Owners) > 0 {
-		owner = cleanString(cf.Owners.Owners[0].PartyName)
+	owners := make([]store.MarkOwner, 0, len(cf.Owners.Owners))
+	for _, entry := range cf.Owners.Owners {
+		parsed := entry.toOwner()
+		if parsed.Name == "" {
+			continue
+		}
+		owners = append(owners, parsed)
+	}
+	if len(owners) > 0 {
+		owner = owners[0].Name
 	}
 
 	var classes []string
@@ -197,7 +264,7 @@ func (cf caseFile) toMark() *store.Mark {
 		}
 	}
 
-	normalized := strings.ToLower(mark)
+	normalized := match.Fold(mark)
 	normalized = strings.Join(strings.Fields(normalized), " ")
 	noSpaces := removeNonAlphaNum(normalized)
 
@@ -208,8 +275,10 @@ func (cf caseFile) toMark() *store.Mark {
 		MarkNormalized: normalized,
 		MarkNoSpaces:   noSpaces,
 		Owner:          owner,
+		StatusCode:     strings.TrimSpace(cf.CaseFileHeader.StatusCode),
 	}
 	m.SetClasses(classes)
+	m.SetOwners(owners)
 	return m
 }
 
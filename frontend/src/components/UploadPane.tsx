@@ -105,6 +105,7 @@ export default function UploadPane({ onProcess, onEvaluate, busy }: UploadPanePr
         <dl className="grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-3 gap-4 mt-6 text-sm">
           <Stat label="Batch" value={lastResponse.batch_name} helper={`Owner: ${lastResponse.owner}`} />
           <Stat label="Rows" value={lastResponse.row_count.toLocaleString()} helper={`Unique: ${lastResponse.unique_domains.toLocaleString()}`} />
+          <Stat label="New Domains" value={lastResponse.new_domains.toLocaleString()} helper={`Seen in earlier uploads: ${lastResponse.known_domains.toLocaleString()}`} />
           <Stat label="Duplicates" value={lastResponse.duplicate_rows.toLocaleString()} helper={`Existing: ${lastResponse.existing_domains.toLocaleString()}`} />
           <Stat label="Already Evaluated" value={lastResponse.processed_domains.toLocaleString()} helper="Will be reused" />
           <Stat label="Marks In DB" value={lastResponse.marks_count.toLocaleString()} />
@@ -8,6 +8,8 @@ export interface UploadResponse {
   duplicate_rows: number;
   processed_domains: number;
   marks_count: number;
+  new_domains: number;
+  known_domains: number;
 }
 
 export interface EvaluationDTO {
@@ -23,6 +25,7 @@ export interface EvaluationDTO {
   overall_recommendation: string;
   confidence: number;
   created_at: string;
+  updated_at: string;
   explanation: string;
   commercial_override: boolean;
   commercial_source: string;
@@ -32,6 +35,9 @@ export interface EvaluationDTO {
 export interface EvaluateResponse {
   items: EvaluationDTO[];
   total: number;
+  page: number;
+  page_size: number;
+  has_next: boolean;
 }
 
 export interface StartEvaluationResponse {
@@ -94,11 +100,15 @@ export interface BatchDTO {
   processed_domains: number;
   created_at: string;
   last_evaluated_at?: string | null;
+  last_run?: BatchRequestDTO;
 }
 
 export interface BatchesResponse {
   items: BatchDTO[];
   total: number;
+  page: number;
+  page_size: number;
+  has_next: boolean;
 }
 
 export interface BatchRequestDTO {
@@ -109,6 +119,18 @@ export interface BatchRequestDTO {
   job_id: string;
   started_at: string;
   finished_at?: string | null;
+  summary?: RunSummary;
+  retry_of?: number;
+}
+
+export interface RunSummary {
+  evaluated: number;
+  reused: number;
+  skipped: number;
+  recommendations: Record<string, number>;
+  commercial_overrides: number;
+  avg_processing_ms: number;
+  duration_ms: number;
 }
 
 export interface EvaluationStatusResponse {